@@ -366,17 +366,23 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, requirement
 	if err != nil {
 		return nil, "", err
 	}
+	// Fetch the glue table first so that its version is never newer than the
+	// metadata loaded below, letting glue reject the update if either is stale
+	glueTable, err := c.getTable(ctx, database, tableName)
+	if err != nil {
+		return nil, "", err
+	}
 	current, err := c.LoadTable(ctx, tbl.Identifier(), nil)
-	if err != nil && !errors.Is(err, catalog.ErrNoSuchTable) {
+	if err != nil {
 		return nil, "", err
 	}
 
 	// Create a staging table with the updates applied
-	staged, err := internal.UpdateAndStageTable(ctx, tbl, tbl.Identifier(), requirements, updates, c)
+	staged, err := internal.UpdateAndStageTable(ctx, current, tbl.Identifier(), requirements, updates, c)
 	if err != nil {
 		return nil, "", err
 	}
-	if current != nil && staged.Metadata().Equals(current.Metadata()) {
+	if staged.Metadata().Equals(current.Metadata()) {
 		return current.Metadata(), current.MetadataLocation(), nil
 	}
 	if err := internal.WriteMetadata(ctx, staged.Metadata(), staged.MetadataLocation(), staged.Properties()); err != nil {
@@ -392,8 +398,15 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, requirement
 		CatalogId:    c.catalogId,
 		DatabaseName: aws.String(database),
 		TableInput:   tableInput,
+		VersionId:    glueTable.VersionId,
 	})
 	if err != nil {
+		var conflictErr *types.ConcurrentModificationException
+		if errors.As(err, &conflictErr) {
+			return nil, "", fmt.Errorf("%w: table %s.%s has been updated by another process: %w",
+				table.ErrCommitFailed, database, tableName, err)
+		}
+
 		return nil, "", err
 	}
 
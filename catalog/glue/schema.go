@@ -77,6 +77,8 @@ func icebergTypeToGlueType(typ iceberg.Type) string {
 		return "timestamp"
 	case iceberg.TimestampTzType:
 		return "timestamp"
+	case iceberg.TimestampNsType, iceberg.TimestampTzNsType:
+		return "timestamp"
 	case iceberg.StringType:
 		return "string"
 	case iceberg.UUIDType:
@@ -339,7 +339,7 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, reqs []tabl
 		}
 
 		if currLoc := hTable.Parameters["metadata_location"]; currLoc != "" && currLoc != current.MetadataLocation() {
-			return fmt.Errorf("table has been updated by another process: expected %s, found %s", current.MetadataLocation(), currLoc)
+			return fmt.Errorf("%w: table has been updated by another process: expected %s, found %s", table.ErrCommitFailed, current.MetadataLocation(), currLoc)
 		}
 
 		hTable.Parameters["metadata_location"] = staged.MetadataLocation()
@@ -80,11 +80,22 @@ var (
 	ErrAuthorizationExpired = fmt.Errorf("%w: authorization expired", ErrRESTError)
 	ErrServiceUnavailable   = fmt.Errorf("%w: service unavailable", ErrRESTError)
 	ErrServerError          = fmt.Errorf("%w: server error", ErrRESTError)
-	ErrCommitFailed         = fmt.Errorf("%w: commit failed, refresh and try again", ErrRESTError)
-	ErrCommitStateUnknown   = fmt.Errorf("%w: commit failed due to unknown reason", ErrRESTError)
+	ErrCommitFailed         = fmt.Errorf("%w: %w", ErrRESTError, table.ErrCommitFailed)
+	ErrCommitStateUnknown   = fmt.Errorf("%w: %w", ErrRESTError, table.ErrCommitStateUnknown)
 	ErrOAuthError           = fmt.Errorf("%w: oauth error", ErrRESTError)
 )
 
+// commitErrors are the errors of the responses to table commits, which may
+// have been applied when the server fails to respond.
+var commitErrors = map[int]error{
+	http.StatusNotFound:            catalog.ErrNoSuchTable,
+	http.StatusConflict:            ErrCommitFailed,
+	http.StatusInternalServerError: ErrCommitStateUnknown,
+	http.StatusBadGateway:          ErrCommitStateUnknown,
+	http.StatusServiceUnavailable:  ErrCommitStateUnknown,
+	http.StatusGatewayTimeout:      ErrCommitStateUnknown,
+}
+
 func init() {
 	reg := catalog.RegistrarFunc(func(ctx context.Context, name string, p iceberg.Properties) (catalog.Catalog, error) {
 		return newCatalogFromProps(ctx, name, p.Get("uri", ""), p)
@@ -133,10 +144,11 @@ func (t *commitTableResponse) UnmarshalJSON(b []byte) (err error) {
 }
 
 type loadTableResponse struct {
-	MetadataLoc string             `json:"metadata-location"`
-	RawMetadata json.RawMessage    `json:"metadata"`
-	Config      iceberg.Properties `json:"config"`
-	Metadata    table.Metadata     `json:"-"`
+	MetadataLoc        string              `json:"metadata-location"`
+	RawMetadata        json.RawMessage     `json:"metadata"`
+	Config             iceberg.Properties  `json:"config"`
+	StorageCredentials []storageCredential `json:"storage-credentials"`
+	Metadata           table.Metadata      `json:"-"`
 }
 
 func (t *loadTableResponse) UnmarshalJSON(b []byte) (err error) {
@@ -150,6 +162,34 @@ func (t *loadTableResponse) UnmarshalJSON(b []byte) (err error) {
 	return
 }
 
+// storageCredential is a set of vended credentials for the files whose
+// locations start with the prefix.
+type storageCredential struct {
+	Prefix string             `json:"prefix"`
+	Config iceberg.Properties `json:"config"`
+}
+
+type loadCredentialsResponse struct {
+	StorageCredentials []storageCredential `json:"storage-credentials"`
+}
+
+// credentialsForLocation returns the config of the credentials with the
+// longest prefix matching the location, or nil if none match.
+func credentialsForLocation(creds []storageCredential, location string) iceberg.Properties {
+	var best *storageCredential
+	for i, c := range creds {
+		if strings.HasPrefix(location, c.Prefix) && (best == nil || len(c.Prefix) > len(best.Prefix)) {
+			best = &creds[i]
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	return best.Config
+}
+
 type createTableRequest struct {
 	Name          string                 `json:"name"`
 	Schema        *iceberg.Schema        `json:"schema"`
@@ -644,6 +684,17 @@ func (r *Catalog) fetchConfig(ctx context.Context, opts *options) (*http.Client,
 	return sess, &o, nil
 }
 
+// sessionToken returns the OAuth token the catalog session authenticates
+// with, if any.
+func (r *Catalog) sessionToken() string {
+	session, ok := r.cl.Transport.(*sessionTransport)
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimPrefix(session.defaultHeaders.Get(authorizationHeader), bearerPrefix+" ")
+}
+
 func (r *Catalog) Name() string              { return r.name }
 func (r *Catalog) CatalogType() catalog.Type { return catalog.REST }
 
@@ -656,15 +707,52 @@ func checkValidNamespace(ident table.Identifier) error {
 }
 
 func (r *Catalog) tableFromResponse(ctx context.Context, identifier []string, metadata table.Metadata, loc string, config iceberg.Properties) (*table.Table, error) {
+	// the S3 requests of tables using remote signing are signed by the
+	// catalog, authenticated with the token of the catalog session.
+	if _, ok := config[iceio.S3SignerUri]; ok && config[keyOauthToken] == "" {
+		if token := r.sessionToken(); token != "" {
+			config[keyOauthToken] = token
+		}
+	}
+
+	fsF := iceio.LoadFSFunc(config, loc)
+	if _, ok := iceio.CredentialsExpiration(config); ok {
+		fsF = iceio.LoadFSFuncWithRefresh(config, loc, func(ctx context.Context) (map[string]string, error) {
+			return r.loadCredentials(ctx, identifier, metadata.Location())
+		})
+	}
+
 	return table.New(
 		identifier,
 		metadata,
 		loc,
-		iceio.LoadFSFunc(config, loc),
+		fsF,
 		r,
 	), nil
 }
 
+// loadCredentials returns the config of the credentials vended for the
+// table location by the credentials endpoint of the catalog.
+func (r *Catalog) loadCredentials(ctx context.Context, ident table.Identifier, location string) (iceberg.Properties, error) {
+	ns, tbl, err := splitIdentForPath(ident)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := doGet[loadCredentialsResponse](ctx, r.baseURI, []string{"namespaces", ns, "tables", tbl, "credentials"},
+		r.cl, map[int]error{http.StatusNotFound: catalog.ErrNoSuchTable})
+	if err != nil {
+		return nil, err
+	}
+
+	config := credentialsForLocation(ret.StorageCredentials, location)
+	if config == nil {
+		return nil, fmt.Errorf("no credentials vended for table location %s", location)
+	}
+
+	return config, nil
+}
+
 func (r *Catalog) ListTables(ctx context.Context, namespace table.Identifier) iter.Seq2[table.Identifier, error] {
 	return func(yield func(table.Identifier, error) bool) {
 		pageSize := r.getPageSize(ctx)
@@ -776,6 +864,7 @@ func (r *Catalog) CreateTable(ctx context.Context, identifier table.Identifier,
 	config := maps.Clone(r.props)
 	maps.Copy(config, ret.Metadata.Properties())
 	maps.Copy(config, ret.Config)
+	maps.Copy(config, credentialsForLocation(ret.StorageCredentials, ret.Metadata.Location()))
 
 	return r.tableFromResponse(ctx, identifier, ret.Metadata, ret.MetadataLoc, config)
 }
@@ -801,7 +890,7 @@ func (r *Catalog) CommitTable(ctx context.Context, tbl *table.Table, requirement
 
 	ret, err := doPost[payload, commitTableResponse](ctx, r.baseURI, []string{"namespaces", ns, "tables", tblName},
 		payload{Identifier: restIdentifier, Requirements: requirements, Updates: updates}, r.cl,
-		map[int]error{http.StatusNotFound: catalog.ErrNoSuchTable, http.StatusConflict: ErrCommitFailed})
+		commitErrors)
 	if err != nil {
 		return nil, "", err
 	}
@@ -834,6 +923,7 @@ func (r *Catalog) RegisterTable(ctx context.Context, identifier table.Identifier
 	config := maps.Clone(r.props)
 	maps.Copy(config, ret.Metadata.Properties())
 	maps.Copy(config, ret.Config)
+	maps.Copy(config, credentialsForLocation(ret.StorageCredentials, ret.Metadata.Location()))
 
 	return r.tableFromResponse(ctx, identifier, ret.Metadata, ret.MetadataLoc, config)
 }
@@ -856,6 +946,7 @@ func (r *Catalog) LoadTable(ctx context.Context, identifier table.Identifier, pr
 	for k, v := range ret.Config {
 		config[k] = v
 	}
+	maps.Copy(config, credentialsForLocation(ret.StorageCredentials, ret.Metadata.Location()))
 
 	return r.tableFromResponse(ctx, identifier, ret.Metadata, ret.MetadataLoc, config)
 }
@@ -877,7 +968,7 @@ func (r *Catalog) UpdateTable(ctx context.Context, ident table.Identifier, requi
 	}
 	ret, err := doPost[payload, commitTableResponse](ctx, r.baseURI, []string{"namespaces", ns, "tables", tbl},
 		payload{Identifier: restIdentifier, Requirements: requirements, Updates: updates}, r.cl,
-		map[int]error{http.StatusNotFound: catalog.ErrNoSuchTable, http.StatusConflict: ErrCommitFailed})
+		commitErrors)
 	if err != nil {
 		return nil, err
 	}
@@ -33,6 +33,7 @@ import (
 	"github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/catalog"
 	"github.com/apache/iceberg-go/catalog/rest"
+	iceio "github.com/apache/iceberg-go/io"
 	"github.com/apache/iceberg-go/table"
 	"github.com/stretchr/testify/suite"
 )
@@ -1212,6 +1213,88 @@ func (r *RestCatalogSuite) TestLoadTable200() {
 	}))
 }
 
+func (r *RestCatalogSuite) TestLoadTableRefreshesVendedCredentials() {
+	var tokens []string
+	iceio.Register("vended", iceio.RegistrarFunc(func(_ context.Context, _ *url.URL, props map[string]string) (iceio.IO, error) {
+		tokens = append(tokens, props[iceio.S3SessionToken])
+
+		return iceio.LocalFS{}, nil
+	}))
+	defer iceio.Unregister("vended")
+
+	expiresAt := func(d time.Duration) string {
+		return strconv.FormatInt(time.Now().Add(d).UnixMilli(), 10)
+	}
+
+	r.mux.HandleFunc("/v1/namespaces/fokko/tables/table", func(w http.ResponseWriter, req *http.Request) {
+		r.Require().Equal(http.MethodGet, req.Method)
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"metadata-location": "vended://warehouse/database/table/metadata/00001.metadata.json",
+			"metadata": json.RawMessage(`{
+				"format-version": 2,
+				"table-uuid": "b55d9dda-6561-423a-8bfc-787980ce421f",
+				"location": "vended://warehouse/database/table",
+				"last-sequence-number": 0,
+				"last-updated-ms": 1646787054459,
+				"last-column-id": 1,
+				"current-schema-id": 0,
+				"schemas": [{"type": "struct", "schema-id": 0, "fields": [
+					{"id": 1, "name": "id", "required": false, "type": "int"}
+				]}],
+				"default-spec-id": 0,
+				"partition-specs": [{"spec-id": 0, "fields": []}],
+				"last-partition-id": 999,
+				"default-sort-order-id": 0,
+				"sort-orders": [{"order-id": 0, "fields": []}]
+			}`),
+			"config": map[string]string{},
+			"storage-credentials": []map[string]any{
+				{"prefix": "vended://warehouse/", "config": map[string]string{
+					iceio.S3SessionToken: "other",
+				}},
+				{"prefix": "vended://warehouse/database/", "config": map[string]string{
+					iceio.S3SessionToken:            "expiring",
+					iceio.S3SessionTokenExpiresAtMs: expiresAt(time.Minute),
+				}},
+			},
+		})
+	})
+
+	var refreshes int
+	r.mux.HandleFunc("/v1/namespaces/fokko/tables/table/credentials", func(w http.ResponseWriter, req *http.Request) {
+		r.Require().Equal(http.MethodGet, req.Method)
+
+		for k, v := range TestHeaders {
+			r.Equal(v, req.Header.Values(k))
+		}
+
+		refreshes++
+		json.NewEncoder(w).Encode(map[string]any{
+			"storage-credentials": []map[string]any{
+				{"prefix": "vended://warehouse/database/table", "config": map[string]string{
+					iceio.S3SessionToken:            "refreshed",
+					iceio.S3SessionTokenExpiresAtMs: expiresAt(time.Hour),
+				}},
+			},
+		})
+	})
+
+	cat, err := rest.NewCatalog(context.Background(), "rest", r.srv.URL, rest.WithOAuthToken(TestToken))
+	r.Require().NoError(err)
+
+	tbl, err := cat.LoadTable(context.Background(), catalog.ToIdentifier("fokko", "table"), nil)
+	r.Require().NoError(err)
+
+	_, err = tbl.FS(context.Background())
+	r.Require().NoError(err)
+	_, err = tbl.FS(context.Background())
+	r.Require().NoError(err)
+
+	r.Equal(1, refreshes)
+	r.Equal([]string{"refreshed", "refreshed"}, tokens)
+}
+
 func (r *RestCatalogSuite) TestRenameTable200() {
 	// Mock the rename table endpoint
 	r.mux.HandleFunc("/v1/tables/rename", func(w http.ResponseWriter, req *http.Request) {
@@ -1535,6 +1618,36 @@ func (r *RestCatalogSuite) TestRegisterTable409() {
 	r.ErrorContains(err, "The given table already exists")
 }
 
+func (r *RestCatalogSuite) TestUpdateTableErrors() {
+	tests := []struct {
+		status int
+		err    error
+	}{
+		{http.StatusConflict, table.ErrCommitFailed},
+		{http.StatusInternalServerError, table.ErrCommitStateUnknown},
+		{http.StatusServiceUnavailable, table.ErrCommitStateUnknown},
+		{http.StatusGatewayTimeout, table.ErrCommitStateUnknown},
+	}
+
+	var status int
+	r.mux.HandleFunc("/v1/namespaces/fokko/tables/commit", func(w http.ResponseWriter, req *http.Request) {
+		r.Require().Equal(http.MethodPost, req.Method)
+
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error": {"message": "commit failed", "type": "CommitFailedException", "code": ` +
+			strconv.Itoa(status) + `}}`))
+	})
+
+	cat, err := rest.NewCatalog(context.Background(), "rest", r.srv.URL, rest.WithOAuthToken(TestToken))
+	r.Require().NoError(err)
+
+	for _, tt := range tests {
+		status = tt.status
+		_, err = cat.UpdateTable(context.Background(), catalog.ToIdentifier("fokko", "commit"), nil, nil)
+		r.ErrorIs(err, tt.err, http.StatusText(tt.status))
+	}
+}
+
 func (r *RestCatalogSuite) TestListViews200() {
 	customPageSize := 100
 	namespace := "accounting"
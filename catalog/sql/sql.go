@@ -368,7 +368,7 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, reqs []tabl
 			}
 
 			if n == 0 {
-				return fmt.Errorf("table has been updated by another process: %s.%s", strings.Join(ns, "."), tblName)
+				return fmt.Errorf("%w: table has been updated by another process: %s.%s", table.ErrCommitFailed, strings.Join(ns, "."), tblName)
 			}
 
 			return nil
@@ -29,6 +29,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/arrow-go/v18/parquet/pqarrow"
@@ -1043,6 +1044,189 @@ func (s *SqliteCatalogTestSuite) TestCommitTable() {
 	}
 }
 
+func (s *SqliteCatalogTestSuite) TestCommitTableRetry() {
+	cat := s.getCatalogSqlite()
+	tblID := s.randomTableIdentifier()
+	ctx := context.Background()
+	s.Require().NoError(cat.CreateNamespace(ctx, catalog.NamespaceFromIdent(tblID), nil))
+
+	sc := iceberg.NewSchema(0, iceberg.NestedField{
+		ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: false,
+	})
+	tbl, err := cat.CreateTable(ctx, tblID, sc, catalog.WithProperties(iceberg.Properties{
+		table.CommitMinRetryWaitMsKey: "1",
+	}))
+	s.Require().NoError(err)
+
+	arrSchema, err := table.SchemaToArrowSchema(sc, nil, false, false)
+	s.Require().NoError(err)
+
+	appendRows := func(tx *table.Transaction, rows string) {
+		arrTbl, err := array.TableFromJSON(memory.DefaultAllocator, arrSchema, []string{rows})
+		s.Require().NoError(err)
+		defer arrTbl.Release()
+
+		s.Require().NoError(tx.AppendTable(ctx, arrTbl, 10, nil))
+	}
+
+	first, second := tbl.NewTransaction(), tbl.NewTransaction()
+	appendRows(first, `[{"id": 1}, {"id": 2}]`)
+	appendRows(second, `[{"id": 3}]`)
+
+	firstTbl, err := first.Commit(ctx)
+	s.Require().NoError(err)
+
+	secondTbl, err := second.Commit(ctx)
+	s.Require().NoError(err)
+
+	s.Len(secondTbl.Metadata().Snapshots(), 2)
+	snap := secondTbl.CurrentSnapshot()
+	s.Require().NotNil(snap.ParentSnapshotID)
+	s.Equal(firstTbl.CurrentSnapshot().SnapshotID, *snap.ParentSnapshotID)
+	s.EqualValues(2, snap.SequenceNumber)
+	s.Equal("3", snap.Summary.Properties["total-records"])
+	s.Equal("2", snap.Summary.Properties["total-data-files"])
+
+	// a conflicting schema change cannot be re-applied and fails the commit
+	first, second = secondTbl.NewTransaction(), secondTbl.NewTransaction()
+	s.Require().NoError(first.UpdateSchema(true).AddColumn([]string{"a"}, iceberg.PrimitiveTypes.String, "", false).Commit())
+	s.Require().NoError(second.UpdateSchema(true).AddColumn([]string{"b"}, iceberg.PrimitiveTypes.String, "", false).Commit())
+
+	_, err = first.Commit(ctx)
+	s.Require().NoError(err)
+
+	_, err = second.Commit(ctx)
+	s.ErrorIs(err, table.ErrCommitFailed)
+	s.ErrorContains(err, "current schema id has changed")
+}
+
+func (s *SqliteCatalogTestSuite) TestCommitTableRetryValidation() {
+	cat := s.getCatalogSqlite()
+	ctx := context.Background()
+	ns := table.Identifier{"validation"}
+	s.Require().NoError(cat.CreateNamespace(ctx, ns, nil))
+
+	sc := iceberg.NewSchema(0, iceberg.NestedField{
+		ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: false,
+	})
+	arrSchema, err := table.SchemaToArrowSchema(sc, nil, false, false)
+	s.Require().NoError(err)
+
+	rows := func(data string) arrow.Table {
+		arrTbl, err := array.TableFromJSON(memory.DefaultAllocator, arrSchema, []string{data})
+		s.Require().NoError(err)
+		s.T().Cleanup(arrTbl.Release)
+
+		return arrTbl
+	}
+
+	newTable := func(name string) *table.Table {
+		tbl, err := cat.CreateTable(ctx, append(ns, name), sc, catalog.WithProperties(iceberg.Properties{
+			table.CommitMinRetryWaitMsKey: "1",
+		}))
+		s.Require().NoError(err)
+
+		tbl, err = tbl.AppendTable(ctx, rows(`[{"id": 1}, {"id": 2}]`), 10, nil)
+		s.Require().NoError(err)
+
+		return tbl
+	}
+
+	overwriteFilter := iceberg.GreaterThanEqual(iceberg.Reference("id"), int64(10))
+	tests := []struct {
+		name       string
+		isolation  table.IsolationLevel
+		concurrent string
+		err        error
+	}{
+		{"serializable_conflict", table.IsolationSerializable, `[{"id": 11}]`, table.ErrValidationFailed},
+		{"serializable_no_conflict", table.IsolationSerializable, `[{"id": 3}]`, nil},
+		{"snapshot", table.IsolationSnapshot, `[{"id": 11}]`, nil},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tbl := newTable(tt.name)
+
+			tx := tbl.NewTransaction(table.WithIsolationLevel(tt.isolation))
+			s.Require().NoError(tx.OverwriteTable(ctx, rows(`[{"id": 10}]`), 10, overwriteFilter, nil))
+
+			_, err := tbl.AppendTable(ctx, rows(tt.concurrent), 10, nil)
+			s.Require().NoError(err)
+
+			updated, err := tx.Commit(ctx)
+			if tt.err != nil {
+				s.ErrorIs(err, tt.err)
+				s.NotErrorIs(err, table.ErrCommitFailed)
+
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Equal(table.OpOverwrite, updated.CurrentSnapshot().Summary.Operation)
+			s.Len(updated.Metadata().Snapshots(), 3)
+		})
+	}
+
+	s.Run("deleted_concurrently", func() {
+		tbl := newTable("deleted_concurrently")
+		deleteFilter := iceberg.LessThan(iceberg.Reference("id"), int64(10))
+
+		tx := tbl.NewTransaction()
+		s.Require().NoError(tx.Delete(ctx, deleteFilter, nil))
+
+		_, err := tbl.Delete(ctx, deleteFilter, nil)
+		s.Require().NoError(err)
+
+		_, err = tx.Commit(ctx)
+		s.ErrorIs(err, table.ErrValidationFailed)
+		s.ErrorContains(err, "data files were removed concurrently")
+	})
+
+	spec := iceberg.NewPartitionSpec(iceberg.PartitionField{
+		SourceID: 1, FieldID: 1000, Name: "id_trunc", Transform: iceberg.TruncateTransform{Width: 10},
+	})
+	dynamicTests := []struct {
+		name       string
+		concurrent string
+		err        error
+	}{
+		{"dynamic_conflict", `[{"id": 15}]`, table.ErrValidationFailed},
+		{"dynamic_no_conflict", `[{"id": 25}]`, nil},
+	}
+
+	for _, tt := range dynamicTests {
+		s.Run(tt.name, func() {
+			tbl, err := cat.CreateTable(ctx, append(ns, tt.name), sc, catalog.WithPartitionSpec(&spec),
+				catalog.WithProperties(iceberg.Properties{table.CommitMinRetryWaitMsKey: "1"}))
+			s.Require().NoError(err)
+
+			tbl, err = tbl.AppendTable(ctx, rows(`[{"id": 1}, {"id": 12}]`), 10, nil)
+			s.Require().NoError(err)
+
+			rdr := array.NewTableReader(rows(`[{"id": 11}]`), 10)
+			defer rdr.Release()
+
+			tx := tbl.NewTransaction(table.WithIsolationLevel(table.IsolationSerializable))
+			s.Require().NoError(tx.DynamicPartitionOverwrite(ctx, rdr, nil))
+
+			_, err = tbl.AppendTable(ctx, rows(tt.concurrent), 10, nil)
+			s.Require().NoError(err)
+
+			updated, err := tx.Commit(ctx)
+			if tt.err != nil {
+				s.ErrorIs(err, tt.err)
+
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Len(updated.Metadata().Snapshots(), 3)
+			s.Equal("3", updated.CurrentSnapshot().Summary.Properties["total-records"])
+		})
+	}
+}
+
 func (s *SqliteCatalogTestSuite) TestCreateView() {
 	db := s.getCatalogSqlite()
 	s.Require().NoError(db.CreateSQLTables(context.Background()))
@@ -57,6 +57,7 @@ go.opentelemetry.io/otel/metric v1.37.0
 go.opentelemetry.io/otel/sdk v1.37.0
 go.opentelemetry.io/otel/sdk/metric v1.37.0
 gocloud.dev v0.43.0
+golang.org/x/oauth2 v0.30.0
 golang.org/x/sync v0.16.0
 google.golang.org/api v0.242.0
 gopkg.in/yaml.v3 v3.0.1
@@ -317,7 +318,6 @@ require (
 	golang.org/x/exp v0.0.0-20250711185948-6ae5c78190dc // indirect
 	golang.org/x/mod v0.26.0 // indirect
 	golang.org/x/net v0.42.0 // indirect
-	golang.org/x/oauth2 v0.30.0 // indirect
 	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/term v0.33.0 // indirect
 	golang.org/x/text v0.27.0 // indirect
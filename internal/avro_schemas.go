@@ -58,6 +58,7 @@ var (
 	BinarySchema         = avro.NewPrimitiveSchema(avro.Bytes, nil)
 	NullableBinarySchema = NullableSchema(BinarySchema)
 	StringSchema         = avro.NewPrimitiveSchema(avro.String, nil)
+	NullableStringSchema = NullableSchema(StringSchema)
 	IntSchema            = avro.NewPrimitiveSchema(avro.Int, nil)
 	NullableIntSchema    = NullableSchema(IntSchema)
 	LongSchema           = avro.NewPrimitiveSchema(avro.Long, nil)
@@ -225,6 +226,12 @@ func init() {
 			WithFieldID(519))),
 	})))
 
+	AvroSchemaCache.Add("manifest_list_file_v3", Must(avro.NewRecordSchema("manifest_file", "",
+		append(AvroSchemaCache.Get("manifest_list_file_v2").(*avro.RecordSchema).Fields(),
+			Must(avro.NewField("first_row_id", NullableLongSchema,
+				avro.WithDoc("Starting row ID to assign to new rows in ADDED data files"),
+				WithFieldID(520)))))))
+
 	AvroSchemaCache.Add("data_file_v1", Must(avro.NewRecordSchema("r2", "", []*avro.Field{
 		Must(avro.NewField("file_path",
 			StringSchema,
@@ -351,6 +358,21 @@ func init() {
 			WithFieldID(140))),
 	})))
 
+	AvroSchemaCache.Add("data_file_v3", Must(avro.NewRecordSchema("r2", "",
+		append(AvroSchemaCache.Get("data_file_v2").(*avro.RecordSchema).Fields(),
+			Must(avro.NewField("first_row_id", NullableLongSchema,
+				avro.WithDoc("The _row_id for the first row in the data file"),
+				WithFieldID(142))),
+			Must(avro.NewField("referenced_data_file", NullableStringSchema,
+				avro.WithDoc("Fully qualified location of a data file that all deletes reference"),
+				WithFieldID(143))),
+			Must(avro.NewField("content_offset", NullableLongSchema,
+				avro.WithDoc("The offset in the file where the content starts"),
+				WithFieldID(144))),
+			Must(avro.NewField("content_size_in_bytes", NullableLongSchema,
+				avro.WithDoc("The length of referenced content stored in the file"),
+				WithFieldID(145)))))))
+
 	AvroSchemaCache.Add("manifest_entry_v1", Must(avro.NewRecordSchema("manifest_entry", "", []*avro.Field{
 		Must(avro.NewField("status", IntSchema, WithFieldID(0))),
 		Must(avro.NewField("snapshot_id", LongSchema, WithFieldID(1))),
@@ -364,6 +386,9 @@ func init() {
 		Must(avro.NewField("file_sequence_number", NullableLongSchema, WithFieldID(4))),
 		// leave data_file for dynamic generation
 	})))
+
+	AvroSchemaCache.Add("manifest_entry_v3", Must(avro.NewRecordSchema("manifest_entry", "",
+		AvroSchemaCache.Get("manifest_entry_v2").(*avro.RecordSchema).Fields())))
 }
 
 func newDataFileSchema(partitionType avro.Schema, version int) (avro.Schema, error) {
@@ -382,7 +407,7 @@ func newDataFileSchema(partitionType avro.Schema, version int) (avro.Schema, err
 
 func NewManifestFileSchema(version int) (avro.Schema, error) {
 	switch version {
-	case 1, 2:
+	case 1, 2, 3:
 	default:
 		return nil, fmt.Errorf("unsupported iceberg spec version: %d", version)
 	}
@@ -394,7 +419,7 @@ func NewManifestFileSchema(version int) (avro.Schema, error) {
 
 func NewManifestEntrySchema(partitionType avro.Schema, version int) (avro.Schema, error) {
 	switch version {
-	case 1, 2:
+	case 1, 2, 3:
 	default:
 		return nil, fmt.Errorf("unsupported iceberg spec version: %d", version)
 	}
@@ -53,6 +53,8 @@ var (
 	filteredBytesCounter otelmetric.Int64Counter
 	scanPlanDuration     otelmetric.Float64Histogram
 	scanPlanBytes        otelmetric.Int64Histogram
+	cacheHitsCounter     otelmetric.Int64Counter
+	cacheMissesCounter   otelmetric.Int64Counter
 
 	counters   sync.Map // map[string]otelmetric.Int64Counter
 	histograms sync.Map // map[string]otelmetric.Float64Histogram
@@ -66,6 +68,7 @@ var (
 	componentKey = attribute.Key("component")
 	operationKey = attribute.Key("operation")
 	statusKey    = attribute.Key("status")
+	cacheKey     = attribute.Key("cache")
 )
 
 func (c Config) withDefaults() Config {
@@ -230,6 +233,22 @@ func initInstruments() error {
 		return err
 	}
 
+	cacheHitsCounter, err = meter.Int64Counter(
+		"libiceberg.cache.hits",
+		instrument.WithDescription("Lookups of metadata files served from a cache"),
+	)
+	if err != nil {
+		return err
+	}
+
+	cacheMissesCounter, err = meter.Int64Counter(
+		"libiceberg.cache.misses",
+		instrument.WithDescription("Lookups of metadata files missing from a cache"),
+	)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -387,6 +406,25 @@ func RecordScanPlanSize(value int64, attrs ...attribute.KeyValue) {
 	scanPlanBytes.Record(context.Background(), value, otelmetric.WithAttributes(attr...))
 }
 
+func AddCacheHit(cache string, attrs ...attribute.KeyValue) {
+	addCacheLookup(cacheHitsCounter, cache, attrs...)
+}
+
+func AddCacheMiss(cache string, attrs ...attribute.KeyValue) {
+	addCacheLookup(cacheMissesCounter, cache, attrs...)
+}
+
+func addCacheLookup(ctr otelmetric.Int64Counter, cache string, attrs ...attribute.KeyValue) {
+	if !providerReady.Load() || ctr == nil {
+		return
+	}
+	attr := append([]attribute.KeyValue{
+		componentKey.String("cache"),
+		cacheKey.String(cache),
+	}, attrs...)
+	ctr.Add(context.Background(), 1, otelmetric.WithAttributes(attr...))
+}
+
 func recordDuration(hist otelmetric.Float64Histogram, duration time.Duration, err error, base []attribute.KeyValue, extra ...attribute.KeyValue) {
 	if !providerReady.Load() || hist == nil {
 		return
@@ -37,6 +37,9 @@ const (
 	AdlsSharedKeyAccountKey    = "adls.auth.shared-key.account.key"
 	AdlsEndpoint               = "adls.endpoint"
 	AdlsProtocol               = "adls.protocol"
+	// AdlsSasTokenExpiresAtMsPrefix prefixes the time the SAS token of an
+	// account expires, in milliseconds since the epoch.
+	AdlsSasTokenExpiresAtMsPrefix = "adls.sas-token-expires-at-ms."
 
 	// Not in use yet
 	// AdlsReadBlockSize          = "adls.read.block-size-bytes"
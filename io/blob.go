@@ -20,11 +20,17 @@ package io
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
+	"iter"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"gocloud.dev/blob"
 )
 
@@ -75,9 +81,12 @@ type blobFileIO struct {
 	*blob.Bucket
 
 	bucketName string
+	key        string
 	ctx        context.Context
 }
 
+func (bfs *blobFileIO) Key() string { return bfs.key }
+
 func (bfs *blobFileIO) preprocess(key string) string {
 	_, after, found := strings.Cut(key, "://")
 	if found {
@@ -109,6 +118,94 @@ func (bfs *blobFileIO) Remove(name string) error {
 	return bfs.Bucket.Delete(bfs.ctx, name)
 }
 
+// List returns the blobs whose keys are under the key of the location.
+func (bfs *blobFileIO) List(location string) iter.Seq2[FileInfo, error] {
+	return func(yield func(FileInfo, error) bool) {
+		// list the location as a directory, the prefix is empty for the
+		// root of the bucket
+		location = strings.TrimSuffix(location, "/") + "/"
+		prefix := bfs.preprocess(location)
+
+		itr := bfs.Bucket.List(&blob.ListOptions{Prefix: prefix})
+		for {
+			obj, err := itr.Next(bfs.ctx)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(FileInfo{}, err)
+
+				return
+			}
+
+			if obj.IsDir {
+				continue
+			}
+
+			if !yield(FileInfo{
+				Location: listedLocation(location, prefix, obj.Key),
+				Size:     obj.Size,
+				ModTime:  obj.ModTime,
+			}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// s3DeleteBatchSize is the maximum number of keys of an S3 DeleteObjects
+// request.
+const s3DeleteBatchSize = 1000
+
+// DeleteFiles removes the named blobs, using DeleteObjects requests for
+// S3 buckets and deleting the blobs one by one otherwise.
+func (bfs *blobFileIO) DeleteFiles(names []string) ([]string, error) {
+	var client *s3.Client
+	if !bfs.Bucket.As(&client) {
+		return deleteEach(names, bfs.Remove)
+	}
+
+	var (
+		deleted = make([]string, 0, len(names))
+		errs    []error
+	)
+	for batch := range slices.Chunk(names, s3DeleteBatchSize) {
+		byKey := make(map[string]string, len(batch))
+		objects := make([]types.ObjectIdentifier, len(batch))
+		for i, name := range batch {
+			key := bfs.preprocess(name)
+			byKey[key] = name
+			objects[i] = types.ObjectIdentifier{Key: aws.String(key)}
+		}
+
+		out, err := client.DeleteObjects(bfs.ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bfs.bucketName),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		// in quiet mode only the keys which failed are returned
+		for _, e := range out.Errors {
+			key := aws.ToString(e.Key)
+			errs = append(errs, fmt.Errorf("failed to delete %s: %s: %s",
+				byKey[key], aws.ToString(e.Code), aws.ToString(e.Message)))
+			delete(byKey, key)
+		}
+
+		for _, name := range batch {
+			if _, ok := byKey[bfs.preprocess(name)]; ok {
+				deleted = append(deleted, name)
+			}
+		}
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
 func (bfs *blobFileIO) Create(name string) (FileWriter, error) {
 	return bfs.NewWriter(bfs.ctx, name, true, nil)
 }
@@ -154,8 +251,8 @@ func (io *blobFileIO) NewWriter(ctx context.Context, path string, overwrite bool
 		nil
 }
 
-func createBlobFS(ctx context.Context, bucket *blob.Bucket, bucketName string) IO {
-	return &blobFileIO{Bucket: bucket, bucketName: bucketName, ctx: ctx}
+func createBlobFS(ctx context.Context, bucket *blob.Bucket, bucketName, key string) IO {
+	return &blobFileIO{Bucket: bucket, bucketName: bucketName, key: key, ctx: ctx}
 }
 
 type blobWriteFile struct {
@@ -0,0 +1,339 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package io
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"gocloud.dev/gcerrors"
+)
+
+// credentialsRefreshMargin is how long before they expire vended
+// credentials are refreshed.
+const credentialsRefreshMargin = 5 * time.Minute
+
+// CredentialsExpiration returns the earliest time the vended credentials
+// of the properties expire at, using the S3 session token, GCS OAuth2 token
+// and ADLS SAS token expiration properties. It returns false if none of the
+// credentials expire.
+func CredentialsExpiration(props map[string]string) (time.Time, bool) {
+	var (
+		earliest time.Time
+		found    bool
+	)
+	for k, v := range props {
+		if k != S3SessionTokenExpiresAtMs && k != GCSOAuth2TokenExpiresAt &&
+			!strings.HasPrefix(k, AdlsSasTokenExpiresAtMsPrefix) {
+			continue
+		}
+
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if t := time.UnixMilli(ms); !found || t.Before(earliest) {
+			earliest, found = t, true
+		}
+	}
+
+	return earliest, found
+}
+
+// CredentialsRefresher returns fresh vended credentials, as properties
+// overriding the ones the IO was loaded with.
+type CredentialsRefresher func(ctx context.Context) (map[string]string, error)
+
+// vendedCredentials holds the properties of an IO using vended credentials,
+// refreshing them before they expire.
+type vendedCredentials struct {
+	refresh CredentialsRefresher
+
+	mx        sync.Mutex
+	props     map[string]string
+	expiresAt time.Time
+	expires   bool
+}
+
+// get returns the properties, refreshing the credentials first if they
+// are about to expire or if force is set.
+func (v *vendedCredentials) get(ctx context.Context, force bool) (map[string]string, error) {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+
+	if !force && (!v.expires || time.Until(v.expiresAt) > credentialsRefreshMargin) {
+		return v.props, nil
+	}
+
+	creds, err := v.refresh(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh vended credentials: %w", err)
+	}
+
+	props := maps.Clone(v.props)
+	maps.Copy(props, creds)
+	v.props = props
+	v.expiresAt, v.expires = CredentialsExpiration(props)
+
+	return v.props, nil
+}
+
+// LoadFSFuncWithRefresh is like LoadFSFunc for properties holding vended
+// credentials, which are refreshed with the refresher before they expire.
+// The operations of the returned IO failing with a permission error are
+// retried once after refreshing the credentials, for the IO implementations
+// backed by object stores.
+func LoadFSFuncWithRefresh(props map[string]string, location string, refresh CredentialsRefresher) func(ctx context.Context) (IO, error) {
+	creds := &vendedCredentials{refresh: refresh, props: props}
+	creds.expiresAt, creds.expires = CredentialsExpiration(props)
+
+	return func(ctx context.Context) (IO, error) {
+		props, err := creds.get(ctx, false)
+		if err != nil {
+			return nil, err
+		}
+
+		iofs, err := LoadFS(ctx, props, location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load metadata file at %s: %w", location, err)
+		}
+
+		if _, ok := iofs.(refreshableIO); !ok {
+			return iofs, nil
+		}
+
+		// the IO outlives the call loading it, the credentials are
+		// refreshed with its context without its cancellation.
+		return &refreshingIO{
+			ctx: context.WithoutCancel(ctx), location: location,
+			creds: creds, fs: iofs.(refreshableIO),
+		}, nil
+	}
+}
+
+// refreshableIO is the set of interfaces implemented by the object store
+// backed IO, which refreshingIO implements by delegation.
+type refreshableIO interface {
+	WriteFileIO
+	ListIO
+	BulkDeleteIO
+}
+
+// refreshingIO retries the operations of an IO failing with a permission
+// error once, with an IO loaded with refreshed credentials.
+type refreshingIO struct {
+	// ctx is used to refresh the credentials, the operations of the IO
+	// don't take a context.
+	ctx      context.Context
+	location string
+	creds    *vendedCredentials
+
+	mx sync.Mutex
+	fs refreshableIO
+}
+
+// isPermissionError returns whether the error is caused by the object
+// store denying the request, such as when the credentials have expired.
+func isPermissionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if gcerrors.Code(err) == gcerrors.PermissionDenied {
+		return true
+	}
+
+	// S3 rejects expired and invalid session tokens as bad requests
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ExpiredToken", "InvalidToken", "TokenRefreshRequired":
+			return true
+		}
+	}
+
+	var rspErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &rspErr) {
+		code := rspErr.HTTPStatusCode()
+
+		return code == http.StatusUnauthorized || code == http.StatusForbidden
+	}
+
+	return false
+}
+
+// Key returns the key of the current IO, which changes along with the
+// credentials.
+func (r *refreshingIO) Key() string {
+	if k, ok := r.current().(KeyedIO); ok {
+		return k.Key()
+	}
+
+	return ""
+}
+
+func (r *refreshingIO) current() refreshableIO {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return r.fs
+}
+
+// reload replaces the IO with one using refreshed credentials, unless it
+// was already replaced since the failed operation used it.
+func (r *refreshingIO) reload(failed refreshableIO) (refreshableIO, error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.fs != failed {
+		return r.fs, nil
+	}
+
+	props, err := r.creds.get(r.ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	iofs, err := LoadFS(r.ctx, props, r.location)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, ok := iofs.(refreshableIO)
+	if !ok {
+		return nil, fmt.Errorf("IO %T loaded with refreshed credentials doesn't support the operations of %T", iofs, r.fs)
+	}
+	r.fs = fs
+
+	return fs, nil
+}
+
+// withRetry runs the operation, running it again once with an IO using
+// refreshed credentials if it fails with a permission error.
+func withRetry[T any](r *refreshingIO, op func(refreshableIO) (T, error)) (T, error) {
+	fs := r.current()
+	out, err := op(fs)
+	if !isPermissionError(err) {
+		return out, err
+	}
+
+	fs, reloadErr := r.reload(fs)
+	if reloadErr != nil {
+		return out, fmt.Errorf("%w (%w)", err, reloadErr)
+	}
+
+	return op(fs)
+}
+
+func (r *refreshingIO) Open(name string) (File, error) {
+	return withRetry(r, func(fs refreshableIO) (File, error) { return fs.Open(name) })
+}
+
+func (r *refreshingIO) Remove(name string) error {
+	_, err := withRetry(r, func(fs refreshableIO) (struct{}, error) { return struct{}{}, fs.Remove(name) })
+
+	return err
+}
+
+func (r *refreshingIO) Create(name string) (FileWriter, error) {
+	return withRetry(r, func(fs refreshableIO) (FileWriter, error) { return fs.Create(name) })
+}
+
+func (r *refreshingIO) WriteFile(name string, p []byte) error {
+	_, err := withRetry(r, func(fs refreshableIO) (struct{}, error) { return struct{}{}, fs.WriteFile(name, p) })
+
+	return err
+}
+
+func (r *refreshingIO) DeleteFiles(names []string) ([]string, error) {
+	fs := r.current()
+	deleted, err := fs.DeleteFiles(names)
+	if !isPermissionError(err) {
+		return deleted, err
+	}
+
+	fs, reloadErr := r.reload(fs)
+	if reloadErr != nil {
+		return deleted, fmt.Errorf("%w (%w)", err, reloadErr)
+	}
+
+	// retry the files which were not deleted
+	remaining := make([]string, 0, len(names)-len(deleted))
+	for _, name := range names {
+		if !slices.Contains(deleted, name) {
+			remaining = append(remaining, name)
+		}
+	}
+
+	more, err := fs.DeleteFiles(remaining)
+
+	return append(deleted, more...), err
+}
+
+// List retries listing the location if it fails with a permission error
+// before any file is listed.
+func (r *refreshingIO) List(location string) iter.Seq2[FileInfo, error] {
+	return func(yield func(FileInfo, error) bool) {
+		var (
+			fs     = r.current()
+			listed bool
+		)
+		for f, err := range fs.List(location) {
+			if err == nil {
+				listed = true
+				if !yield(f, nil) {
+					return
+				}
+
+				continue
+			}
+
+			if listed || !isPermissionError(err) {
+				yield(FileInfo{}, err)
+
+				return
+			}
+
+			fs, reloadErr := r.reload(fs)
+			if reloadErr != nil {
+				yield(FileInfo{}, fmt.Errorf("%w (%w)", err, reloadErr))
+
+				return
+			}
+
+			for f, err := range fs.List(location) {
+				if !yield(f, err) || err != nil {
+					return
+				}
+			}
+
+			return
+		}
+	}
+}
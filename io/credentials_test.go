@@ -0,0 +1,188 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package io_test
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/apache/iceberg-go/io"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type statusError int
+
+func (e statusError) Error() string       { return fmt.Sprintf("status %d", int(e)) }
+func (e statusError) HTTPStatusCode() int { return int(e) }
+
+// expiredTokenError is an error like the ones of the S3 client for requests
+// with an expired session token, which are bad requests.
+type expiredTokenError struct{}
+
+func (expiredTokenError) Error() string       { return "expired token" }
+func (expiredTokenError) HTTPStatusCode() int { return http.StatusBadRequest }
+func (expiredTokenError) Unwrap() error {
+	return &smithy.GenericAPIError{Code: "ExpiredToken", Message: "The provided token has expired."}
+}
+
+// tokenIO is an object store like IO whose operations are denied unless
+// it was loaded with the valid token.
+type tokenIO struct {
+	io.LocalFS
+	token string
+}
+
+func (f tokenIO) Open(name string) (io.File, error) {
+	switch f.token {
+	case "valid":
+		return f.LocalFS.Open(name)
+	case "expired":
+		return nil, expiredTokenError{}
+	}
+
+	return nil, statusError(http.StatusForbidden)
+}
+
+func (f tokenIO) List(string) iter.Seq2[io.FileInfo, error] {
+	return func(yield func(io.FileInfo, error) bool) {
+		if f.token != "valid" {
+			yield(io.FileInfo{}, statusError(http.StatusForbidden))
+
+			return
+		}
+		yield(io.FileInfo{Location: "file"}, nil)
+	}
+}
+
+func (f tokenIO) DeleteFiles(names []string) ([]string, error) { return names, nil }
+
+func TestCredentialsExpiration(t *testing.T) {
+	_, ok := io.CredentialsExpiration(map[string]string{io.S3SessionToken: "token"})
+	assert.False(t, ok)
+
+	exp, ok := io.CredentialsExpiration(map[string]string{
+		io.S3SessionTokenExpiresAtMs:                      "2000",
+		io.GCSOAuth2TokenExpiresAt:                        "3000",
+		io.AdlsSasTokenExpiresAtMsPrefix + "account":      "1000",
+		io.AdlsSasTokenExpiresAtMsPrefix + "otheraccount": "invalid",
+	})
+	assert.True(t, ok)
+	assert.Equal(t, time.UnixMilli(1000), exp)
+}
+
+func TestLoadFSFuncWithRefresh(t *testing.T) {
+	ctx := context.Background()
+	io.Register("vended", io.RegistrarFunc(func(_ context.Context, _ *url.URL, props map[string]string) (io.IO, error) {
+		return tokenIO{token: props[io.S3SessionToken]}, nil
+	}))
+	defer io.Unregister("vended")
+
+	expiresAt := func(d time.Duration) string {
+		return strconv.FormatInt(time.Now().Add(d).UnixMilli(), 10)
+	}
+
+	var refreshes int
+	refresh := func(token string, err error) io.CredentialsRefresher {
+		return func(ctx context.Context) (map[string]string, error) {
+			refreshes++
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			return map[string]string{
+				io.S3SessionToken:            token,
+				io.S3SessionTokenExpiresAtMs: expiresAt(time.Hour),
+			}, err
+		}
+	}
+
+	// credentials about to expire are refreshed before loading the IO
+	fsF := io.LoadFSFuncWithRefresh(map[string]string{
+		io.S3SessionToken:            "expiring",
+		io.S3SessionTokenExpiresAtMs: expiresAt(time.Minute),
+	}, "vended://bucket/table", refresh("valid", nil))
+
+	fs, err := fsF(ctx)
+	require.NoError(t, err)
+	_, err = fs.Open("/does/not/exist")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Equal(t, 1, refreshes)
+
+	_, err = fsF(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, refreshes)
+
+	// operations denied with revoked credentials are retried once with
+	// refreshed credentials
+	refreshes = 0
+	fsF = io.LoadFSFuncWithRefresh(map[string]string{
+		io.S3SessionToken:            "revoked",
+		io.S3SessionTokenExpiresAtMs: expiresAt(time.Hour),
+	}, "vended://bucket/table", refresh("valid", nil))
+
+	fs, err = fsF(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, refreshes)
+
+	var listed []string
+	for f, err := range fs.(io.ListIO).List("vended://bucket/table") {
+		require.NoError(t, err)
+		listed = append(listed, f.Location)
+	}
+	assert.Equal(t, []string{"file"}, listed)
+	assert.Equal(t, 1, refreshes)
+
+	// the operation fails if the refreshed credentials are denied too
+	refreshes = 0
+	fsF = io.LoadFSFuncWithRefresh(map[string]string{
+		io.S3SessionToken:            "revoked",
+		io.S3SessionTokenExpiresAtMs: expiresAt(time.Hour),
+	}, "vended://bucket/table", refresh("revoked", nil))
+
+	fs, err = fsF(ctx)
+	require.NoError(t, err)
+	_, err = fs.Open("/does/not/exist")
+	assert.Equal(t, statusError(http.StatusForbidden), err)
+	assert.Equal(t, 1, refreshes)
+
+	// expired tokens are rejected as bad requests, the credentials are
+	// refreshed after the context loading the IO is canceled
+	refreshes = 0
+	fsF = io.LoadFSFuncWithRefresh(map[string]string{
+		io.S3SessionToken:            "expired",
+		io.S3SessionTokenExpiresAtMs: expiresAt(time.Hour),
+	}, "vended://bucket/table", refresh("valid", nil))
+
+	loadCtx, cancel := context.WithCancel(ctx)
+	fs, err = fsF(loadCtx)
+	require.NoError(t, err)
+	cancel()
+
+	_, err = fs.Open("/does/not/exist")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Equal(t, 1, refreshes)
+}
@@ -26,6 +26,7 @@ import (
 	"gocloud.dev/blob"
 	"gocloud.dev/blob/gcsblob"
 	"gocloud.dev/gcp"
+	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
 )
 
@@ -35,6 +36,12 @@ const (
 	GCSKeyPath    = "gcs.keypath"
 	GCSJSONKey    = "gcs.jsonkey"
 	GCSUseJsonAPI = "gcs.usejsonapi" // set to anything to enable
+	// GCSOAuth2Token is an OAuth2 access token, such as one vended by a
+	// catalog, used instead of the default credentials.
+	GCSOAuth2Token = "gcs.oauth2.token"
+	// GCSOAuth2TokenExpiresAt is the time the OAuth2 token expires, in
+	// milliseconds since the epoch.
+	GCSOAuth2TokenExpiresAt = "gcs.oauth2.token-expires-at"
 )
 
 // ParseGCSConfig parses GCS properties and returns a configuration.
@@ -61,6 +68,16 @@ func ParseGCSConfig(props map[string]string) *gcsblob.Options {
 // Construct a GCS bucket from a URL
 func createGCSBucket(ctx context.Context, parsed *url.URL, props map[string]string) (*blob.Bucket, error) {
 	gcscfg := ParseGCSConfig(props)
+	if token := props[GCSOAuth2Token]; token != "" {
+		client, err := gcp.NewHTTPClient(gcp.DefaultTransport(),
+			oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+		if err != nil {
+			return nil, err
+		}
+
+		return gcsblob.OpenBucket(ctx, client, parsed.Host, gcscfg)
+	}
+
 	creds, _ := gcp.DefaultCredentials(ctx)
 	var client *gcp.HTTPClient
 	if creds == nil {
@@ -5,15 +5,18 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"iter"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/apache/iceberg-go/internal/telemetry/metrics"
 	"go.opentelemetry.io/otel/attribute"
 
 	hdfs "github.com/colinmarc/hdfs/v2"
+	"github.com/colinmarc/hdfs/v2/hadoopconf"
 	krb "github.com/jcmturner/gokrb5/v8/client"
 	krbconfig "github.com/jcmturner/gokrb5/v8/config"
 	krbkeytab "github.com/jcmturner/gokrb5/v8/keytab"
@@ -29,8 +32,29 @@ const (
 	HDFSKerberosKeytab      = "hdfs.kerberos.keytab"
 )
 
+// errStopWalk stops walking the directories listed by HdfsFS.List once
+// the caller stops iterating.
+var errStopWalk = errors.New("stop walk")
+
 // HdfsFS is an implementation of IO backed by an HDFS cluster.
-type HdfsFS struct{ client *hdfs.Client }
+type HdfsFS struct {
+	client  *hdfs.Client
+	key     string
+	poolKey hdfsPoolKey
+	closed  sync.Once
+}
+
+// Key identifies the cluster and the identity the client connects with.
+func (h *HdfsFS) Key() string { return h.key }
+
+// Close releases the client of the file system. The client is shared by
+// the instances created for the same cluster and identity, it is closed
+// and removed from the pool once all of them are closed.
+func (h *HdfsFS) Close() (err error) {
+	h.closed.Do(func() { err = releaseHDFSClient(h.poolKey, h.client) })
+
+	return err
+}
 
 func (h *HdfsFS) preprocess(name string) string {
 	if strings.HasPrefix(name, "hdfs://") {
@@ -81,6 +105,48 @@ func (h *HdfsFS) Remove(name string) error {
 	return err
 }
 
+// List returns the files under the directory at the location.
+func (h *HdfsFS) List(location string) iter.Seq2[FileInfo, error] {
+	return func(yield func(FileInfo, error) bool) {
+		location = strings.TrimSuffix(location, "/")
+		root := h.preprocess(location)
+		start := time.Now()
+		err := h.client.Walk(root, func(path string, info fs.FileInfo, err error) error {
+			if err != nil {
+				if path == root && errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+
+				return err
+			}
+
+			if info.IsDir() {
+				return nil
+			}
+
+			if !yield(FileInfo{
+				Location: listedLocation(location, root, path),
+				Size:     info.Size(),
+				ModTime:  info.ModTime(),
+			}, nil) {
+				return errStopWalk
+			}
+
+			return nil
+		})
+		metrics.RecordHDFSRequest("list", time.Since(start), err)
+		if err != nil && !errors.Is(err, errStopWalk) {
+			yield(FileInfo{}, err)
+		}
+	}
+}
+
+// DeleteFiles removes the named files one by one, HDFS has no batch
+// delete requests.
+func (h *HdfsFS) DeleteFiles(names []string) ([]string, error) {
+	return deleteEach(names, h.Remove)
+}
+
 // Create creates the named file in HDFS and returns a writer for it.
 func (h *HdfsFS) Create(name string) (FileWriter, error) {
 	name = h.preprocess(name)
@@ -177,18 +243,103 @@ func (f *hdfsWriteFile) ReadFrom(r io.Reader) (int64, error) {
 	return io.Copy(f.FileWriter, r)
 }
 
+var (
+	hdfsPoolMutex sync.Mutex
+	hdfsPool      = map[hdfsPoolKey]*hdfsPooledClient{}
+)
+
+// hdfsPoolKey identifies the clients which can be shared by the HdfsFS
+// instances created for the same cluster and identity.
+type hdfsPoolKey struct {
+	addresses           string
+	user                string
+	principal           string
+	keytab              string
+	krb5Conf            string
+	useDatanodeHostname bool
+}
+
+// hdfsPooledClient is a client of the pool, with the kerberos client it
+// authenticates with and the number of HdfsFS instances using it.
+type hdfsPooledClient struct {
+	client *hdfs.Client
+	krb    *krb.Client
+	refs   int
+}
+
+func (p *hdfsPooledClient) close() error {
+	err := p.client.Close()
+	if p.krb != nil {
+		p.krb.Destroy()
+	}
+
+	return err
+}
+
 // createHDFSFS constructs an HDFS-backed IO from a parsed URL and configuration properties.
+//
+// The HDFS clients are shared process wide by the instances using the same
+// namenodes, user and kerberos login, so that the connections and the
+// kerberos logins are reused rather than made on every call. The kerberos
+// client renews its ticket and logs in again with the keytab once the
+// ticket can't be renewed anymore.
 func createHDFSFS(parsed *url.URL, props map[string]string) (IO, error) {
-	addresses := []string{}
-	if nn := props[HDFSNameNode]; nn != "" {
-		addresses = append(addresses, nn)
-	} else if parsed != nil && parsed.Host != "" {
-		addresses = append(addresses, parsed.Host)
+	opts, key, err := hdfsClientOptions(parsed, props)
+	if err != nil {
+		return nil, err
+	}
+
+	if fs := acquireHDFSClient(key, nil); fs != nil {
+		return fs, nil
+	}
+
+	// log in and connect without holding the lock, the pool keeps the
+	// first client created for the key.
+	pooled := &hdfsPooledClient{}
+	if key.principal != "" {
+		if pooled.krb, err = kerberosLogin(key.principal, key.krb5Conf, key.keytab); err != nil {
+			return nil, err
+		}
+		opts.KerberosClient = pooled.krb
+		if opts.KerberosServicePrincipleName == "" {
+			opts.KerberosServicePrincipleName = "nn/_HOST"
+		}
+	}
+
+	if pooled.client, err = hdfs.NewClient(opts); err != nil {
+		if pooled.krb != nil {
+			pooled.krb.Destroy()
+		}
+
+		return nil, fmt.Errorf("failed to connect to HDFS: %w", err)
+	}
+
+	fs := acquireHDFSClient(key, pooled)
+	if fs.client != pooled.client {
+		_ = pooled.close()
 	}
-	if len(addresses) == 0 {
-		return nil, errors.New("hdfs namenode not specified")
+
+	return fs, nil
+}
+
+// hdfsClientOptions returns the options of the client for the location
+// and properties, along with the key of the clients of the pool which can
+// be used instead.
+func hdfsClientOptions(parsed *url.URL, props map[string]string) (hdfs.ClientOptions, hdfsPoolKey, error) {
+	conf, err := hadoopconf.LoadFromEnvironment()
+	if err != nil {
+		return hdfs.ClientOptions{}, hdfsPoolKey{}, fmt.Errorf("failed to load hadoop configuration: %w", err)
+	}
+
+	var opts hdfs.ClientOptions
+	if conf != nil {
+		opts = hdfs.ClientOptionsFromConf(conf)
+	}
+
+	opts.Addresses = hdfsNamenodes(parsed, props, conf)
+	if len(opts.Addresses) == 0 {
+		return opts, hdfsPoolKey{}, errors.New("hdfs namenode not specified")
 	}
-	opts := hdfs.ClientOptions{Addresses: addresses}
 	if user := props[HDFSUser]; user != "" {
 		opts.User = user
 	}
@@ -198,35 +349,142 @@ func createHDFSFS(parsed *url.URL, props map[string]string) (IO, error) {
 		}
 	}
 
-	if principal := props[HDFSKerberosPrincipal]; principal != "" {
-		confPath := props[HDFSKerberosKrb5Conf]
-		keytabPath := props[HDFSKerberosKeytab]
-		if confPath == "" || keytabPath == "" {
-			return nil, errors.New("kerberos configuration requires krb5-conf and keytab")
+	principal := props[HDFSKerberosPrincipal]
+	if principal == "" && opts.KerberosClient != nil {
+		return opts, hdfsPoolKey{}, fmt.Errorf("hadoop configuration enables kerberos but %s is not set", HDFSKerberosPrincipal)
+	}
+
+	return opts, hdfsPoolKey{
+		addresses:           strings.Join(opts.Addresses, ","),
+		user:                opts.User,
+		principal:           principal,
+		keytab:              props[HDFSKerberosKeytab],
+		krb5Conf:            props[HDFSKerberosKrb5Conf],
+		useDatanodeHostname: opts.UseDatanodeHostname,
+	}, nil
+}
+
+// acquireHDFSClient returns an HdfsFS using the client of the pool for the
+// key, adding the given client to the pool if it has none. It returns nil
+// if the pool has no client for the key and none is given.
+func acquireHDFSClient(key hdfsPoolKey, client *hdfsPooledClient) *HdfsFS {
+	hdfsPoolMutex.Lock()
+	defer hdfsPoolMutex.Unlock()
+
+	pooled, ok := hdfsPool[key]
+	if !ok {
+		if client == nil {
+			return nil
 		}
-		kt, err := krbkeytab.Load(keytabPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load kerberos keytab: %w", err)
+		pooled = client
+		hdfsPool[key] = pooled
+	}
+	pooled.refs++
+
+	return &HdfsFS{client: pooled.client, key: fmt.Sprintf("hdfs:%+v", key), poolKey: key}
+}
+
+// releaseHDFSClient releases a use of the client of the pool for the key,
+// closing and removing it once it has no uses left.
+func releaseHDFSClient(key hdfsPoolKey, client *hdfs.Client) error {
+	hdfsPoolMutex.Lock()
+	pooled, ok := hdfsPool[key]
+	if !ok || pooled.client != client {
+		hdfsPoolMutex.Unlock()
+
+		return nil
+	}
+
+	pooled.refs--
+	if pooled.refs > 0 {
+		hdfsPoolMutex.Unlock()
+
+		return nil
+	}
+	delete(hdfsPool, key)
+	hdfsPoolMutex.Unlock()
+
+	return pooled.close()
+}
+
+// hdfsNamenodes returns the addresses of the namenodes to connect to, the
+// client fails over between them in order. The addresses are taken from
+// the comma separated hdfs.namenode property, or else from the host of the
+// location, which is resolved with the hadoop configuration when it names
+// an HA nameservice. Without either, the namenodes of the default file
+// system of the hadoop configuration are used.
+func hdfsNamenodes(parsed *url.URL, props map[string]string, conf hadoopconf.HadoopConf) []string {
+	if nn := props[HDFSNameNode]; nn != "" {
+		return splitAddresses(nn)
+	}
+
+	if parsed != nil && parsed.Host != "" {
+		if addrs := nameserviceNamenodes(conf, parsed.Host); len(addrs) > 0 {
+			return addrs
 		}
-		cfg, err := krbconfig.Load(confPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load krb5 config: %w", err)
+		return []string{parsed.Host}
+	}
+
+	if defaultFS := conf["fs.defaultFS"]; defaultFS != "" {
+		if u, err := url.Parse(defaultFS); err == nil && u.Host != "" {
+			if addrs := nameserviceNamenodes(conf, u.Host); len(addrs) > 0 {
+				return addrs
+			}
+			return []string{u.Host}
 		}
-		parts := strings.Split(principal, "@")
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid kerberos principal: %s", principal)
+	}
+
+	return conf.Namenodes()
+}
+
+// nameserviceNamenodes returns the rpc addresses of the namenodes of the
+// HA nameservice as configured in hdfs-site.xml, or nil if the name isn't
+// a nameservice.
+func nameserviceNamenodes(conf hadoopconf.HadoopConf, nameservice string) []string {
+	ids := conf["dfs.ha.namenodes."+nameservice]
+	if ids == "" {
+		return nil
+	}
+
+	var addrs []string
+	for _, id := range splitAddresses(ids) {
+		if addr := conf["dfs.namenode.rpc-address."+nameservice+"."+id]; addr != "" {
+			addrs = append(addrs, addr)
 		}
-		client := krb.NewWithKeytab(parts[0], parts[1], kt, cfg)
-		if err := client.Login(); err != nil {
-			return nil, fmt.Errorf("kerberos login failed: %w", err)
+	}
+	return addrs
+}
+
+func splitAddresses(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
 		}
-		opts.KerberosClient = client
-		opts.KerberosServicePrincipleName = "nn/_HOST"
 	}
+	return out
+}
 
-	client, err := hdfs.NewClient(opts)
+// kerberosLogin logs the principal in using the keytab and krb5 config.
+func kerberosLogin(principal, confPath, keytabPath string) (*krb.Client, error) {
+	if confPath == "" || keytabPath == "" {
+		return nil, errors.New("kerberos configuration requires krb5-conf and keytab")
+	}
+	kt, err := krbkeytab.Load(keytabPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to HDFS: %w", err)
+		return nil, fmt.Errorf("failed to load kerberos keytab: %w", err)
+	}
+	cfg, err := krbconfig.Load(confPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load krb5 config: %w", err)
+	}
+	parts := strings.Split(principal, "@")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid kerberos principal: %s", principal)
+	}
+	client := krb.NewWithKeytab(parts[0], parts[1], kt, cfg)
+	if err := client.Login(); err != nil {
+		return nil, fmt.Errorf("kerberos login failed: %w", err)
 	}
-	return &HdfsFS{client: client}, nil
+	return client, nil
 }
@@ -0,0 +1,150 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package io
+
+import (
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/colinmarc/hdfs/v2/hadoopconf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testHDFSSite = `<configuration>
+  <property><name>fs.defaultFS</name><value>hdfs://ns1</value></property>
+  <property><name>dfs.nameservices</name><value>ns1</value></property>
+  <property><name>dfs.ha.namenodes.ns1</name><value>nn1, nn2</value></property>
+  <property><name>dfs.namenode.rpc-address.ns1.nn1</name><value>host1:8020</value></property>
+  <property><name>dfs.namenode.rpc-address.ns1.nn2</name><value>host2:8020</value></property>
+</configuration>`
+
+func TestHDFSNamenodes(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hdfs-site.xml"), []byte(testHDFSSite), 0o644))
+	conf, err := hadoopconf.Load(dir)
+	require.NoError(t, err)
+
+	parse := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		require.NoError(t, err)
+
+		return u
+	}
+
+	// the property takes priority, listing the namenodes to fail over to
+	assert.Equal(t, []string{"a:8020", "b:8020"}, hdfsNamenodes(parse("hdfs://ns1/warehouse"),
+		map[string]string{HDFSNameNode: "a:8020, b:8020"}, conf))
+	// nameservices are resolved with the hadoop configuration
+	assert.Equal(t, []string{"host1:8020", "host2:8020"}, hdfsNamenodes(parse("hdfs://ns1/warehouse"), nil, conf))
+	assert.Equal(t, []string{"other:9000"}, hdfsNamenodes(parse("hdfs://other:9000/warehouse"), nil, conf))
+	// the default file system is used without a host
+	assert.Equal(t, []string{"host1:8020", "host2:8020"}, hdfsNamenodes(parse("/warehouse"), nil, conf))
+	assert.Equal(t, []string{"other:9000"}, hdfsNamenodes(parse("hdfs://other:9000/warehouse"), nil, nil))
+	assert.Empty(t, hdfsNamenodes(parse("/warehouse"), nil, nil))
+}
+
+// listenNamenode returns the address of a listener standing in for a
+// namenode, the clients connect without reading from it unless kerberos
+// is enabled. The number of accepted connections is returned along.
+func listenNamenode(t *testing.T) (string, *atomic.Int32) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	var conns atomic.Int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns.Add(1)
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	return ln.Addr().String(), &conns
+}
+
+func TestHDFSClientPool(t *testing.T) {
+	t.Setenv("HADOOP_CONF_DIR", "")
+	t.Setenv("HADOOP_HOME", "")
+
+	addr, conns := listenNamenode(t)
+	props := map[string]string{HDFSNameNode: addr, HDFSUser: "iceberg"}
+
+	first, err := createHDFSFS(nil, props)
+	require.NoError(t, err)
+	second, err := createHDFSFS(nil, props)
+	require.NoError(t, err)
+
+	// the instances of the same key share the client
+	fs1, fs2 := first.(*HdfsFS), second.(*HdfsFS)
+	assert.Same(t, fs1.client, fs2.client)
+	assert.Equal(t, fs1.Key(), fs2.Key())
+	assert.Eventually(t, func() bool { return conns.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// the keytab and krb5 config are part of the key
+	_, key, err := hdfsClientOptions(nil, props)
+	require.NoError(t, err)
+	_, other, err := hdfsClientOptions(nil, map[string]string{
+		HDFSNameNode: addr, HDFSUser: "iceberg", HDFSKerberosKeytab: "/etc/other.keytab",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fs1.poolKey, key)
+	assert.NotEqual(t, key, other)
+
+	// the client is closed and evicted once all its instances are closed
+	require.NoError(t, fs1.Close())
+	require.NoError(t, fs1.Close())
+	hdfsPoolMutex.Lock()
+	assert.Contains(t, hdfsPool, key)
+	hdfsPoolMutex.Unlock()
+
+	require.NoError(t, fs2.Close())
+	hdfsPoolMutex.Lock()
+	assert.NotContains(t, hdfsPool, key)
+	hdfsPoolMutex.Unlock()
+
+	third, err := createHDFSFS(nil, props)
+	require.NoError(t, err)
+	defer third.(*HdfsFS).Close()
+	assert.NotSame(t, fs1.client, third.(*HdfsFS).client)
+}
+
+func TestHDFSKerberosWithoutPrincipal(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "core-site.xml"), []byte(`<configuration>
+  <property><name>hadoop.security.authentication</name><value>kerberos</value></property>
+</configuration>`), 0o644))
+	t.Setenv("HADOOP_CONF_DIR", dir)
+
+	_, err := createHDFSFS(&url.URL{Scheme: "hdfs", Host: "nn:8020"}, map[string]string{HDFSUser: "iceberg"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), HDFSKerberosPrincipal)
+}
@@ -23,11 +23,9 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
-	"net/url"
+	"iter"
 	"strings"
-
-	"gocloud.dev/blob"
-	"gocloud.dev/blob/memblob"
+	"time"
 )
 
 // IO is an interface to a hierarchical file system.
@@ -82,6 +80,54 @@ type WriteFileIO interface {
 	WriteFile(name string, p []byte) error
 }
 
+// FileInfo describes a file listed by a ListIO.
+type FileInfo struct {
+	// Location is the location of the file, it can be passed to Open
+	// and Remove.
+	Location string
+	// Size is the size of the file in bytes.
+	Size int64
+	// ModTime is the time the file was last modified.
+	ModTime time.Time
+}
+
+// ListIO is the interface implemented by a file system that can
+// list the files under a location.
+type ListIO interface {
+	IO
+
+	// List returns the files under the location, which is treated as
+	// a directory, recursing into its subdirectories. The files are
+	// listed in no particular order, a location with no files under
+	// it, including one that doesn't exist, yields no files.
+	List(location string) iter.Seq2[FileInfo, error]
+}
+
+// BulkDeleteIO is the interface implemented by a file system that
+// can remove many files at once, using batch delete requests where
+// the underlying storage supports them.
+type BulkDeleteIO interface {
+	IO
+
+	// DeleteFiles removes the named files. It returns the names of the
+	// files which were removed, along with an error joining the errors
+	// of the ones which could not be.
+	DeleteFiles(names []string) ([]string, error)
+}
+
+// KeyedIO is the interface implemented by a file system that can
+// identify the storage it reads from and the credentials it uses, so
+// that the files read with it can be shared with file systems of the
+// same key, such as by the caches of tables.
+type KeyedIO interface {
+	IO
+
+	// Key returns the identity of the file system. File systems with
+	// the same key must read the same files with the same access. An
+	// empty key shares nothing with other file systems.
+	Key() string
+}
+
 // A File provides access to a single file. The File interface is the
 // minimum implementation required for Iceberg to interact with a file.
 // Directory files should also implement
@@ -235,53 +281,17 @@ func (f ioFile) ReadDir(count int) ([]fs.DirEntry, error) {
 	return d.ReadDir(count)
 }
 
-func inferFileIOFromSchema(ctx context.Context, path string, props map[string]string) (IO, error) {
-	parsed, err := url.Parse(path)
-	if err != nil {
-		return nil, err
-	}
-	var bucket *blob.Bucket
-
-	switch parsed.Scheme {
-	case "s3", "s3a", "s3n":
-		bucket, err = createS3Bucket(ctx, parsed, props)
-		if err != nil {
-			return nil, err
-		}
-		return createBlobFS(ctx, bucket, parsed.Host), nil
-	case "gs":
-		bucket, err = createGCSBucket(ctx, parsed, props)
-		if err != nil {
-			return nil, err
-		}
-		return createBlobFS(ctx, bucket, parsed.Host), nil
-	case "mem":
-		// memblob doesn't use the URL host or path
-		bucket = memblob.OpenBucket(nil)
-		return createBlobFS(ctx, bucket, parsed.Host), nil
-	case "file", "":
-		return LocalFS{}, nil
-	case "abfs", "abfss", "wasb", "wasbs":
-		bucket, err = createAzureBucket(ctx, parsed, props)
-		if err != nil {
-			return nil, err
-		}
-		return createBlobFS(ctx, bucket, parsed.Host), nil
-	case "hdfs":
-		return createHDFSFS(parsed, props)
-	default:
-		return nil, fmt.Errorf("IO for file '%s' not implemented", path)
-	}
-}
-
 // LoadFS takes a map of properties and an optional URI location
 // and attempts to infer an IO object from it.
 //
-// A schema of "file://" or an empty string will result in a LocalFS
-// implementation. Otherwise this will return an error if the schema
-// does not yet have an implementation here.
+// The IO is created by the factory registered for the scheme of the
+// location, or by the one registered with the name given by the IOImpl
+// property if it is set. A schema of "file://" or an empty string will
+// result in a LocalFS implementation. Otherwise this will return an
+// ErrIONotFound error if the schema has no registered implementation.
 //
-// Currently local, S3, GCS, and In-Memory FSs are implemented.
+// Currently local, S3, GCS, Azure, HDFS and In-Memory FSs are registered
+// by default, see Register to add other implementations.
 func LoadFS(ctx context.Context, props map[string]string, location string) (IO, error) {
 	if location == "" {
 		location = props["warehouse"]
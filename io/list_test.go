@@ -0,0 +1,102 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package io_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/apache/iceberg-go/io"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listLocations(t *testing.T, fs io.ListIO, location string) []string {
+	var out []string
+	for f, err := range fs.List(location) {
+		require.NoError(t, err)
+		assert.Positive(t, f.Size)
+		assert.False(t, f.ModTime.IsZero())
+		out = append(out, f.Location)
+	}
+	slices.Sort(out)
+
+	return out
+}
+
+func TestLocalFSListAndDelete(t *testing.T) {
+	dir := filepath.ToSlash(t.TempDir())
+	fs, err := io.LoadFS(context.Background(), nil, "file://"+dir)
+	require.NoError(t, err)
+
+	for _, name := range []string{"a.parquet", "data/b.parquet", "data/c/d.parquet"} {
+		w, err := fs.(io.WriteFileIO).Create("file://" + dir + "/table/" + name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("data"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+
+	lister, ok := fs.(io.ListIO)
+	require.True(t, ok)
+	assert.Equal(t, []string{
+		"file://" + dir + "/table/a.parquet",
+		"file://" + dir + "/table/data/b.parquet",
+		"file://" + dir + "/table/data/c/d.parquet",
+	}, listLocations(t, lister, "file://"+dir+"/table/"))
+	assert.Equal(t, []string{dir + "/table/data/b.parquet", dir + "/table/data/c/d.parquet"},
+		listLocations(t, lister, dir+"/table/data"))
+	assert.Empty(t, listLocations(t, lister, dir+"/missing"))
+
+	deleted, err := fs.(io.BulkDeleteIO).DeleteFiles([]string{
+		"file://" + dir + "/table/a.parquet",
+		dir + "/table/missing.parquet",
+		dir + "/table/data/b.parquet",
+	})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Equal(t, []string{"file://" + dir + "/table/a.parquet", dir + "/table/data/b.parquet"}, deleted)
+	assert.Equal(t, []string{dir + "/table/data/c/d.parquet"}, listLocations(t, lister, dir+"/table"))
+}
+
+func TestBlobFSListAndDelete(t *testing.T) {
+	fs, err := io.LoadFS(context.Background(), nil, "mem://bucket")
+	require.NoError(t, err)
+
+	for _, name := range []string{"table/a.parquet", "table/data/b.parquet", "tablex/c.parquet"} {
+		require.NoError(t, fs.(io.WriteFileIO).WriteFile("mem://bucket/"+name, []byte("data")))
+	}
+
+	lister, ok := fs.(io.ListIO)
+	require.True(t, ok)
+	assert.Equal(t, []string{
+		"mem://bucket/table/a.parquet",
+		"mem://bucket/table/data/b.parquet",
+	}, listLocations(t, lister, "mem://bucket/table"))
+	assert.Len(t, listLocations(t, lister, "mem://bucket"), 3)
+
+	deleted, err := fs.(io.BulkDeleteIO).DeleteFiles([]string{
+		"mem://bucket/table/a.parquet",
+		"mem://bucket/table/data/b.parquet",
+	})
+	require.NoError(t, err)
+	assert.Len(t, deleted, 2)
+	assert.Equal(t, []string{"mem://bucket/tablex/c.parquet"}, listLocations(t, lister, "mem://bucket/"))
+}
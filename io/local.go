@@ -18,6 +18,9 @@
 package io
 
 import (
+	"errors"
+	"io/fs"
+	"iter"
 	"os"
 	"path/filepath"
 	"strings"
@@ -47,3 +50,46 @@ func (LocalFS) WriteFile(name string, content []byte) error {
 func (LocalFS) Remove(name string) error {
 	return os.Remove(strings.TrimPrefix(name, "file://"))
 }
+
+// List returns the files under the directory at the location.
+func (LocalFS) List(location string) iter.Seq2[FileInfo, error] {
+	return func(yield func(FileInfo, error) bool) {
+		root := strings.TrimSuffix(strings.TrimPrefix(location, "file://"), "/")
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == root && errors.Is(err, fs.ErrNotExist) {
+					return fs.SkipAll
+				}
+
+				return err
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			if !yield(FileInfo{
+				Location: listedLocation(strings.TrimSuffix(location, "/"), root, filepath.ToSlash(path)),
+				Size:     info.Size(),
+				ModTime:  info.ModTime(),
+			}, nil) {
+				return fs.SkipAll
+			}
+
+			return nil
+		})
+		if err != nil {
+			yield(FileInfo{}, err)
+		}
+	}
+}
+
+// DeleteFiles removes the named files one by one.
+func (l LocalFS) DeleteFiles(names []string) ([]string, error) {
+	return deleteEach(names, l.Remove)
+}
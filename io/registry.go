@@ -0,0 +1,185 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package io
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sync"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/memblob"
+)
+
+// IOImpl is the property used to select a registered IO implementation by
+// name, rather than by the scheme of the location it is loaded for.
+const IOImpl = "io-impl"
+
+var ErrIONotFound = errors.New("io implementation not found")
+
+type registry map[string]Registrar
+
+func (r registry) getKeys() []string {
+	regMutex.Lock()
+	defer regMutex.Unlock()
+
+	return slices.Collect(maps.Keys(r))
+}
+
+func (r registry) set(scheme string, reg Registrar) {
+	regMutex.Lock()
+	defer regMutex.Unlock()
+	r[scheme] = reg
+}
+
+func (r registry) get(scheme string) (Registrar, bool) {
+	regMutex.Lock()
+	defer regMutex.Unlock()
+	reg, ok := r[scheme]
+
+	return reg, ok
+}
+
+func (r registry) remove(scheme string) {
+	regMutex.Lock()
+	defer regMutex.Unlock()
+	delete(r, scheme)
+}
+
+var (
+	regMutex        sync.Mutex
+	defaultRegistry = registry{}
+)
+
+// Registrar is a factory for creating IO instances, used for registering
+// to use with LoadFS. The location is the parsed URI the IO is loaded for.
+type Registrar interface {
+	GetIO(ctx context.Context, location *url.URL, props map[string]string) (IO, error)
+}
+
+type RegistrarFunc func(context.Context, *url.URL, map[string]string) (IO, error)
+
+func (f RegistrarFunc) GetIO(ctx context.Context, location *url.URL, props map[string]string) (IO, error) {
+	return f(ctx, location, props)
+}
+
+// Register adds the IO factory for the scheme to the registry. If the scheme
+// is already registered, it will be replaced. The scheme can also be any name
+// to be selected with the IOImpl property.
+func Register(scheme string, reg Registrar) {
+	if reg == nil {
+		panic("io: Register io factory is nil")
+	}
+	defaultRegistry.set(scheme, reg)
+}
+
+// Unregister removes the requested IO factory from the registry.
+func Unregister(scheme string) {
+	defaultRegistry.remove(scheme)
+}
+
+// GetRegisteredSchemes returns the list of registered schemes and names that
+// can be looked up via LoadFS.
+func GetRegisteredSchemes() []string {
+	return defaultRegistry.getKeys()
+}
+
+func init() {
+	Register("file", RegistrarFunc(func(context.Context, *url.URL, map[string]string) (IO, error) {
+		return LocalFS{}, nil
+	}))
+	Register("", RegistrarFunc(func(context.Context, *url.URL, map[string]string) (IO, error) {
+		return LocalFS{}, nil
+	}))
+	Register("mem", RegistrarFunc(func(ctx context.Context, parsed *url.URL, _ map[string]string) (IO, error) {
+		// memblob doesn't use the URL host or path, and every bucket is a
+		// different store
+		return createBlobFS(ctx, memblob.OpenBucket(nil), parsed.Host, ""), nil
+	}))
+
+	s3 := blobRegistrar(createS3Bucket)
+	for _, scheme := range []string{"s3", "s3a", "s3n"} {
+		Register(scheme, s3)
+	}
+
+	Register("gs", blobRegistrar(createGCSBucket))
+
+	azure := blobRegistrar(createAzureBucket)
+	for _, scheme := range []string{"abfs", "abfss", "wasb", "wasbs"} {
+		Register(scheme, azure)
+	}
+
+	Register("hdfs", RegistrarFunc(func(_ context.Context, parsed *url.URL, props map[string]string) (IO, error) {
+		return createHDFSFS(parsed, props)
+	}))
+}
+
+// blobRegistrar returns a Registrar creating a blob backed IO for the bucket
+// of the location.
+func blobRegistrar(createBucket func(context.Context, *url.URL, map[string]string) (*blob.Bucket, error)) Registrar {
+	return RegistrarFunc(func(ctx context.Context, parsed *url.URL, props map[string]string) (IO, error) {
+		bucket, err := createBucket(ctx, parsed, props)
+		if err != nil {
+			return nil, err
+		}
+
+		return createBlobFS(ctx, bucket, parsed.Host, blobKey(parsed, props)), nil
+	})
+}
+
+// blobKey identifies the bucket of the location along with the properties
+// the IO is created with, which hold the endpoint and credentials of the
+// object store. The properties are hashed to keep the credentials out of
+// the key.
+func blobKey(parsed *url.URL, props map[string]string) string {
+	h := sha256.New()
+	for _, k := range slices.Sorted(maps.Keys(props)) {
+		fmt.Fprintf(h, "%q=%q\n", k, props[k])
+	}
+
+	return parsed.Scheme + "://" + parsed.Host + "#" + hex.EncodeToString(h.Sum(nil))
+}
+
+func inferFileIOFromSchema(ctx context.Context, path string, props map[string]string) (IO, error) {
+	parsed, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if impl := props[IOImpl]; impl != "" {
+		reg, ok := defaultRegistry.get(impl)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrIONotFound, impl)
+		}
+
+		return reg.GetIO(ctx, parsed, props)
+	}
+
+	reg, ok := defaultRegistry.get(parsed.Scheme)
+	if !ok {
+		return nil, fmt.Errorf("%w: IO for file '%s' not implemented", ErrIONotFound, path)
+	}
+
+	return reg.GetIO(ctx, parsed, props)
+}
@@ -0,0 +1,101 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package io_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/apache/iceberg-go/io"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIORegistry(t *testing.T) {
+	ctx := context.Background()
+	assert.ElementsMatch(t, []string{
+		"", "file", "mem", "s3", "s3a", "s3n", "gs",
+		"abfs", "abfss", "wasb", "wasbs", "hdfs",
+	}, io.GetRegisteredSchemes())
+
+	var calls int
+	io.Register("foobar", io.RegistrarFunc(func(ctx context.Context, location *url.URL, props map[string]string) (io.IO, error) {
+		calls++
+		assert.Equal(t, "bucket", location.Host)
+		assert.Equal(t, "baz", props["foo"])
+
+		return io.LocalFS{}, nil
+	}))
+	defer io.Unregister("foobar")
+
+	fs, err := io.LoadFS(ctx, map[string]string{"foo": "baz"}, "foobar://bucket/path")
+	require.NoError(t, err)
+	assert.Equal(t, io.LocalFS{}, fs)
+	assert.Equal(t, 1, calls)
+
+	// the io-impl property takes priority over the scheme of the location
+	fs, err = io.LoadFS(ctx, map[string]string{"foo": "baz", io.IOImpl: "foobar"}, "s3://bucket/path")
+	require.NoError(t, err)
+	assert.Equal(t, io.LocalFS{}, fs)
+	assert.Equal(t, 2, calls)
+
+	_, err = io.LoadFS(ctx, map[string]string{io.IOImpl: "unknown"}, "s3://bucket/path")
+	assert.ErrorIs(t, err, io.ErrIONotFound)
+
+	_, err = io.LoadFS(ctx, nil, "unknown://bucket/path")
+	assert.ErrorIs(t, err, io.ErrIONotFound)
+
+	fs, err = io.LoadFS(ctx, nil, "file:///tmp/path")
+	require.NoError(t, err)
+	assert.Equal(t, io.LocalFS{}, fs)
+}
+
+func TestIORegistryPanic(t *testing.T) {
+	assert.PanicsWithValue(t, "io: Register io factory is nil", func() { io.Register("foobar", nil) })
+}
+
+func TestIOKeys(t *testing.T) {
+	ctx := context.Background()
+	key := func(props map[string]string, location string) string {
+		fs, err := io.LoadFS(ctx, props, location)
+		require.NoError(t, err)
+		require.Implements(t, (*io.KeyedIO)(nil), fs)
+
+		return fs.(io.KeyedIO).Key()
+	}
+
+	props := map[string]string{
+		io.S3Region:          "us-east-1",
+		io.S3AccessKeyID:     "access-key",
+		io.S3SecretAccessKey: "secret-key",
+	}
+	k := key(props, "s3://bucket/path")
+	assert.NotEmpty(t, k)
+	assert.NotContains(t, k, "secret-key")
+	assert.Equal(t, k, key(props, "s3://bucket/other/path"))
+	assert.NotEqual(t, k, key(props, "s3://other-bucket/path"))
+	assert.NotEqual(t, k, key(map[string]string{
+		io.S3Region:          "us-east-1",
+		io.S3AccessKeyID:     "other-access-key",
+		io.S3SecretAccessKey: "secret-key",
+	}, "s3://bucket/path"))
+
+	// every in memory bucket is a different store
+	assert.Empty(t, key(nil, "mem://bucket/path"))
+}
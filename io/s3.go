@@ -48,11 +48,13 @@ const (
 	S3ConnectTimeout         = "s3.connect-timeout"
 	S3SignerUri              = "s3.signer.uri"
 	S3ForceVirtualAddressing = "s3.force-virtual-addressing"
+	// S3SessionTokenExpiresAtMs is the time the vended session token
+	// expires, in milliseconds since the epoch.
+	S3SessionTokenExpiresAtMs = "s3.session-token-expires-at-ms"
 )
 
 var unsupportedS3Props = []string{
 	S3ConnectTimeout,
-	S3SignerUri,
 }
 
 // ParseAWSConfig parses S3 properties and returns a configuration.
@@ -133,12 +135,25 @@ func createS3Bucket(ctx context.Context, parsed *url.URL, props map[string]strin
 		}
 	}
 
+	var signer *remoteSigner
+	if props[S3SignerUri] != "" {
+		signer, err = newRemoteSigner(props, awscfg.Region)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	client := s3.NewFromConfig(*awscfg, func(o *s3.Options) {
 		if endpoint != "" {
 			o.BaseEndpoint = aws.String(endpoint)
 		}
 		o.UsePathStyle = usePathStyle
 		o.DisableLogOutputChecksumValidationSkipped = true
+		if signer != nil {
+			// the requests are signed remotely rather than by the SDK
+			o.Credentials = aws.AnonymousCredentials{}
+			o.APIOptions = append(o.APIOptions, signer.addTo)
+		}
 	})
 
 	// Create a *blob.Bucket.
@@ -0,0 +1,253 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package io
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+const (
+	// S3SignerEndpoint is the path of the signing endpoint relative to the
+	// S3SignerUri, defaulting to S3SignerEndpointDefault.
+	S3SignerEndpoint        = "s3.signer.endpoint"
+	S3SignerEndpointDefault = "v1/aws/s3/sign"
+)
+
+const (
+	// s3SignatureCacheTTL is how long signatures are reused for requests of
+	// the same shape, well within the 15 minutes S3 accepts signed requests.
+	s3SignatureCacheTTL = 5 * time.Minute
+	// s3SignatureCacheSize bounds the number of cached signatures.
+	s3SignatureCacheSize = 1000
+	// unsignedPayload is sent as the payload hash so that the signature
+	// doesn't depend on the body of the request.
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+type s3SignRequest struct {
+	Region  string              `json:"region"`
+	URI     string              `json:"uri"`
+	Method  string              `json:"method"`
+	Headers map[string][]string `json:"headers"`
+}
+
+type s3SignResponse struct {
+	URI     string              `json:"uri"`
+	Headers map[string][]string `json:"headers"`
+}
+
+type cachedSignature struct {
+	rsp     s3SignResponse
+	expires time.Time
+}
+
+// remoteSigner is an S3 client middleware signing each request with a
+// remote signing endpoint, such as the one of a REST catalog, rather
+// than with local credentials. The client must use anonymous credentials
+// so that the requests aren't signed by the SDK first.
+type remoteSigner struct {
+	client   *http.Client
+	endpoint string
+	token    string
+	region   string
+
+	mx    sync.Mutex
+	cache map[string]cachedSignature
+}
+
+func newRemoteSigner(props map[string]string, region string) (*remoteSigner, error) {
+	base, err := url.Parse(props[S3SignerUri])
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 signer uri '%s': %w", props[S3SignerUri], err)
+	}
+
+	endpoint := props[S3SignerEndpoint]
+	if endpoint == "" {
+		endpoint = S3SignerEndpointDefault
+	}
+
+	return &remoteSigner{
+		client:   &http.Client{Transport: http.DefaultTransport},
+		endpoint: base.JoinPath(endpoint).String(),
+		token:    props["token"],
+		region:   region,
+		cache:    make(map[string]cachedSignature),
+	}, nil
+}
+
+func (*remoteSigner) ID() string { return "RemoteSigning" }
+
+// addTo adds the signer to the end of the finalize step of the stack, so
+// that it runs for every attempt of a request.
+func (s *remoteSigner) addTo(stack *middleware.Stack) error {
+	return stack.Finalize.Add(s, middleware.After)
+}
+
+func (s *remoteSigner) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	req, ok := in.Request.(*smithyhttp.Request)
+	if !ok {
+		return middleware.FinalizeOutput{}, middleware.Metadata{},
+			fmt.Errorf("unexpected request type %T to sign", in.Request)
+	}
+
+	if err := s.sign(ctx, req.Request); err != nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+	}
+
+	return next.HandleFinalize(ctx, in)
+}
+
+// signedHeaders returns the headers of the request which are sent to be
+// signed, leaving out the ones the SDK changes on every attempt.
+func signedHeaders(hdr http.Header) map[string][]string {
+	out := make(map[string][]string, len(hdr))
+	for k, v := range hdr {
+		if strings.HasPrefix(strings.ToLower(k), "amz-sdk-") {
+			continue
+		}
+		out[k] = v
+	}
+
+	return out
+}
+
+// cacheKey identifies the shape of a signing request, the requests with
+// the same method, uri and signed headers share their signature.
+func (r s3SignRequest) cacheKey() string {
+	var b strings.Builder
+	b.WriteString(r.Method)
+	b.WriteByte(' ')
+	b.WriteString(r.URI)
+
+	keys := make([]string, 0, len(r.Headers))
+	for k := range r.Headers {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		b.WriteByte('\n')
+		b.WriteString(k)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(r.Headers[k], ","))
+	}
+
+	return b.String()
+}
+
+func (s *remoteSigner) sign(ctx context.Context, req *http.Request) error {
+	if req.Header.Get("X-Amz-Content-Sha256") == "" {
+		req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	}
+
+	signReq := s3SignRequest{
+		Region:  s.region,
+		URI:     req.URL.String(),
+		Method:  req.Method,
+		Headers: signedHeaders(req.Header),
+	}
+
+	rsp, err := s.signature(ctx, signReq)
+	if err != nil {
+		return err
+	}
+
+	if rsp.URI != "" && rsp.URI != signReq.URI {
+		if req.URL, err = url.Parse(rsp.URI); err != nil {
+			return fmt.Errorf("invalid signed uri '%s': %w", rsp.URI, err)
+		}
+		req.Host = req.URL.Host
+	}
+
+	for k, v := range rsp.Headers {
+		req.Header[http.CanonicalHeaderKey(k)] = v
+	}
+
+	return nil
+}
+
+// signature returns the signature for the request from the cache, or else
+// from the signing endpoint.
+func (s *remoteSigner) signature(ctx context.Context, signReq s3SignRequest) (s3SignResponse, error) {
+	key := signReq.cacheKey()
+
+	s.mx.Lock()
+	cached, ok := s.cache[key]
+	s.mx.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.rsp, nil
+	}
+
+	body, err := json.Marshal(signReq)
+	if err != nil {
+		return s3SignResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return s3SignResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	httpRsp, err := s.client.Do(req)
+	if err != nil {
+		return s3SignResponse{}, fmt.Errorf("failed to sign s3 request: %w", err)
+	}
+	defer httpRsp.Body.Close()
+
+	if httpRsp.StatusCode != http.StatusOK {
+		return s3SignResponse{}, fmt.Errorf("failed to sign s3 request: signer responded with %s",
+			httpRsp.Status)
+	}
+
+	var rsp s3SignResponse
+	if err := json.NewDecoder(httpRsp.Body).Decode(&rsp); err != nil {
+		return s3SignResponse{}, fmt.Errorf("failed to decode s3 signer response: %w", err)
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	now := time.Now()
+	if len(s.cache) >= s3SignatureCacheSize {
+		for k, v := range s.cache {
+			if now.After(v.expires) {
+				delete(s.cache, k)
+			}
+		}
+		if len(s.cache) >= s3SignatureCacheSize {
+			clear(s.cache)
+		}
+	}
+	s.cache[key] = cachedSignature{rsp: rsp, expires: now.Add(s3SignatureCacheTTL)}
+
+	return rsp, nil
+}
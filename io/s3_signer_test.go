@@ -0,0 +1,144 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package io_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const remoteSignature = "AWS4-HMAC-SHA256 Credential=remote/20250101/us-east-1/s3/aws4_request"
+
+// fakeS3 is a minimal stand-in for an S3 compatible store serving path
+// style requests, which only accepts requests signed by the remote signer.
+type fakeS3 struct {
+	mx      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != remoteSignature {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 00:00:00 GMT")
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3RemoteSigning(t *testing.T) {
+	t.Setenv("AWS_REQUEST_CHECKSUM_CALCULATION", "when_required")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	store := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+	defer store.Close()
+
+	var (
+		mx    sync.Mutex
+		calls = map[string]int{}
+	)
+	signer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/catalog/v1/aws/s3/sign", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var req struct {
+			Region  string              `json:"region"`
+			URI     string              `json:"uri"`
+			Method  string              `json:"method"`
+			Headers map[string][]string `json:"headers"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "us-east-1", req.Region)
+		assert.True(t, strings.HasPrefix(req.URI, store.URL+"/bucket/"))
+		assert.NotEmpty(t, req.Headers["X-Amz-Content-Sha256"])
+
+		mx.Lock()
+		calls[req.Method]++
+		mx.Unlock()
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"uri": req.URI,
+			"headers": map[string][]string{
+				"Authorization": {remoteSignature},
+				"X-Amz-Date":    {"20250101T000000Z"},
+			},
+		})
+	}))
+	defer signer.Close()
+
+	fs, err := iceio.LoadFS(context.Background(), map[string]string{
+		iceio.S3SignerUri:   signer.URL + "/catalog",
+		iceio.S3EndpointURL: store.URL,
+		iceio.S3Region:      "us-east-1",
+		"token":             "secret",
+	}, "s3://bucket/table")
+	require.NoError(t, err)
+
+	require.NoError(t, fs.(iceio.WriteFileIO).WriteFile("s3://bucket/table/data.parquet", []byte("contents")))
+
+	for range 2 {
+		f, err := fs.Open("s3://bucket/table/data.parquet")
+		require.NoError(t, err)
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Equal(t, "contents", string(data))
+	}
+
+	// the signature of the second read is reused
+	assert.Equal(t, 1, calls[http.MethodPut])
+	assert.Equal(t, 1, calls[http.MethodGet])
+
+	require.NoError(t, fs.Remove("s3://bucket/table/data.parquet"))
+	_, err = fs.Open("s3://bucket/table/data.parquet")
+	assert.Error(t, err)
+}
@@ -17,7 +17,10 @@
 
 package io
 
-import "strings"
+import (
+	"errors"
+	"strings"
+)
 
 func propertiesWithPrefix(props map[string]string, prefix string) map[string]string {
 	result := map[string]string{}
@@ -29,3 +32,33 @@ func propertiesWithPrefix(props map[string]string, prefix string) map[string]str
 
 	return result
 }
+
+// deleteEach removes the named files one by one, for the file systems
+// without batch delete requests.
+func deleteEach(names []string, remove func(string) error) ([]string, error) {
+	var (
+		deleted = make([]string, 0, len(names))
+		errs    []error
+	)
+	for _, name := range names {
+		if err := remove(name); err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+		deleted = append(deleted, name)
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+// listedLocation returns the location of a listed file from the location
+// which was listed and the path it was preprocessed into, so that the
+// listed files keep the scheme and authority of the listed location.
+func listedLocation(location, listed, path string) string {
+	if base, ok := strings.CutSuffix(location, listed); ok {
+		return base + path
+	}
+
+	return path
+}
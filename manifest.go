@@ -153,6 +153,12 @@ func (b *ManifestBuilder) KeyMetadata(km []byte) *ManifestBuilder {
 	return b
 }
 
+func (b *ManifestBuilder) FirstRowID(id int64) *ManifestBuilder {
+	b.m.FirstRow = &id
+
+	return b
+}
+
 func (b *ManifestBuilder) Build() ManifestFile {
 	return b.m
 }
@@ -323,6 +329,7 @@ type manifestFile struct {
 	DeletedRowsCount   int64           `avro:"deleted_rows_count"`
 	PartitionList      *[]FieldSummary `avro:"partitions"`
 	Key                []byte          `avro:"key_metadata"`
+	FirstRow           *int64          `avro:"first_row_id"`
 
 	version int `avro:"-"`
 }
@@ -391,6 +398,7 @@ func (m *manifestFile) DeletedRows() int64               { return m.DeletedRowsC
 func (m *manifestFile) SequenceNum() int64               { return m.SeqNumber }
 func (m *manifestFile) MinSequenceNum() int64            { return m.MinSeqNumber }
 func (m *manifestFile) KeyMetadata() []byte              { return m.Key }
+func (m *manifestFile) FirstRowID() *int64               { return m.FirstRow }
 func (m *manifestFile) Partitions() []FieldSummary {
 	if m.PartitionList == nil {
 		return nil
@@ -465,10 +473,10 @@ func fetchManifestEntries(m ManifestFile, fs iceio.IO, discardDeleted bool) ([]M
 	return entries, err
 }
 
-// ManifestFile is the interface which covers both V1 and V2 manifest files.
+// ManifestFile is the interface which covers V1, V2 and V3 manifest files.
 type ManifestFile interface {
 	// Version returns the version number of this manifest file.
-	// It should be 1 or 2.
+	// It should be 1, 2 or 3.
 	Version() int
 	// FilePath is the location URI of this manifest file.
 	FilePath() string
@@ -511,6 +519,10 @@ type ManifestFile interface {
 	// KeyMetadata returns implementation-specific key metadata for encryption
 	// if it exists in the manifest list.
 	KeyMetadata() []byte
+	// FirstRowID returns the first row id assigned to rows of data files
+	// added in this manifest that have no first row id of their own. It
+	// is only set for v3 data manifests.
+	FirstRowID() *int64
 	// Partitions returns a list of field summaries for each partition
 	// field in the spec. Each field in the list corresponds to a field in
 	// the manifest file's partition spec.
@@ -582,6 +594,9 @@ type ManifestReader struct {
 	content       ManifestContent
 	fieldNameToID map[string]int
 	fieldIDToType map[int]avro.LogicalType
+	// nextRowID is the row id assigned to the next live data file
+	// without a first_row_id, nil if the manifest has no row lineage.
+	nextRowID *int64
 
 	// The rest are lazily populated, on demand. Most readers
 	// will likely only try to load the entries.
@@ -641,6 +656,12 @@ func NewManifestReader(file ManifestFile, in io.Reader) (*ManifestReader, error)
 	}
 	fieldNameToID, fieldIDToType := getFieldIDMap(sc)
 
+	var nextRowID *int64
+	if first := file.FirstRowID(); first != nil && content == ManifestContentData {
+		id := *first
+		nextRowID = &id
+	}
+
 	return &ManifestReader{
 		dec:           dec,
 		file:          file,
@@ -649,6 +670,7 @@ func NewManifestReader(file ManifestFile, in io.Reader) (*ManifestReader, error)
 		content:       content,
 		fieldNameToID: fieldNameToID,
 		fieldIDToType: fieldIDToType,
+		nextRowID:     nextRowID,
 	}, nil
 }
 
@@ -745,6 +767,13 @@ func (c *ManifestReader) ReadEntry() (ManifestEntry, error) {
 		tmp = tmp.(*fallbackManifestEntry).toEntry()
 	}
 	tmp.inherit(c.file)
+	if c.nextRowID != nil && tmp.Status() != EntryStatusDELETED {
+		if df := tmp.DataFile().(*dataFile); df.FirstRow == nil {
+			id := *c.nextRowID
+			df.FirstRow = &id
+			*c.nextRowID += df.RecordCount
+		}
+	}
 	if fieldToIDMap, ok := tmp.DataFile().(hasFieldToIDMap); ok {
 		fieldToIDMap.setFieldNameToIDMap(c.fieldNameToID)
 		fieldToIDMap.setFieldIDToLogicalTypeMap(c.fieldIDToType)
@@ -855,6 +884,14 @@ func (v2writerImpl) prepareEntry(entry *manifestEntry, snapshotID int64) (Manife
 	return entry, nil
 }
 
+// v2DeleteWriterImpl writes manifests tracking position and equality
+// delete files, which only exist in format version 2 and later.
+type v2DeleteWriterImpl struct {
+	v2writerImpl
+}
+
+func (v2DeleteWriterImpl) content() ManifestContent { return ManifestContentDeletes }
+
 type fieldStats interface {
 	toSummary() FieldSummary
 	update(value any) error
@@ -1033,12 +1070,30 @@ func NewManifestWriter(version int, out io.Writer, spec PartitionSpec, schema *S
 	switch version {
 	case 1:
 		impl = v1writerImpl{}
-	case 2:
+	case 2, 3:
 		impl = v2writerImpl{}
 	default:
 		return nil, fmt.Errorf("unsupported manifest version: %d", version)
 	}
 
+	return newManifestWriter(impl, version, out, spec, schema, snapshotID)
+}
+
+// NewDeleteManifestWriter is like NewManifestWriter, but creates a writer
+// for a manifest tracking position or equality delete files rather than
+// data files. Delete files require format version 2 or later.
+func NewDeleteManifestWriter(version int, out io.Writer, spec PartitionSpec, schema *Schema, snapshotID int64) (*ManifestWriter, error) {
+	switch version {
+	case 1:
+		return nil, errors.New("delete manifests are not supported in format version 1")
+	case 2, 3:
+		return newManifestWriter(v2DeleteWriterImpl{}, version, out, spec, schema, snapshotID)
+	default:
+		return nil, fmt.Errorf("unsupported manifest version: %d", version)
+	}
+}
+
+func newManifestWriter(impl writerImpl, version int, out io.Writer, spec PartitionSpec, schema *Schema, snapshotID int64) (*ManifestWriter, error) {
 	sc, err := partitionTypeToAvroSchema(spec.PartitionType(schema))
 	if err != nil {
 		return nil, err
@@ -1069,7 +1124,8 @@ func NewManifestWriter(version int, out io.Writer, spec PartitionSpec, schema *S
 		ocf.WithSchemaMarshaler(ocf.FullSchemaMarshaler),
 		ocf.WithEncoderSchemaCache(&avro.SchemaCache{}),
 		ocf.WithMetadata(md),
-		ocf.WithCodec(ocf.Deflate))
+		ocf.WithCodec(ocf.Deflate),
+		ocf.WithEncodingConfig(manifestEncodingConfig))
 
 	w.writer = enc
 
@@ -1109,7 +1165,7 @@ func (w *ManifestWriter) ToManifestFile(location string, length int64) (Manifest
 		Path:               location,
 		Len:                length,
 		SpecID:             int32(w.spec.id),
-		Content:            ManifestContentData,
+		Content:            w.impl.content(),
 		SeqNumber:          -1,
 		MinSeqNumber:       w.minSeqNum,
 		AddedSnapshotID:    w.snapshotID,
@@ -1208,6 +1264,7 @@ type ManifestListWriter struct {
 	out              io.Writer
 	commitSnapshotID int64
 	sequenceNumber   int64
+	nextRowID        int64
 	writer           *ocf.Encoder
 }
 
@@ -1252,6 +1309,38 @@ func NewManifestListWriterV2(out io.Writer, snapshotID, sequenceNumber int64, pa
 	})
 }
 
+// NewManifestListWriterV3 is like NewManifestListWriterV2, but also assigns
+// row ids to the data manifests it writes, starting at firstRowID. After
+// writing, NextRowID reports the first row id not assigned by this writer.
+func NewManifestListWriterV3(out io.Writer, snapshotID, sequenceNumber, firstRowID int64, parentSnapshot *int64) (*ManifestListWriter, error) {
+	m := &ManifestListWriter{
+		version:          3,
+		out:              out,
+		commitSnapshotID: snapshotID,
+		sequenceNumber:   sequenceNumber,
+		nextRowID:        firstRowID,
+	}
+
+	parentSnapshotStr := "null"
+	if parentSnapshot != nil {
+		parentSnapshotStr = strconv.Itoa(int(*parentSnapshot))
+	}
+
+	return m, m.init(map[string][]byte{
+		"format-version":     []byte(strconv.Itoa(m.version)),
+		"snapshot-id":        []byte(strconv.Itoa(int(snapshotID))),
+		"sequence-number":    []byte(strconv.Itoa(int(sequenceNumber))),
+		"parent-snapshot-id": []byte(parentSnapshotStr),
+		"first-row-id":       []byte(strconv.FormatInt(firstRowID, 10)),
+	})
+}
+
+// NextRowID returns the row id following the last one assigned to the
+// manifests added so far. It is only meaningful for version 3 writers.
+func (m *ManifestListWriter) NextRowID() int64 {
+	return m.nextRowID
+}
+
 func (m *ManifestListWriter) init(meta map[string][]byte) error {
 	fileSchema, err := internal.NewManifestFileSchema(m.version)
 	if err != nil {
@@ -1301,10 +1390,11 @@ func (m *ManifestListWriter) AddManifests(files []ManifestFile) error {
 			}
 		}
 
-	case 2:
+	case 2, 3:
 		for _, file := range files {
-			if file.Version() != 2 {
-				return fmt.Errorf("%w: ManifestListWriter only supports version 2 manifest files", ErrInvalidArgument)
+			if file.Version() != m.version {
+				return fmt.Errorf("%w: ManifestListWriter only supports version %d manifest files",
+					ErrInvalidArgument, m.version)
 			}
 
 			wrapped := *(file.(*manifestFile))
@@ -1329,6 +1419,15 @@ func (m *ManifestListWriter) AddManifests(files []ManifestFile) error {
 				// the one for this commit
 				wrapped.MinSeqNumber = m.sequenceNumber
 			}
+
+			if m.version >= 3 && wrapped.Content == ManifestContentData && wrapped.FirstRow == nil {
+				// rows of data files added or kept by this manifest are
+				// assigned ids from the range starting at first_row_id
+				firstRowID := m.nextRowID
+				wrapped.FirstRow = &firstRowID
+				m.nextRowID += wrapped.AddedRowsCount + wrapped.ExistingRowsCount
+			}
+
 			if err := m.writer.Encode(wrapped); err != nil {
 				return err
 			}
@@ -1355,6 +1454,8 @@ func WriteManifestList(version int, out io.Writer, snapshotID int64, parentSnaps
 			return errors.New("sequence number is required for V2 tables")
 		}
 		writer, err = NewManifestListWriterV2(out, snapshotID, *sequenceNumber, parentSnapshotID)
+	case 3:
+		return errors.New("version 3 manifest lists require a first row id, use NewManifestListWriterV3")
 	default:
 		return fmt.Errorf("unsupported manifest version: %d", version)
 	}
@@ -1474,10 +1575,29 @@ func mapToAvroColMap[K comparable, V any](m map[K]V) *[]colMap[K, V] {
 	return &out
 }
 
+// manifestEncodingConfig resolves the nullable unions of partition fields
+// with a logical type from the Go types partition values are held in, both
+// the iceberg types and the raw ints of their physical representation.
+var manifestEncodingConfig = func() avro.API {
+	cfg := avro.Config{}.Freeze()
+	for _, v := range []any{int32(0), Date(0)} {
+		cfg.Register(string(avro.Int)+"."+string(avro.Date), v)
+	}
+	for _, v := range []any{int64(0), Time(0)} {
+		cfg.Register(string(avro.Long)+"."+string(avro.TimeMicros), v)
+	}
+	for _, v := range []any{int64(0), Timestamp(0)} {
+		cfg.Register(string(avro.Long)+"."+string(avro.TimestampMicros), v)
+	}
+	cfg.Register("uuid", uuid.UUID{})
+
+	return cfg
+}()
+
 func avroPartitionData(input map[int]any, logicalTypes map[int]avro.LogicalType) map[int]any {
 	out := make(map[int]any)
 	for k, v := range input {
-		if logical, ok := logicalTypes[k]; ok {
+		if logical, ok := logicalTypes[k]; ok && v != nil {
 			switch logical {
 			case avro.Date:
 				out[k] = Date(v.(time.Time).Truncate(24*time.Hour).Unix() / int64((time.Hour * 24).Seconds()))
@@ -1520,6 +1640,10 @@ type dataFile struct {
 	Splits           *[]int64               `avro:"split_offsets"`
 	EqualityIDs      *[]int                 `avro:"equality_ids"`
 	SortOrder        *int                   `avro:"sort_order_id"`
+	FirstRow         *int64                 `avro:"first_row_id"`
+	RefDataFile      *string                `avro:"referenced_data_file"`
+	ContentOff       *int64                 `avro:"content_offset"`
+	ContentSize      *int64                 `avro:"content_size_in_bytes"`
 
 	colSizeMap     map[int]int64
 	valCntMap      map[int]int64
@@ -1646,7 +1770,11 @@ func (d *dataFile) EqualityFieldIDs() []int {
 	return *d.EqualityIDs
 }
 
-func (d *dataFile) SortOrderID() *int { return d.SortOrder }
+func (d *dataFile) SortOrderID() *int           { return d.SortOrder }
+func (d *dataFile) FirstRowID() *int64          { return d.FirstRow }
+func (d *dataFile) ReferencedDataFile() *string { return d.RefDataFile }
+func (d *dataFile) ContentOffset() *int64       { return d.ContentOff }
+func (d *dataFile) ContentSizeInBytes() *int64  { return d.ContentSize }
 
 type ManifestEntryBuilder struct {
 	m *manifestEntry
@@ -1915,6 +2043,29 @@ func (b *DataFileBuilder) SortOrderID(id int) *DataFileBuilder {
 	return b
 }
 
+// FirstRowID sets the row id of the first row in the data file.
+func (b *DataFileBuilder) FirstRowID(id int64) *DataFileBuilder {
+	b.d.FirstRow = &id
+
+	return b
+}
+
+// ReferencedDataFile sets the location of the data file that all the
+// deletes in this delete file apply to.
+func (b *DataFileBuilder) ReferencedDataFile(path string) *DataFileBuilder {
+	b.d.RefDataFile = &path
+
+	return b
+}
+
+// ContentOffset sets the offset and size in bytes of the content, such as
+// a deletion vector, stored within the file.
+func (b *DataFileBuilder) ContentOffset(offset, size int64) *DataFileBuilder {
+	b.d.ContentOff, b.d.ContentSize = &offset, &size
+
+	return b
+}
+
 func (b *DataFileBuilder) Build() DataFile {
 	return b.d
 }
@@ -1979,6 +2130,19 @@ type DataFile interface {
 	// SortOrderID returns the id representing the sort order for this
 	// file, or nil if there is no sort order.
 	SortOrderID() *int
+	// FirstRowID returns the row id of the first row in the data file,
+	// either written in the manifest or inherited from the manifest's
+	// first row id. It is nil for files of tables before format version 3.
+	FirstRowID() *int64
+	// ReferencedDataFile returns the location of the data file that all
+	// of the deletes in this delete file reference, if any.
+	ReferencedDataFile() *string
+	// ContentOffset returns the offset in the file at which the content,
+	// such as a deletion vector, starts. Only set for v3 delete vectors.
+	ContentOffset() *int64
+	// ContentSizeInBytes returns the length of the content referenced by
+	// ContentOffset.
+	ContentSizeInBytes() *int64
 	// SpecID returns the partition spec id for this data file, inherited
 	// from the manifest that the data file was read from
 	SpecID() int32
@@ -2011,5 +2175,5 @@ type ManifestEntry interface {
 
 var PositionalDeleteSchema = NewSchema(0,
 	NestedField{ID: 2147483546, Type: PrimitiveTypes.String, Name: "file_path", Required: true},
-	NestedField{ID: 2147483545, Type: PrimitiveTypes.Int32, Name: "pos", Required: true},
+	NestedField{ID: 2147483545, Type: PrimitiveTypes.Int64, Name: "pos", Required: true},
 )
@@ -1157,6 +1157,91 @@ func (m *ManifestTestSuite) TestManifestEntryBuilder() {
 	m.Assert().Equal(0, *data.SortOrderID())
 }
 
+func (m *ManifestTestSuite) TestManifestRowLineageV3() {
+	sch := NewSchema(0, NestedField{ID: 1, Name: "id", Type: Int64Type{}, Required: true})
+	newEntry := func(path string, count int64, firstRowID *int64) ManifestEntry {
+		bldr, err := NewDataFileBuilder(*UnpartitionedSpec, EntryContentData, path,
+			ParquetFile, nil, count, 100)
+		m.Require().NoError(err)
+		if firstRowID != nil {
+			bldr.FirstRowID(*firstRowID)
+		}
+
+		return NewManifestEntryBuilder(EntryStatusADDED, &snapshotID, bldr.Build()).Build()
+	}
+
+	var manifestBuf bytes.Buffer
+	keptRowID := int64(7)
+	mf, err := WriteManifest("/manifest-v3.avro", &manifestBuf, 3, *UnpartitionedSpec, sch, snapshotID,
+		[]ManifestEntry{newEntry("a.parquet", 5, nil), newEntry("b.parquet", 2, &keptRowID), newEntry("c.parquet", 3, nil)})
+	m.Require().NoError(err)
+	m.Nil(mf.FirstRowID())
+
+	var listBuf bytes.Buffer
+	wr, err := NewManifestListWriterV3(&listBuf, snapshotID, 1, 100, nil)
+	m.Require().NoError(err)
+	m.Require().NoError(wr.AddManifests([]ManifestFile{mf}))
+	m.Require().NoError(wr.Close())
+	m.EqualValues(110, wr.NextRowID())
+
+	list, err := ReadManifestList(&listBuf)
+	m.Require().NoError(err)
+	m.Require().Len(list, 1)
+	m.Equal(3, list[0].Version())
+	m.Require().NotNil(list[0].FirstRowID())
+	m.EqualValues(100, *list[0].FirstRowID())
+
+	entries, err := ReadManifest(list[0], &manifestBuf, false)
+	m.Require().NoError(err)
+	m.Require().Len(entries, 3)
+
+	firstRowIDs := make([]int64, len(entries))
+	for i, e := range entries {
+		m.Require().NotNil(e.DataFile().FirstRowID())
+		firstRowIDs[i] = *e.DataFile().FirstRowID()
+	}
+	// files without a first row id are assigned consecutive ranges
+	m.Equal([]int64{100, 7, 105}, firstRowIDs)
+
+	m.ErrorContains(WriteManifestList(3, io.Discard, snapshotID, nil, nil, list), "NewManifestListWriterV3")
+}
+
+func (m *ManifestTestSuite) TestManifestNullablePartitionValues() {
+	sch := NewSchema(0,
+		NestedField{ID: 1, Name: "dt", Type: DateType{}},
+		NestedField{ID: 2, Name: "ts", Type: TimestampType{}},
+		NestedField{ID: 3, Name: "category", Type: StringType{}})
+	spec := NewPartitionSpec(
+		PartitionField{SourceID: 1, FieldID: 1000, Transform: IdentityTransform{}, Name: "dt"},
+		PartitionField{SourceID: 2, FieldID: 1001, Transform: IdentityTransform{}, Name: "ts"},
+		PartitionField{SourceID: 3, FieldID: 1002, Transform: IdentityTransform{}, Name: "category"})
+
+	newEntry := func(path string, partition map[int]any) ManifestEntry {
+		bldr, err := NewDataFileBuilder(spec, EntryContentData, path, ParquetFile, partition, 1, 100)
+		m.Require().NoError(err)
+
+		return NewManifestEntryBuilder(EntryStatusADDED, &snapshotID, bldr.Build()).Build()
+	}
+
+	var buf bytes.Buffer
+	mf, err := WriteManifest("/manifest-nullable.avro", &buf, 2, spec, sch, snapshotID, []ManifestEntry{
+		newEntry("typed.parquet", map[int]any{1000: Date(19800), 1001: Timestamp(1710000000000000), 1002: "a"}),
+		newEntry("raw.parquet", map[int]any{1000: int32(19801), 1001: int64(1710000000000001), 1002: "b"}),
+		newEntry("null.parquet", map[int]any{1000: nil, 1001: nil, 1002: nil}),
+	})
+	m.Require().NoError(err)
+
+	entries, err := ReadManifest(mf, &buf, false)
+	m.Require().NoError(err)
+	m.Require().Len(entries, 3)
+
+	m.Equal(map[int]any{1000: Date(19800), 1001: Timestamp(1710000000000000), 1002: "a"},
+		entries[0].DataFile().Partition())
+	m.Equal(map[int]any{1000: Date(19801), 1001: Timestamp(1710000000000001), 1002: "b"},
+		entries[1].DataFile().Partition())
+	m.Equal(map[int]any{1000: nil, 1001: nil, 1002: nil}, entries[2].DataFile().Partition())
+}
+
 func (m *ManifestTestSuite) TestManifestWriterMeta() {
 	sch := NewSchema(0, NestedField{ID: 0, Name: "test01", Type: StringType{}})
 	w, err := NewManifestWriter(2, io.Discard, *UnpartitionedSpec, sch, 1)
@@ -476,9 +476,13 @@ type SchemaVisitorPerPrimitiveType[T any] interface {
 	VisitTime() T
 	VisitTimestamp() T
 	VisitTimestampTz() T
+	VisitTimestampNs() T
+	VisitTimestampTzNs() T
 	VisitString() T
 	VisitBinary() T
 	VisitUUID() T
+	VisitUnknown() T
+	VisitVariant() T
 }
 
 // Visit accepts a visitor and performs a post-order traversal of the given schema.
@@ -609,12 +613,20 @@ func visitField[T any](f NestedField, visitor SchemaVisitor[T]) T {
 				return perPrimitive.VisitTimestamp()
 			case TimestampTzType:
 				return perPrimitive.VisitTimestampTz()
+			case TimestampNsType:
+				return perPrimitive.VisitTimestampNs()
+			case TimestampTzNsType:
+				return perPrimitive.VisitTimestampTzNs()
 			case StringType:
 				return perPrimitive.VisitString()
 			case BinaryType:
 				return perPrimitive.VisitBinary()
 			case UUIDType:
 				return perPrimitive.VisitUUID()
+			case UnknownType:
+				return perPrimitive.VisitUnknown()
+			case VariantType:
+				return perPrimitive.VisitVariant()
 			case DecimalType:
 				return perPrimitive.VisitDecimal(t)
 			case FixedType:
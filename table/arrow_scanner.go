@@ -19,9 +19,13 @@ package table
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"iter"
+	"maps"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -29,6 +33,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/compute"
 	"github.com/apache/arrow-go/v18/arrow/compute/exprs"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/metadata"
 	"github.com/apache/iceberg-go"
 	iceio "github.com/apache/iceberg-go/io"
 	"github.com/apache/iceberg-go/table/internal"
@@ -44,8 +49,20 @@ const (
 type (
 	positionDeletes   = []*arrow.Chunked
 	perFilePosDeletes = map[string]positionDeletes
+	perFileEqDeletes  = map[string]*equalityDeletes
 )
 
+// equalityDeletes holds the set of deleted row keys read from one or more
+// equality delete files sharing the same equality field ids.
+type equalityDeletes struct {
+	fieldIDs []int
+	// types are the arrow types of the equality fields in the table
+	// schema, which the numeric columns of the delete and data files are
+	// cast to before building the keys.
+	types []arrow.DataType
+	keys  set[string]
+}
+
 func readAllDeleteFiles(ctx context.Context, fs iceio.IO, tasks []FileScanTask, concurrency int) (perFilePosDeletes, error) {
 	var (
 		deletesPerFile = make(perFilePosDeletes)
@@ -124,12 +141,13 @@ func readDeletes(ctx context.Context, fs iceio.IO, dataFile iceberg.DataFile) (m
 
 	filePathCol := tbl.Column(tbl.Schema().FieldIndices("file_path")[0]).Data()
 	posCol := tbl.Column(tbl.Schema().FieldIndices("pos")[0]).Data()
-	dict := filePathCol.Chunk(0).(*array.Dictionary).Dictionary().(*array.String)
+	paths, err := distinctFilePaths(filePathCol)
+	if err != nil {
+		return nil, err
+	}
 
 	results := make(map[string]*arrow.Chunked)
-	for i := 0; i < dict.Len(); i++ {
-		v := dict.Value(i)
-
+	for v := range paths {
 		mask, err := compute.CallFunction(ctx, "equal", nil,
 			compute.NewDatumWithoutOwning(filePathCol), compute.NewDatum(v))
 		if err != nil {
@@ -149,15 +167,294 @@ func readDeletes(ctx context.Context, fs iceio.IO, dataFile iceberg.DataFile) (m
 	return results, nil
 }
 
+// distinctFilePaths returns the data file paths of a file_path column of
+// position deletes, which is dictionary encoded when read from Parquet and
+// a plain string column when read from Avro or ORC.
+func distinctFilePaths(col *arrow.Chunked) (set[string], error) {
+	paths := make(set[string])
+	for _, chunk := range col.Chunks() {
+		var values *array.String
+		switch arr := chunk.(type) {
+		case *array.String:
+			values = arr
+		case *array.Dictionary:
+			dict, ok := arr.Dictionary().(*array.String)
+			if !ok {
+				return nil, fmt.Errorf("%w: position delete file_path dictionary of type %s",
+					iceberg.ErrInvalidArgument, arr.Dictionary().DataType())
+			}
+			values = dict
+		default:
+			return nil, fmt.Errorf("%w: position delete file_path column of type %s",
+				iceberg.ErrInvalidArgument, chunk.DataType())
+		}
+
+		for i := 0; i < values.Len(); i++ {
+			if values.IsValid(i) {
+				paths[values.Value(i)] = struct{}{}
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+func readAllEqualityDeleteFiles(ctx context.Context, fs iceio.IO, tableSchema *iceberg.Schema, tasks []FileScanTask, concurrency int, nameMapping iceberg.NameMapping) (perFileEqDeletes, error) {
+	uniqueDeletes := make(map[string]iceberg.DataFile)
+	for _, t := range tasks {
+		for _, d := range t.DeleteFiles {
+			if d.ContentType() != iceberg.EntryContentEqDeletes {
+				continue
+			}
+
+			if _, ok := uniqueDeletes[d.FilePath()]; !ok {
+				uniqueDeletes[d.FilePath()] = d
+			}
+		}
+	}
+
+	deletesPerFile := make(perFileEqDeletes)
+	if len(uniqueDeletes) == 0 {
+		return deletesPerFile, nil
+	}
+
+	var mx sync.Mutex
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+
+	for path, v := range uniqueDeletes {
+		g.Go(func() error {
+			deletes, err := readEqualityDeletes(ctx, fs, tableSchema, v, nameMapping)
+			if err != nil {
+				return err
+			}
+
+			mx.Lock()
+			defer mx.Unlock()
+			deletesPerFile[path] = deletes
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return deletesPerFile, nil
+}
+
+func readEqualityDeletes(ctx context.Context, fs iceio.IO, tableSchema *iceberg.Schema, dataFile iceberg.DataFile, nameMapping iceberg.NameMapping) (*equalityDeletes, error) {
+	fieldIDs := dataFile.EqualityFieldIDs()
+	if len(fieldIDs) == 0 {
+		return nil, fmt.Errorf("%w: equality delete file %s has no equality field ids",
+			ErrInvalidMetadata, dataFile.FilePath())
+	}
+
+	types, err := equalityFieldTypes(tableSchema, fieldIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := internal.GetFile(ctx, fs, dataFile, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, err := src.GetReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	tbl, err := rdr.ReadTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tbl.Release()
+
+	deleteSchema, err := ArrowSchemaToIceberg(tbl.Schema(), false, nameMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &equalityDeletes{fieldIDs: fieldIDs, types: types, keys: set[string]{}}
+	tblRdr := array.NewTableReader(tbl, -1)
+	defer tblRdr.Release()
+
+	for tblRdr.Next() {
+		rec := tblRdr.Record()
+		cols, err := columnsForFieldIDs(deleteSchema, rec, fieldIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		cols, err = castEqualityColumns(ctx, cols, types)
+		if err != nil {
+			return nil, err
+		}
+
+		for row := range int(rec.NumRows()) {
+			result.keys[equalityKey(cols, row)] = struct{}{}
+		}
+		releaseArrays(cols)
+	}
+
+	return result, tblRdr.Err()
+}
+
+// equalityFieldTypes returns the arrow types of the equality fields in the
+// schema of the table, nil for the fields it doesn't have.
+func equalityFieldTypes(sc *iceberg.Schema, fieldIDs []int) ([]arrow.DataType, error) {
+	types := make([]arrow.DataType, len(fieldIDs))
+	for i, id := range fieldIDs {
+		f, ok := sc.FindFieldByID(id)
+		if !ok {
+			continue
+		}
+
+		typ, err := TypeToArrowType(f.Type, false, false)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = typ
+	}
+
+	return types, nil
+}
+
+// castEqualityColumns casts the numeric columns to the numeric types of the
+// equality fields, so that the values of fields promoted since a file was
+// written, such as from int to long or float to double, produce the same
+// keys as the values of files written after. The returned columns must be
+// released.
+func castEqualityColumns(ctx context.Context, cols []arrow.Array, types []arrow.DataType) ([]arrow.Array, error) {
+	out := make([]arrow.Array, len(cols))
+	for i, c := range cols {
+		if c == nil {
+			continue
+		}
+
+		if i >= len(types) || types[i] == nil || arrow.TypeEqual(c.DataType(), types[i]) ||
+			!isNumericType(c.DataType()) || !isNumericType(types[i]) {
+			c.Retain()
+			out[i] = c
+
+			continue
+		}
+
+		casted, err := compute.CastArray(ctx, c, compute.SafeCastOptions(types[i]))
+		if err != nil {
+			releaseArrays(out)
+
+			return nil, err
+		}
+		out[i] = casted
+	}
+
+	return out, nil
+}
+
+func isNumericType(dt arrow.DataType) bool {
+	id := dt.ID()
+
+	return arrow.IsInteger(id) || arrow.IsFloating(id) || arrow.IsDecimal(id)
+}
+
+func releaseArrays(arrs []arrow.Array) {
+	for _, a := range arrs {
+		if a != nil {
+			a.Release()
+		}
+	}
+}
+
+// columnsForFieldIDs returns the arrays for each of the given field ids from
+// the record, which is expected to match the provided schema. Fields nested
+// within structs are resolved through their parents. A nil array is returned
+// for any field that does not exist in the record, in which case all of its
+// values are considered null.
+func columnsForFieldIDs(sc *iceberg.Schema, rec arrow.Record, fieldIDs []int) ([]arrow.Array, error) {
+	parents, err := iceberg.IndexParents(sc)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]arrow.Array, len(fieldIDs))
+	for i, id := range fieldIDs {
+		if _, ok := sc.FindFieldByID(id); !ok {
+			continue
+		}
+
+		path := []int{id}
+		for parent, ok := parents[id]; ok; parent, ok = parents[parent] {
+			path = append(path, parent)
+		}
+		slices.Reverse(path)
+
+		var (
+			col    arrow.Array
+			fields = sc.Fields()
+		)
+		for _, fieldID := range path {
+			idx := slices.IndexFunc(fields, func(f iceberg.NestedField) bool {
+				return f.ID == fieldID
+			})
+			if idx < 0 {
+				return nil, fmt.Errorf("%w: cannot use field %d as an equality delete field",
+					iceberg.ErrInvalidSchema, id)
+			}
+
+			if col == nil {
+				col = rec.Column(idx)
+			} else {
+				col = col.(*array.Struct).Field(idx)
+			}
+
+			if st, ok := fields[idx].Type.(*iceberg.StructType); ok {
+				fields = st.FieldList
+			} else {
+				fields = nil
+			}
+		}
+
+		out[i] = col
+	}
+
+	return out, nil
+}
+
+// equalityKey encodes the values of the given columns at the row into
+// a single comparable string. Null values compare equal to each other.
+func equalityKey(cols []arrow.Array, row int) string {
+	var b strings.Builder
+	for _, c := range cols {
+		if c == nil || c.IsNull(row) {
+			b.WriteByte('N')
+
+			continue
+		}
+
+		v := c.ValueStr(row)
+		b.WriteByte('V')
+		b.WriteString(strconv.Itoa(len(v)))
+		b.WriteByte(':')
+		b.WriteString(v)
+	}
+
+	return b.String()
+}
+
 type set[T comparable] map[T]struct{}
 
-func combinePositionalDeletes(mem memory.Allocator, deletes set[int64], start, end int64) arrow.Array {
+func combinePositionalDeletes(mem memory.Allocator, start, end int64, keep func(int64) bool) arrow.Array {
 	bldr := array.NewInt64Builder(mem)
 	defer bldr.Release()
 
+	// the indices are relative to the record starting at row start
 	for i := start; i < end; i++ {
-		if _, ok := deletes[i]; !ok {
-			bldr.Append(i)
+		if keep(i) {
+			bldr.Append(i - start)
 		}
 	}
 
@@ -166,8 +463,11 @@ func combinePositionalDeletes(mem memory.Allocator, deletes set[int64], start, e
 
 type recProcessFn func(arrow.Record) (arrow.Record, error)
 
-func processPositionalDeletes(ctx context.Context, deletes set[int64]) recProcessFn {
-	nextIdx, mem := int64(0), compute.GetAllocator(ctx)
+// takeRows returns a recProcessFn keeping the rows of consecutive records
+// for which keep returns true, given the position in the file of the first
+// row of the first record.
+func takeRows(ctx context.Context, firstRow int64, keep func(int64) bool) recProcessFn {
+	nextIdx, mem := firstRow, compute.GetAllocator(ctx)
 
 	return func(r arrow.Record) (arrow.Record, error) {
 		defer r.Release()
@@ -175,7 +475,7 @@ func processPositionalDeletes(ctx context.Context, deletes set[int64]) recProces
 		currentIdx := nextIdx
 		nextIdx += r.NumRows()
 
-		indices := combinePositionalDeletes(mem, deletes, currentIdx, nextIdx)
+		indices := combinePositionalDeletes(mem, currentIdx, nextIdx, keep)
 		defer indices.Release()
 
 		out, err := compute.Take(ctx, *compute.DefaultTakeOptions(),
@@ -188,6 +488,110 @@ func processPositionalDeletes(ctx context.Context, deletes set[int64]) recProces
 	}
 }
 
+func processPositionalDeletes(ctx context.Context, deletes set[int64], firstRow int64) recProcessFn {
+	return takeRows(ctx, firstRow, func(pos int64) bool {
+		_, deleted := deletes[pos]
+
+		return !deleted
+	})
+}
+
+// processSelectedRows keeps only the rows at the given positions which
+// aren't deleted.
+func processSelectedRows(ctx context.Context, rows, deletes set[int64], firstRow int64) recProcessFn {
+	return takeRows(ctx, firstRow, func(pos int64) bool {
+		_, selected := rows[pos]
+		_, deleted := deletes[pos]
+
+		return selected && !deleted
+	})
+}
+
+func processEqualityDeletes(ctx context.Context, fileSchema *iceberg.Schema, deletes []*equalityDeletes) recProcessFn {
+	mem := compute.GetAllocator(ctx)
+
+	return func(r arrow.Record) (arrow.Record, error) {
+		defer r.Release()
+
+		groups := make([][]arrow.Array, len(deletes))
+		defer func() {
+			for _, cols := range groups {
+				releaseArrays(cols)
+			}
+		}()
+
+		for i, d := range deletes {
+			cols, err := columnsForFieldIDs(fileSchema, r, d.fieldIDs)
+			if err != nil {
+				return nil, err
+			}
+
+			if groups[i], err = castEqualityColumns(ctx, cols, d.types); err != nil {
+				return nil, err
+			}
+		}
+
+		bldr := array.NewBooleanBuilder(mem)
+		defer bldr.Release()
+
+		bldr.Reserve(int(r.NumRows()))
+		for row := range int(r.NumRows()) {
+			keep := true
+			for i, d := range deletes {
+				if _, ok := d.keys[equalityKey(groups[i], row)]; ok {
+					keep = false
+
+					break
+				}
+			}
+			bldr.UnsafeAppend(keep)
+		}
+
+		mask := bldr.NewArray()
+		defer mask.Release()
+
+		out, err := compute.Filter(ctx, compute.NewDatumWithoutOwning(r),
+			compute.NewDatumWithoutOwning(mask), *compute.DefaultFilterOptions())
+		if err != nil {
+			return nil, err
+		}
+
+		return out.(*compute.RecordDatum).Value, nil
+	}
+}
+
+// mergeEqualityDeletes collects the equality deletes that apply to the task,
+// combining the keys of delete files which share the same equality field ids.
+func mergeEqualityDeletes(task FileScanTask, deletesPerFile perFileEqDeletes) []*equalityDeletes {
+	var (
+		byFieldIDs = make(map[string]*equalityDeletes)
+		out        []*equalityDeletes
+	)
+
+	for _, df := range task.DeleteFiles {
+		if df.ContentType() != iceberg.EntryContentEqDeletes {
+			continue
+		}
+
+		deletes, ok := deletesPerFile[df.FilePath()]
+		if !ok || len(deletes.keys) == 0 {
+			continue
+		}
+
+		key := fmt.Sprint(deletes.fieldIDs)
+		merged, ok := byFieldIDs[key]
+		if !ok {
+			merged = &equalityDeletes{fieldIDs: deletes.fieldIDs, types: deletes.types, keys: set[string]{}}
+			byFieldIDs[key] = merged
+			out = append(out, merged)
+		}
+
+		maps.Copy(merged.keys, deletes.keys)
+	}
+
+	return out
+}
+
 func filterRecords(ctx context.Context, recordFilter expr.Expression) recProcessFn {
 	return func(rec arrow.Record) (arrow.Record, error) {
 		defer rec.Release()
@@ -221,6 +625,10 @@ type arrowScan struct {
 	concurrency   int
 
 	nameMapping iceberg.NameMapping
+
+	// selectedRows, when set, restricts the rows read from each file to
+	// those at the given positions, the deletes still being applied.
+	selectedRows set[int64]
 }
 
 func (as *arrowScan) projectedFieldIDs() (set[int], error) {
@@ -254,12 +662,7 @@ type enumeratedRecord struct {
 	Err    error
 }
 
-func (as *arrowScan) prepareToRead(ctx context.Context, file iceberg.DataFile) (*iceberg.Schema, []int, internal.FileReader, error) {
-	ids, err := as.projectedFieldIDs()
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
+func (as *arrowScan) prepareToRead(ctx context.Context, ids set[int], file iceberg.DataFile) (*iceberg.Schema, []int, internal.FileReader, error) {
 	src, err := internal.GetFile(ctx, as.fs, file, false)
 	if err != nil {
 		return nil, nil, nil, err
@@ -277,7 +680,10 @@ func (as *arrowScan) prepareToRead(ctx context.Context, file iceberg.DataFile) (
 		return nil, nil, nil, err
 	}
 
-	iceSchema, err := ArrowSchemaToIceberg(fileSchema, false, as.nameMapping)
+	// nanosecond timestamps in a file can only be read as the v3 timestamp_ns
+	// types, projecting them to any other type fails when reading
+	iceSchema, err := arrowSchemaToIceberg(fileSchema,
+		convertToIceberg{nsTimestamp: true}, as.nameMapping)
 	if err != nil {
 		rdr.Close()
 
@@ -288,59 +694,110 @@ func (as *arrowScan) prepareToRead(ctx context.Context, file iceberg.DataFile) (
 }
 
 func (as *arrowScan) getRecordFilter(ctx context.Context, fileSchema *iceberg.Schema) (recProcessFn, bool, error) {
+	ctx, recordFilter, dropFile, err := as.translateRecordFilter(ctx, fileSchema)
+	if err != nil || recordFilter == nil {
+		return nil, dropFile, err
+	}
+
+	return filterRecords(ctx, recordFilter), false, nil
+}
+
+// translateRecordFilter converts the bound row filter into a substrait
+// expression against the given file schema, returning the context that
+// must be used to execute it. A nil expression is returned if the filter
+// always matches, and the returned bool is true if it never matches.
+func (as *arrowScan) translateRecordFilter(ctx context.Context, fileSchema *iceberg.Schema) (context.Context, expr.Expression, bool, error) {
 	if as.boundRowFilter == nil || as.boundRowFilter.Equals(iceberg.AlwaysTrue{}) {
-		return nil, false, nil
+		return ctx, nil, false, nil
 	}
 
 	translatedFilter, err := iceberg.TranslateColumnNames(as.boundRowFilter, fileSchema)
 	if err != nil {
-		return nil, false, err
+		return ctx, nil, false, err
 	}
 
 	if translatedFilter.Equals(iceberg.AlwaysFalse{}) {
-		return nil, true, nil
+		return ctx, nil, true, nil
 	}
 
 	translatedFilter, err = iceberg.BindExpr(fileSchema, translatedFilter, as.caseSensitive)
 	if err != nil {
-		return nil, false, err
+		return ctx, nil, false, err
 	}
 
 	if !translatedFilter.Equals(iceberg.AlwaysTrue{}) {
 		extSet, recordFilter, err := substrait.ConvertExpr(fileSchema, translatedFilter, as.caseSensitive)
 		if err != nil {
-			return nil, false, err
+			return ctx, nil, false, err
 		}
 
-		ctx = exprs.WithExtensionIDSet(ctx, exprs.NewExtensionSetDefault(*extSet))
+		return exprs.WithExtensionIDSet(ctx, exprs.NewExtensionSetDefault(*extSet)), recordFilter, false, nil
+	}
+
+	return ctx, nil, false, nil
+}
 
-		return filterRecords(ctx, recordFilter), false, nil
+// parquetRowGroupTester returns the function selecting the rows of a parquet
+// file to read for the task, along with the position in the file of the first
+// row of the task. Only the row groups starting within the byte range of the
+// task are read. Unless position deletes have to be applied, as they require
+// the positions of the rows read to be contiguous, row groups whose
+// statistics or bloom filters show that no rows can match the filter are
+// skipped, as are the pages whose column index excludes any matching row.
+func (as *arrowScan) parquetRowGroupTester(fileSchema *iceberg.Schema, task FileScanTask, meta *metadata.FileMetaData, hasPosDeletes bool) (internal.ParquetRowSelector, int64, error) {
+	var selectRows internal.ParquetRowSelector
+	if !hasPosDeletes {
+		var err error
+		selectRows, err = newParquetRowSelector(fileSchema, as.boundRowFilter)
+		if err != nil {
+			return nil, 0, err
+		}
 	}
 
-	return nil, false, nil
+	wholeFile := task.Length <= 0 || (task.Start <= 0 && task.Length >= task.File.FileSizeBytes())
+	end := task.Start + task.Length
+
+	var firstRow int64
+	if !wholeFile {
+		for i := range meta.NumRowGroups() {
+			rg := meta.RowGroup(i)
+			offset, err := internal.RowGroupOffset(rg)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			if offset < task.Start {
+				firstRow += rg.NumRows()
+			}
+		}
+	}
+
+	return func(rg internal.ParquetRowGroup, cols []int) (bool, []internal.RowRange, error) {
+		if !wholeFile {
+			offset, err := internal.RowGroupOffset(rg.RowGroupMetaData)
+			if err != nil || offset < task.Start || offset >= end {
+				return false, nil, err
+			}
+		}
+
+		if selectRows == nil {
+			return true, nil, nil
+		}
+
+		return selectRows(rg, cols)
+	}, firstRow, nil
 }
 
 func (as *arrowScan) processRecords(
 	ctx context.Context,
 	task internal.Enumerated[FileScanTask],
-	fileSchema *iceberg.Schema,
 	rdr internal.FileReader,
 	columns []int,
+	testRowGroups any,
 	pipeline []recProcessFn,
 	out chan<- enumeratedRecord,
 ) (err error) {
-	var (
-		testRowGroups any
-		recRdr        array.RecordReader
-	)
-
-	switch task.Value.File.FileFormat() {
-	case iceberg.ParquetFile:
-		testRowGroups, err = newParquetRowGroupStatsEvaluator(fileSchema, as.boundRowFilter, false)
-		if err != nil {
-			return err
-		}
-	}
+	var recRdr array.RecordReader
 
 	recRdr, err = rdr.GetRecords(ctx, columns, testRowGroups)
 	if err != nil {
@@ -385,7 +842,7 @@ func (as *arrowScan) processRecords(
 	return err
 }
 
-func (as *arrowScan) recordsFromTask(ctx context.Context, task internal.Enumerated[FileScanTask], out chan<- enumeratedRecord, positionalDeletes positionDeletes) (err error) {
+func (as *arrowScan) recordsFromTask(ctx context.Context, task internal.Enumerated[FileScanTask], out chan<- enumeratedRecord, positionalDeletes positionDeletes, eqDeletes []*equalityDeletes) (err error) {
 	defer func() {
 		if err != nil {
 			out <- enumeratedRecord{Task: task, Err: err}
@@ -398,26 +855,61 @@ func (as *arrowScan) recordsFromTask(ctx context.Context, task internal.Enumerat
 		colIndices []int
 		filterFunc recProcessFn
 		dropFile   bool
+		ids        set[int]
 	)
 
-	iceSchema, colIndices, rdr, err = as.prepareToRead(ctx, task.Value.File)
+	ids, err = as.projectedFieldIDs()
+	if err != nil {
+		return
+	}
+
+	// the equality fields must be read in order to apply the deletes, even
+	// if they aren't part of the projection.
+	for _, d := range eqDeletes {
+		for _, id := range d.fieldIDs {
+			ids[id] = struct{}{}
+		}
+	}
+
+	iceSchema, colIndices, rdr, err = as.prepareToRead(ctx, ids, task.Value.File)
 	if err != nil {
 		return
 	}
 	defer rdr.Close()
 
-	pipeline := make([]recProcessFn, 0, 2)
-	if len(positionalDeletes) > 0 {
-		deletes := set[int64]{}
-		for _, chunk := range positionalDeletes {
-			for _, a := range chunk.Chunks() {
-				for _, v := range a.(*array.Int64).Int64Values() {
-					deletes[v] = struct{}{}
-				}
+	var (
+		testRowGroups any
+		firstRow      int64
+	)
+
+	switch task.Value.File.FileFormat() {
+	case iceberg.ParquetFile:
+		testRowGroups, firstRow, err = as.parquetRowGroupTester(iceSchema, task.Value,
+			rdr.Metadata().(*metadata.FileMetaData), len(positionalDeletes) > 0 || as.selectedRows != nil)
+		if err != nil {
+			return
+		}
+	}
+
+	pipeline := make([]recProcessFn, 0, 3)
+	deletes := set[int64]{}
+	for _, chunk := range positionalDeletes {
+		for _, a := range chunk.Chunks() {
+			for _, v := range a.(*array.Int64).Int64Values() {
+				deletes[v] = struct{}{}
 			}
 		}
+	}
 
-		pipeline = append(pipeline, processPositionalDeletes(ctx, deletes))
+	switch {
+	case as.selectedRows != nil:
+		pipeline = append(pipeline, processSelectedRows(ctx, as.selectedRows, deletes, firstRow))
+	case len(deletes) > 0:
+		pipeline = append(pipeline, processPositionalDeletes(ctx, deletes, firstRow))
+	}
+
+	if len(eqDeletes) > 0 {
+		pipeline = append(pipeline, processEqualityDeletes(ctx, iceSchema, eqDeletes))
 	}
 
 	filterFunc, dropFile, err = as.getRecordFilter(ctx, iceSchema)
@@ -448,7 +940,7 @@ func (as *arrowScan) recordsFromTask(ctx context.Context, task internal.Enumerat
 		return ToRequestedSchema(ctx, as.projectedSchema, iceSchema, r, false, false, as.useLargeTypes)
 	})
 
-	err = as.processRecords(ctx, task, iceSchema, rdr, colIndices, pipeline, out)
+	err = as.processRecords(ctx, task, rdr, colIndices, testRowGroups, pipeline, out)
 
 	return
 }
@@ -553,9 +1045,8 @@ func createIterator(ctx context.Context, numWorkers uint, records <-chan enumera
 	}
 }
 
-func (as *arrowScan) recordBatchesFromTasksAndDeletes(ctx context.Context, tasks []FileScanTask, deletesPerFile perFilePosDeletes) iter.Seq2[arrow.Record, error] {
+func (as *arrowScan) recordBatchesFromTasksAndDeletes(ctx context.Context, tasks []FileScanTask, deletesPerFile perFilePosDeletes, eqDeletesPerFile perFileEqDeletes) iter.Seq2[arrow.Record, error] {
 	extSet := substrait.NewExtensionSet()
-	as.nameMapping = as.metadata.NameMapping()
 
 	ctx, cancel := context.WithCancelCause(exprs.WithExtensionIDSet(ctx, extSet))
 	taskChan := make(chan internal.Enumerated[FileScanTask], len(tasks))
@@ -579,7 +1070,8 @@ func (as *arrowScan) recordBatchesFromTasksAndDeletes(ctx context.Context, tasks
 					}
 
 					if err := as.recordsFromTask(ctx, task, records,
-						deletesPerFile[task.Value.File.FilePath()]); err != nil {
+						deletesPerFile[task.Value.File.FilePath()],
+						mergeEqualityDeletes(task.Value, eqDeletesPerFile)); err != nil {
 						cancel(err)
 
 						return
@@ -605,14 +1097,20 @@ func (as *arrowScan) recordBatchesFromTasksAndDeletes(ctx context.Context, tasks
 		cancel, as.rowLimit)
 }
 
-func (as *arrowScan) GetRecords(ctx context.Context, tasks []FileScanTask) (*arrow.Schema, iter.Seq2[arrow.Record, error], error) {
+// resultSchema returns the arrow schema of the records read, using large
+// types if the scan options ask for them.
+func (as *arrowScan) resultSchema() (*arrow.Schema, error) {
 	var err error
 	as.useLargeTypes, err = strconv.ParseBool(as.options.Get(ScanOptionArrowUseLargeTypes, "false"))
 	if err != nil {
 		as.useLargeTypes = false
 	}
 
-	resultSchema, err := SchemaToArrowSchema(as.projectedSchema, nil, false, as.useLargeTypes)
+	return SchemaToArrowSchema(as.projectedSchema, nil, false, as.useLargeTypes)
+}
+
+func (as *arrowScan) GetRecords(ctx context.Context, tasks []FileScanTask) (*arrow.Schema, iter.Seq2[arrow.Record, error], error) {
+	resultSchema, err := as.resultSchema()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -621,10 +1119,16 @@ func (as *arrowScan) GetRecords(ctx context.Context, tasks []FileScanTask) (*arr
 		return resultSchema, func(yield func(arrow.Record, error) bool) {}, nil
 	}
 
+	as.nameMapping = as.metadata.NameMapping()
 	deletesPerFile, err := readAllDeleteFiles(ctx, as.fs, tasks, as.concurrency)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return resultSchema, as.recordBatchesFromTasksAndDeletes(ctx, tasks, deletesPerFile), nil
+	eqDeletesPerFile, err := readAllEqualityDeleteFiles(ctx, as.fs, as.metadata.CurrentSchema(), tasks, as.concurrency, as.nameMapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resultSchema, as.recordBatchesFromTasksAndDeletes(ctx, tasks, deletesPerFile, eqDeletesPerFile), nil
 }
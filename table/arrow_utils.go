@@ -18,6 +18,8 @@
 package table
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"iter"
@@ -29,6 +31,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/bitutil"
 	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
 	"github.com/apache/arrow-go/v18/arrow/extensions"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/iceberg-go"
@@ -232,6 +235,10 @@ func (hasIDs) Primitive(arrow.DataType) bool { return true }
 
 type convertToIceberg struct {
 	downcastTimestamp bool
+	// nsTimestamp maps nanosecond timestamps to timestamp_ns and
+	// timestamptz_ns rather than rejecting or downcasting them, which
+	// is only valid for format version 3 tables.
+	nsTimestamp bool
 
 	fieldID func(arrow.Field) int
 }
@@ -331,6 +338,18 @@ func (c convertToIceberg) Primitive(dt arrow.DataType) (result iceberg.NestedFie
 			panic(fmt.Errorf("%w: unsupported arrow type for conversion - %s", iceberg.ErrInvalidSchema, dt))
 		}
 	case *arrow.TimestampType:
+		if dt.Unit == arrow.Nanosecond && c.nsTimestamp {
+			if slices.Contains(utcAliases, dt.TimeZone) {
+				result.Type = iceberg.PrimitiveTypes.TimestampTzNs
+			} else if dt.TimeZone == "" {
+				result.Type = iceberg.PrimitiveTypes.TimestampNs
+			} else {
+				panic(fmt.Errorf("%w: unsupported arrow type for conversion - %s", iceberg.ErrInvalidSchema, dt))
+			}
+
+			break
+		}
+
 		if dt.Unit == arrow.Nanosecond {
 			if !c.downcastTimestamp {
 				panic(fmt.Errorf("%w: 'ns' timestamp precision not supported", iceberg.ErrType))
@@ -385,28 +404,32 @@ func ArrowTypeToIceberg(dt arrow.DataType, downcastNsTimestamp bool) (iceberg.Ty
 }
 
 func ArrowSchemaToIceberg(sc *arrow.Schema, downcastNsTimestamp bool, nameMapping iceberg.NameMapping) (*iceberg.Schema, error) {
+	return arrowSchemaToIceberg(sc, convertToIceberg{downcastTimestamp: downcastNsTimestamp}, nameMapping)
+}
+
+// arrowSchemaToIceberg is ArrowSchemaToIceberg with the timestamp handling
+// options taken from conv, whose fieldID func is always replaced.
+func arrowSchemaToIceberg(sc *arrow.Schema, conv convertToIceberg, nameMapping iceberg.NameMapping) (*iceberg.Schema, error) {
 	hasIDs, _ := VisitArrowSchema(sc, hasIDs{})
 
 	switch {
 	case hasIDs:
-		out, err := VisitArrowSchema(sc, convertToIceberg{
-			downcastTimestamp: downcastNsTimestamp,
-			fieldID: func(field arrow.Field) int {
-				if id := getFieldID(field); id != nil {
-					return *id
-				}
+		conv.fieldID = func(field arrow.Field) int {
+			if id := getFieldID(field); id != nil {
+				return *id
+			}
 
-				panic(fmt.Errorf("%w: cannot convert %s to Iceberg field, missing field_id",
-					iceberg.ErrInvalidSchema, field))
-			},
-		})
+			panic(fmt.Errorf("%w: cannot convert %s to Iceberg field, missing field_id",
+				iceberg.ErrInvalidSchema, field))
+		}
+		out, err := VisitArrowSchema(sc, conv)
 		if err != nil {
 			return nil, err
 		}
 
 		return iceberg.NewSchema(0, out.Type.(*iceberg.StructType).FieldList...), nil
 	case nameMapping != nil:
-		schemaWithoutIDs, err := arrowToSchemaWithoutIDs(sc, downcastNsTimestamp)
+		schemaWithoutIDs, err := arrowToSchemaWithoutIDs(sc, conv)
 		if err != nil {
 			return nil, err
 		}
@@ -419,7 +442,7 @@ func ArrowSchemaToIceberg(sc *arrow.Schema, downcastNsTimestamp bool, nameMappin
 }
 
 func ArrowSchemaToIcebergWithFreshIDs(sc *arrow.Schema, downcastNsTimestamp bool) (*iceberg.Schema, error) {
-	schemaWithoutIDs, err := arrowToSchemaWithoutIDs(sc, downcastNsTimestamp)
+	schemaWithoutIDs, err := arrowToSchemaWithoutIDs(sc, convertToIceberg{downcastTimestamp: downcastNsTimestamp})
 	if err != nil {
 		return nil, err
 	}
@@ -427,11 +450,9 @@ func ArrowSchemaToIcebergWithFreshIDs(sc *arrow.Schema, downcastNsTimestamp bool
 	return iceberg.AssignFreshSchemaIDs(schemaWithoutIDs, nil)
 }
 
-func arrowToSchemaWithoutIDs(sc *arrow.Schema, downcastNsTimestamp bool) (*iceberg.Schema, error) {
-	withoutIDs, err := VisitArrowSchema(sc, convertToIceberg{
-		downcastTimestamp: downcastNsTimestamp,
-		fieldID:           func(_ arrow.Field) int { return -1 },
-	})
+func arrowToSchemaWithoutIDs(sc *arrow.Schema, conv convertToIceberg) (*iceberg.Schema, error) {
+	conv.fieldID = func(_ arrow.Field) int { return -1 }
+	withoutIDs, err := VisitArrowSchema(sc, conv)
 	if err != nil {
 		return nil, err
 	}
@@ -587,6 +608,14 @@ func (c convertToArrow) VisitTimestamp() arrow.Field {
 	return arrow.Field{Type: &arrow.TimestampType{Unit: arrow.Microsecond}}
 }
 
+func (c convertToArrow) VisitTimestampTzNs() arrow.Field {
+	return arrow.Field{Type: arrow.FixedWidthTypes.Timestamp_ns}
+}
+
+func (c convertToArrow) VisitTimestampNs() arrow.Field {
+	return arrow.Field{Type: &arrow.TimestampType{Unit: arrow.Nanosecond}}
+}
+
 func (c convertToArrow) VisitString() arrow.Field {
 	if c.useLargeTypes {
 		return arrow.Field{Type: arrow.BinaryTypes.LargeString}
@@ -607,6 +636,24 @@ func (c convertToArrow) VisitUUID() arrow.Field {
 	return arrow.Field{Type: extensions.NewUUIDType()}
 }
 
+func (c convertToArrow) VisitUnknown() arrow.Field {
+	return arrow.Field{Type: arrow.Null}
+}
+
+// VisitVariant maps a variant to its unshredded physical layout, a struct
+// of the binary metadata and value buffers.
+func (c convertToArrow) VisitVariant() arrow.Field {
+	binType := arrow.BinaryTypes.Binary
+	if c.useLargeTypes {
+		binType = arrow.BinaryTypes.LargeBinary
+	}
+
+	return arrow.Field{Type: arrow.StructOf(
+		arrow.Field{Name: "metadata", Type: binType},
+		arrow.Field{Name: "value", Type: binType},
+	)}
+}
+
 // SchemaToArrowSchema converts an Iceberg schema to an Arrow schema. If the metadata parameter
 // is non-nil, it will be included as the top-level metadata in the schema. If includeFieldIDs
 // is true, then each field of the schema will contain a metadata key PARQUET:field_id set to
@@ -1296,10 +1343,11 @@ func recordsToDataFiles(ctx context.Context, rootLocation string, meta *Metadata
 			for batch := range binPackRecords(args.itr, 20, targetFileSize) {
 				cnt, _ := nextCount()
 				t := WriteTask{
-					Uuid:    *args.writeUUID,
-					ID:      cnt,
-					Schema:  taskSchema,
-					Batches: batch,
+					Uuid:        *args.writeUUID,
+					ID:          cnt,
+					Schema:      taskSchema,
+					Batches:     batch,
+					SortOrderID: meta.defaultSortOrderID,
 				}
 				if !yield(t) {
 					return
@@ -1310,5 +1358,700 @@ func recordsToDataFiles(ctx context.Context, rootLocation string, meta *Metadata
 		return writeFiles(ctx, rootLocation, args.fs, meta, tasks)
 	}
 
-	panic(fmt.Errorf("%w: write stream with partitions", iceberg.ErrNotImplemented))
+	fanout, err := newPartitionFanout(meta.CurrentSpec(), meta.CurrentSchema(), taskSchema)
+	if err != nil {
+		panic(err)
+	}
+
+	// the records are split as they are read, a partition is written once
+	// its buffered records reach the target file size and the largest
+	// partitions are written early to bound the total buffered size.
+	var splitErr error
+	maxBuffered := targetFileSize * partitionFanoutMaxBufferedFiles
+	tasks := func(yield func(WriteTask) bool) {
+		defer stopCount()
+		defer fanout.release()
+
+		emit := func(p *partitionBuffer) bool {
+			cnt, _ := nextCount()
+
+			return yield(WriteTask{
+				Uuid:            *args.writeUUID,
+				ID:              cnt,
+				Schema:          taskSchema,
+				Batches:         p.take(),
+				SortOrderID:     meta.defaultSortOrderID,
+				PartitionValues: p.values,
+				PartitionPath:   p.path,
+			})
+		}
+
+		for rec, err := range args.itr {
+			if err != nil {
+				splitErr = err
+
+				return
+			}
+
+			touched, err := fanout.add(ctx, rec)
+			if err != nil {
+				splitErr = err
+
+				return
+			}
+
+			for _, p := range touched {
+				if p.nbytes >= targetFileSize && !emit(p) {
+					return
+				}
+			}
+
+			for fanout.buffered() > maxBuffered {
+				if !emit(fanout.largest()) {
+					return
+				}
+			}
+		}
+
+		for _, p := range fanout.partitions {
+			if len(p.records) > 0 && !emit(p) {
+				return
+			}
+		}
+	}
+
+	files := writeFiles(ctx, rootLocation, args.fs, meta, tasks)
+
+	return func(yield func(iceberg.DataFile, error) bool) {
+		for df, err := range files {
+			if !yield(df, err) || err != nil {
+				return
+			}
+		}
+
+		if splitErr != nil {
+			yield(nil, splitErr)
+		}
+	}
+}
+
+// partitionFanoutMaxBufferedFiles bounds the size of the records buffered
+// across all partitions during a partitioned write, as a multiple of the
+// target file size.
+const partitionFanoutMaxBufferedFiles = 4
+
+// partitionBuffer holds the records of a single partition of the table's
+// current spec waiting to be written, along with the partition values
+// keyed by partition field id.
+type partitionBuffer struct {
+	values  map[int]any
+	path    string
+	records []arrow.Record
+	nbytes  int64
+}
+
+// take returns the buffered records, handing over their ownership, and
+// empties the buffer.
+func (p *partitionBuffer) take() []arrow.Record {
+	out := p.records
+	p.records, p.nbytes = nil, 0
+
+	return out
+}
+
+// partitionFanout splits records by applying the transforms of the
+// partition spec to their source columns, buffering the rows of each
+// partition until they are written.
+type partitionFanout struct {
+	spec        iceberg.PartitionSpec
+	tableSchema *iceberg.Schema
+	recSchema   *iceberg.Schema
+	fields      []iceberg.PartitionField
+	sourceIDs   []int
+	sourceTypes []iceberg.Type
+
+	byKey map[string]*partitionBuffer
+	// partitions in the order they were first encountered
+	partitions []*partitionBuffer
+}
+
+func newPartitionFanout(spec iceberg.PartitionSpec, tableSchema, recSchema *iceberg.Schema) (*partitionFanout, error) {
+	fields := slices.Collect(spec.Fields())
+	sourceIDs, sourceTypes := make([]int, len(fields)), make([]iceberg.Type, len(fields))
+	for i, f := range fields {
+		sourceField, ok := tableSchema.FindFieldByID(f.SourceID)
+		if !ok {
+			return nil, fmt.Errorf("%w: could not find source field %d for partition field %s",
+				iceberg.ErrInvalidSchema, f.SourceID, f.Name)
+		}
+
+		if _, ok := recSchema.FindFieldByID(f.SourceID); !ok {
+			return nil, fmt.Errorf("%w: records are missing source column %s for partition field %s",
+				iceberg.ErrInvalidSchema, sourceField.Name, f.Name)
+		}
+
+		sourceIDs[i], sourceTypes[i] = f.SourceID, sourceField.Type
+	}
+
+	return &partitionFanout{
+		spec:        spec,
+		tableSchema: tableSchema,
+		recSchema:   recSchema,
+		fields:      fields,
+		sourceIDs:   sourceIDs,
+		sourceTypes: sourceTypes,
+		byKey:       make(map[string]*partitionBuffer),
+	}, nil
+}
+
+// add splits the rows of the record by partition and appends them to the
+// buffers of their partitions, which are returned.
+func (f *partitionFanout) add(ctx context.Context, rec arrow.Record) ([]*partitionBuffer, error) {
+	cols, err := columnsForFieldIDs(f.recSchema, rec, f.sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	readers := make([]func(int) (iceberg.Optional[iceberg.Literal], error), len(f.fields))
+	for i := range f.fields {
+		if readers[i], err = arrowLiteralReader(cols[i], f.sourceTypes[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		literals  = make([]iceberg.Literal, len(f.fields))
+		rowsByKey = make(map[string][]int64)
+		keyOrder  []string
+	)
+	for row := range int(rec.NumRows()) {
+		for i, pf := range f.fields {
+			val, err := readers[i](row)
+			if err != nil {
+				return nil, err
+			}
+
+			literals[i] = nil
+			if result := pf.Transform.Apply(val); result.Valid {
+				literals[i] = result.Val
+			}
+		}
+
+		k := partitionKey(literals)
+		if _, ok := f.byKey[k]; !ok {
+			values := make(map[int]any, len(f.fields))
+			transformed := make(partitionRecord, len(f.fields))
+			for i, pf := range f.fields {
+				if literals[i] != nil {
+					transformed[i] = literals[i].Any()
+				}
+				values[pf.FieldID] = transformed[i]
+			}
+
+			p := &partitionBuffer{
+				values: values,
+				path:   f.spec.PartitionToPath(transformed, f.tableSchema),
+			}
+			f.byKey[k] = p
+			f.partitions = append(f.partitions, p)
+		}
+
+		if _, ok := rowsByKey[k]; !ok {
+			keyOrder = append(keyOrder, k)
+		}
+		rowsByKey[k] = append(rowsByKey[k], int64(row))
+	}
+
+	mem := compute.GetAllocator(ctx)
+	touched := make([]*partitionBuffer, 0, len(keyOrder))
+	for _, k := range keyOrder {
+		p, rows := f.byKey[k], rowsByKey[k]
+		touched = append(touched, p)
+
+		if len(rows) == int(rec.NumRows()) {
+			rec.Retain()
+			p.records = append(p.records, rec)
+			p.nbytes += recordNBytes(rec)
+
+			continue
+		}
+
+		indices := array.NewInt64Builder(mem)
+		indices.AppendValues(rows, nil)
+		idxArr := indices.NewArray()
+		indices.Release()
+
+		result, err := compute.Take(ctx, *compute.DefaultTakeOptions(),
+			compute.NewDatumWithoutOwning(rec), compute.NewDatumWithoutOwning(idxArr))
+		idxArr.Release()
+		if err != nil {
+			return nil, err
+		}
+
+		part := result.(*compute.RecordDatum).Value
+		p.records = append(p.records, part)
+		p.nbytes += recordNBytes(part)
+	}
+
+	return touched, nil
+}
+
+// buffered returns the total size of the buffered records.
+func (f *partitionFanout) buffered() (total int64) {
+	for _, p := range f.partitions {
+		total += p.nbytes
+	}
+
+	return total
+}
+
+// largest returns the partition with the most buffered records.
+func (f *partitionFanout) largest() *partitionBuffer {
+	return slices.MaxFunc(f.partitions, func(a, b *partitionBuffer) int {
+		return cmp.Compare(a.nbytes, b.nbytes)
+	})
+}
+
+// release releases the records which are still buffered.
+func (f *partitionFanout) release() {
+	for _, p := range f.partitions {
+		for _, rec := range p.take() {
+			rec.Release()
+		}
+	}
+}
+
+// sortRow is a row of one of the records being sorted.
+type sortRow struct {
+	rec, row int
+}
+
+// sortRecords returns the rows of the records, which must share the same
+// schema, sorted by the sort order. The values of the sort fields are
+// transformed into arrow arrays and compared with comparators for their
+// types, rows with equal sort values keep the order they had in the records.
+// The records aren't concatenated, the sorted records hold the runs of rows
+// taken from the same record.
+func sortRecords(ctx context.Context, sc *iceberg.Schema, order SortOrder, recs []arrow.Record) ([]arrow.Record, error) {
+	var rows []sortRow
+	for i, rec := range recs {
+		for row := range int(rec.NumRows()) {
+			rows = append(rows, sortRow{rec: i, row: row})
+		}
+	}
+
+	if len(rows) == 0 {
+		for _, rec := range recs {
+			rec.Retain()
+		}
+
+		return slices.Clone(recs), nil
+	}
+
+	var (
+		mem  = compute.GetAllocator(ctx)
+		keys = make([][]arrow.Array, 0, len(order.Fields))
+		cmps = make([]func(a, b sortRow) int, 0, len(order.Fields))
+	)
+	defer func() {
+		for _, cols := range keys {
+			for _, c := range cols {
+				if c != nil {
+					c.Release()
+				}
+			}
+		}
+	}()
+
+	for _, f := range order.Fields {
+		sourceField, ok := sc.FindFieldByID(f.SourceID)
+		if !ok {
+			return nil, fmt.Errorf("%w: could not find source field %d for sort field %s",
+				iceberg.ErrInvalidSchema, f.SourceID, f.String())
+		}
+
+		// the values of a void transform are all null
+		if _, ok := f.Transform.(iceberg.VoidTransform); ok {
+			continue
+		}
+
+		cols := make([]arrow.Array, len(recs))
+		keys = append(keys, cols)
+		for i, rec := range recs {
+			col, err := sortKeyColumn(mem, sc, rec, sourceField, f.Transform)
+			if err != nil {
+				return nil, err
+			}
+			cols[i] = col
+		}
+
+		compare, err := arrowComparator(cols)
+		if err != nil {
+			return nil, fmt.Errorf("%w for sort field %s", err, f.String())
+		}
+
+		cmps = append(cmps, func(a, b sortRow) int {
+			aNull, bNull := cols[a.rec].IsNull(a.row), cols[b.rec].IsNull(b.row)
+			switch {
+			case aNull && bNull:
+				return 0
+			case aNull || bNull:
+				// the null order doesn't depend on the direction
+				if bNull == (f.NullOrder == NullsFirst) {
+					return 1
+				}
+
+				return -1
+			}
+
+			if f.Direction == SortDESC {
+				return -compare(a, b)
+			}
+
+			return compare(a, b)
+		})
+	}
+
+	slices.SortStableFunc(rows, func(a, b sortRow) int {
+		for _, compare := range cmps {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	return takeSortedRows(ctx, recs, rows)
+}
+
+// sortKeyColumn returns the values of the source field of the record with
+// the transform applied, as an array of the result type of the transform.
+func sortKeyColumn(mem memory.Allocator, sc *iceberg.Schema, rec arrow.Record, source iceberg.NestedField, transform iceberg.Transform) (arrow.Array, error) {
+	cols, err := columnsForFieldIDs(sc, rec, []int{source.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	col := cols[0]
+	if _, ok := transform.(iceberg.IdentityTransform); ok {
+		col.Retain()
+
+		return col, nil
+	}
+
+	dt, err := TypeToArrowType(transform.ResultType(source.Type), false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	read, err := arrowLiteralReader(col, source.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	bldr := array.NewBuilder(mem, dt)
+	defer bldr.Release()
+	bldr.Reserve(col.Len())
+
+	for row := range col.Len() {
+		val, err := read(row)
+		if err != nil {
+			return nil, err
+		}
+
+		result := transform.Apply(val)
+		if !result.Valid {
+			bldr.AppendNull()
+
+			continue
+		}
+
+		if err := appendLiteral(bldr, result.Val); err != nil {
+			return nil, err
+		}
+	}
+
+	return bldr.NewArray(), nil
+}
+
+// appendLiteral appends the value of the literal to the builder, which
+// must be a builder for the result types of the transforms.
+func appendLiteral(bldr array.Builder, lit iceberg.Literal) error {
+	ok := false
+	switch b := bldr.(type) {
+	case *array.Int32Builder:
+		var v int32
+		if v, ok = lit.Any().(int32); ok {
+			b.Append(v)
+		}
+	case *array.Int64Builder:
+		var v int64
+		if v, ok = lit.Any().(int64); ok {
+			b.Append(v)
+		}
+	case *array.StringBuilder:
+		var v string
+		if v, ok = lit.Any().(string); ok {
+			b.Append(v)
+		}
+	case *array.BinaryBuilder:
+		var v []byte
+		if v, ok = lit.Any().([]byte); ok {
+			b.Append(v)
+		}
+	case *array.Decimal128Builder:
+		var v iceberg.Decimal
+		if v, ok = lit.Any().(iceberg.Decimal); ok {
+			b.Append(v.Val)
+		}
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: cannot append %s literal to %s array",
+			iceberg.ErrNotImplemented, lit.Type(), bldr.Type())
+	}
+
+	return nil
+}
+
+// arrowComparator returns a function comparing the non-null values of the
+// rows of the arrays, which must share the same type.
+func arrowComparator(cols []arrow.Array) (func(a, b sortRow) int, error) {
+	// extension arrays, such as of uuids, are compared by their storage
+	if _, ok := cols[0].(array.ExtensionArray); ok {
+		storage := make([]arrow.Array, len(cols))
+		for i, c := range cols {
+			storage[i] = c.(array.ExtensionArray).Storage()
+		}
+		cols = storage
+	}
+
+	switch cols[0].(type) {
+	case *array.Boolean:
+		return valueComparator[bool, *array.Boolean](cols, func(a, b bool) int {
+			switch {
+			case a == b:
+				return 0
+			case a:
+				return 1
+			}
+
+			return -1
+		}), nil
+	case *array.Int8:
+		return valueComparator[int8, *array.Int8](cols, cmp.Compare[int8]), nil
+	case *array.Int16:
+		return valueComparator[int16, *array.Int16](cols, cmp.Compare[int16]), nil
+	case *array.Int32:
+		return valueComparator[int32, *array.Int32](cols, cmp.Compare[int32]), nil
+	case *array.Int64:
+		return valueComparator[int64, *array.Int64](cols, cmp.Compare[int64]), nil
+	case *array.Uint8:
+		return valueComparator[uint8, *array.Uint8](cols, cmp.Compare[uint8]), nil
+	case *array.Uint16:
+		return valueComparator[uint16, *array.Uint16](cols, cmp.Compare[uint16]), nil
+	case *array.Uint32:
+		return valueComparator[uint32, *array.Uint32](cols, cmp.Compare[uint32]), nil
+	case *array.Uint64:
+		return valueComparator[uint64, *array.Uint64](cols, cmp.Compare[uint64]), nil
+	case *array.Float32:
+		return valueComparator[float32, *array.Float32](cols, cmp.Compare[float32]), nil
+	case *array.Float64:
+		return valueComparator[float64, *array.Float64](cols, cmp.Compare[float64]), nil
+	case *array.Date32:
+		return valueComparator[arrow.Date32, *array.Date32](cols, cmp.Compare[arrow.Date32]), nil
+	case *array.Date64:
+		return valueComparator[arrow.Date64, *array.Date64](cols, cmp.Compare[arrow.Date64]), nil
+	case *array.Time32:
+		return valueComparator[arrow.Time32, *array.Time32](cols, cmp.Compare[arrow.Time32]), nil
+	case *array.Time64:
+		return valueComparator[arrow.Time64, *array.Time64](cols, cmp.Compare[arrow.Time64]), nil
+	case *array.Timestamp:
+		return valueComparator[arrow.Timestamp, *array.Timestamp](cols, cmp.Compare[arrow.Timestamp]), nil
+	case *array.String:
+		return valueComparator[string, *array.String](cols, strings.Compare), nil
+	case *array.LargeString:
+		return valueComparator[string, *array.LargeString](cols, strings.Compare), nil
+	case *array.Binary:
+		return valueComparator[[]byte, *array.Binary](cols, bytes.Compare), nil
+	case *array.LargeBinary:
+		return valueComparator[[]byte, *array.LargeBinary](cols, bytes.Compare), nil
+	case *array.FixedSizeBinary:
+		return valueComparator[[]byte, *array.FixedSizeBinary](cols, bytes.Compare), nil
+	case *array.Decimal128:
+		return valueComparator[decimal128.Num, *array.Decimal128](cols, decimal128.Num.Cmp), nil
+	}
+
+	return nil, fmt.Errorf("%w: cannot sort %s values", iceberg.ErrNotImplemented, cols[0].DataType())
+}
+
+// valueComparator returns a function comparing the values of the rows of
+// the arrays, which must all be of type A, with the compare function.
+func valueComparator[T any, A interface{ Value(int) T }](cols []arrow.Array, compare func(T, T) int) func(a, b sortRow) int {
+	arrs := make([]A, len(cols))
+	for i, c := range cols {
+		arrs[i] = c.(A)
+	}
+
+	return func(a, b sortRow) int {
+		return compare(arrs[a.rec].Value(a.row), arrs[b.rec].Value(b.row))
+	}
+}
+
+// takeSortedRows returns the rows of the records in the given order. The runs of
+// rows from the same record are taken from it at once, as a slice of the
+// record when the rows of the run are contiguous.
+func takeSortedRows(ctx context.Context, recs []arrow.Record, rows []sortRow) (out []arrow.Record, err error) {
+	defer func() {
+		if err != nil {
+			for _, rec := range out {
+				rec.Release()
+			}
+			out = nil
+		}
+	}()
+
+	mem := compute.GetAllocator(ctx)
+	for len(rows) > 0 {
+		n, contiguous := 1, true
+		for n < len(rows) && rows[n].rec == rows[0].rec {
+			contiguous = contiguous && rows[n].row == rows[n-1].row+1
+			n++
+		}
+
+		run, rec := rows[:n], recs[rows[0].rec]
+		rows = rows[n:]
+
+		if contiguous {
+			out = append(out, rec.NewSlice(int64(run[0].row), int64(run[0].row+n)))
+
+			continue
+		}
+
+		bldr := array.NewInt64Builder(mem)
+		bldr.Reserve(n)
+		for _, r := range run {
+			bldr.UnsafeAppend(int64(r.row))
+		}
+		idxArr := bldr.NewArray()
+		bldr.Release()
+
+		result, err := compute.Take(ctx, *compute.DefaultTakeOptions(),
+			compute.NewDatumWithoutOwning(rec), compute.NewDatumWithoutOwning(idxArr))
+		idxArr.Release()
+		if err != nil {
+			return out, err
+		}
+
+		out = append(out, result.(*compute.RecordDatum).Value)
+	}
+
+	return out, nil
+}
+
+// arrowLiteralReader returns a function converting the values of the array
+// into iceberg literals of the provided type, an invalid optional being
+// returned for null values. The type of the array is resolved once, so
+// that the rows of a column are converted without dispatching on it.
+func arrowLiteralReader(arr arrow.Array, typ iceberg.Type) (func(row int) (iceberg.Optional[iceberg.Literal], error), error) {
+	if arr == nil {
+		return func(int) (iceberg.Optional[iceberg.Literal], error) {
+			return iceberg.Optional[iceberg.Literal]{}, nil
+		}, nil
+	}
+
+	toMicros := func(v int64, unit arrow.TimeUnit) int64 {
+		return v * int64(unit.Multiplier()) / int64(arrow.Microsecond.Multiplier())
+	}
+
+	var value func(int) iceberg.Literal
+	switch a := arr.(type) {
+	case *array.Boolean:
+		value = func(i int) iceberg.Literal { return iceberg.BoolLiteral(a.Value(i)) }
+	case *array.Int8:
+		value = func(i int) iceberg.Literal { return iceberg.Int32Literal(a.Value(i)) }
+	case *array.Int16:
+		value = func(i int) iceberg.Literal { return iceberg.Int32Literal(a.Value(i)) }
+	case *array.Int32:
+		value = func(i int) iceberg.Literal { return iceberg.Int32Literal(a.Value(i)) }
+	case *array.Int64:
+		value = func(i int) iceberg.Literal { return iceberg.Int64Literal(a.Value(i)) }
+	case *array.Uint8:
+		value = func(i int) iceberg.Literal { return iceberg.Int32Literal(a.Value(i)) }
+	case *array.Uint16:
+		value = func(i int) iceberg.Literal { return iceberg.Int32Literal(a.Value(i)) }
+	case *array.Uint32:
+		value = func(i int) iceberg.Literal { return iceberg.Int64Literal(a.Value(i)) }
+	case *array.Float32:
+		value = func(i int) iceberg.Literal { return iceberg.Float32Literal(a.Value(i)) }
+	case *array.Float64:
+		value = func(i int) iceberg.Literal { return iceberg.Float64Literal(a.Value(i)) }
+	case *array.Date32:
+		value = func(i int) iceberg.Literal { return iceberg.DateLiteral(a.Value(i)) }
+	case *array.Time32:
+		unit := a.DataType().(*arrow.Time32Type).Unit
+		value = func(i int) iceberg.Literal { return iceberg.TimeLiteral(toMicros(int64(a.Value(i)), unit)) }
+	case *array.Time64:
+		unit := a.DataType().(*arrow.Time64Type).Unit
+		value = func(i int) iceberg.Literal { return iceberg.TimeLiteral(toMicros(int64(a.Value(i)), unit)) }
+	case *array.Timestamp:
+		unit := a.DataType().(*arrow.TimestampType).Unit
+		value = func(i int) iceberg.Literal { return iceberg.TimestampLiteral(toMicros(int64(a.Value(i)), unit)) }
+	case *array.String:
+		value = func(i int) iceberg.Literal { return iceberg.StringLiteral(a.Value(i)) }
+	case *array.LargeString:
+		value = func(i int) iceberg.Literal { return iceberg.StringLiteral(a.Value(i)) }
+	case *array.StringView:
+		value = func(i int) iceberg.Literal { return iceberg.StringLiteral(a.Value(i)) }
+	case *array.Binary:
+		value = func(i int) iceberg.Literal { return iceberg.BinaryLiteral(slices.Clone(a.Value(i))) }
+	case *array.LargeBinary:
+		value = func(i int) iceberg.Literal { return iceberg.BinaryLiteral(slices.Clone(a.Value(i))) }
+	case *array.BinaryView:
+		value = func(i int) iceberg.Literal { return iceberg.BinaryLiteral(slices.Clone(a.Value(i))) }
+	case *array.FixedSizeBinary:
+		value = func(i int) iceberg.Literal { return iceberg.FixedLiteral(slices.Clone(a.Value(i))) }
+	case *extensions.UUIDArray:
+		value = func(i int) iceberg.Literal { return iceberg.UUIDLiteral(a.Value(i)) }
+	case *array.Decimal128:
+		scale := int(a.DataType().(*arrow.Decimal128Type).Scale)
+		value = func(i int) iceberg.Literal {
+			return iceberg.DecimalLiteral(iceberg.Decimal{Val: a.Value(i), Scale: scale})
+		}
+	case array.ExtensionArray:
+		return arrowLiteralReader(a.Storage(), typ)
+	default:
+		return nil, fmt.Errorf("%w: cannot partition by column of type %s",
+			iceberg.ErrNotImplemented, arr.DataType())
+	}
+
+	// whether the literals must be converted to the type, which is known
+	// once the first value is read
+	var convert *bool
+
+	return func(row int) (iceberg.Optional[iceberg.Literal], error) {
+		var out iceberg.Optional[iceberg.Literal]
+		if arr.IsNull(row) {
+			return out, nil
+		}
+
+		lit := value(row)
+		if convert == nil {
+			c := !lit.Type().Equals(typ)
+			convert = &c
+		}
+
+		if *convert {
+			converted, err := lit.To(typ)
+			if err != nil {
+				return out, err
+			}
+			lit = converted
+		}
+
+		out.Val, out.Valid = lit, true
+
+		return out, nil
+	}, nil
 }
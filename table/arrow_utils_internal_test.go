@@ -20,11 +20,15 @@ package table
 import (
 	"bytes"
 	"cmp"
+	"context"
 	"math"
 	"slices"
+	"strings"
 	"testing"
 
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/arrow-go/v18/parquet"
 	"github.com/apache/arrow-go/v18/parquet/file"
@@ -443,3 +447,112 @@ func TestStatsTypes(t *testing.T) {
 		iceberg.PrimitiveTypes.Int32,
 	}, actual)
 }
+
+func TestSortRecords(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	ctx := compute.WithAllocator(context.Background(), mem)
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "a", Type: iceberg.PrimitiveTypes.Int32},
+		iceberg.NestedField{ID: 2, Name: "s", Type: iceberg.PrimitiveTypes.String})
+	arrSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+
+	recs := make([]arrow.Record, 2)
+	for i, data := range []string{
+		`[{"a": 3, "s": "ba"}, {"a": 1, "s": "ab"}, {"a": null, "s": "bb"}]`,
+		`[{"a": 2, "s": "aa"}, {"a": 1, "s": "bc"}]`,
+	} {
+		rec, _, err := array.RecordFromJSON(mem, arrSchema, strings.NewReader(data))
+		require.NoError(t, err)
+		defer rec.Release()
+		recs[i] = rec
+	}
+
+	tests := []struct {
+		name     string
+		fields   []SortField
+		expected []string
+	}{
+		{"identity", []SortField{
+			{SourceID: 1, Transform: iceberg.IdentityTransform{}, Direction: SortASC, NullOrder: NullsLast},
+		}, []string{"ab", "bc", "aa", "ba", "bb"}},
+		{"transformed", []SortField{
+			{SourceID: 2, Transform: iceberg.TruncateTransform{Width: 1}, Direction: SortASC, NullOrder: NullsFirst},
+			{SourceID: 1, Transform: iceberg.IdentityTransform{}, Direction: SortDESC, NullOrder: NullsFirst},
+		}, []string{"aa", "ab", "bb", "ba", "bc"}},
+		{"void", []SortField{
+			{SourceID: 1, Transform: iceberg.VoidTransform{}, Direction: SortASC, NullOrder: NullsFirst},
+		}, []string{"ba", "ab", "bb", "aa", "bc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := sortRecords(ctx, sc, SortOrder{Fields: tt.fields}, recs)
+			require.NoError(t, err)
+
+			var actual []string
+			for _, rec := range sorted {
+				strs := rec.Column(1).(*array.String)
+				for i := range strs.Len() {
+					actual = append(actual, strs.Value(i))
+				}
+				rec.Release()
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestArrowLiteralReader(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	read := func(dt arrow.DataType, data string, typ iceberg.Type) []iceberg.Optional[iceberg.Literal] {
+		arr, _, err := array.FromJSON(mem, dt, strings.NewReader(data))
+		require.NoError(t, err)
+		defer arr.Release()
+
+		rdr, err := arrowLiteralReader(arr, typ)
+		require.NoError(t, err)
+
+		out := make([]iceberg.Optional[iceberg.Literal], arr.Len())
+		for i := range out {
+			out[i], err = rdr(i)
+			require.NoError(t, err)
+		}
+
+		return out
+	}
+
+	valid := func(lit iceberg.Literal) iceberg.Optional[iceberg.Literal] {
+		return iceberg.Optional[iceberg.Literal]{Valid: true, Val: lit}
+	}
+
+	// the values are converted to the type of the field
+	assert.Equal(t, []iceberg.Optional[iceberg.Literal]{
+		valid(iceberg.Int64Literal(1)), {}, valid(iceberg.Int64Literal(3)),
+	}, read(arrow.PrimitiveTypes.Int32, `[1, null, 3]`, iceberg.PrimitiveTypes.Int64))
+
+	assert.Equal(t, []iceberg.Optional[iceberg.Literal]{
+		valid(iceberg.TimestampLiteral(1_000)), valid(iceberg.TimestampLiteral(2_000)),
+	}, read(&arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}, `[1, 2]`,
+		iceberg.PrimitiveTypes.TimestampTz))
+
+	assert.Equal(t, []iceberg.Optional[iceberg.Literal]{
+		valid(iceberg.StringLiteral("a")), {},
+	}, read(arrow.BinaryTypes.String, `["a", null]`, iceberg.PrimitiveTypes.String))
+
+	// missing columns are all nulls
+	rdr, err := arrowLiteralReader(nil, iceberg.PrimitiveTypes.Int32)
+	require.NoError(t, err)
+	val, err := rdr(5)
+	require.NoError(t, err)
+	assert.False(t, val.Valid)
+
+	_, err = arrowLiteralReader(array.NewNull(1), iceberg.PrimitiveTypes.Int32)
+	assert.ErrorIs(t, err, iceberg.ErrNotImplemented)
+}
@@ -182,6 +182,28 @@ func TestArrowToIceberg(t *testing.T) {
 	}
 }
 
+func TestV3TypeToArrow(t *testing.T) {
+	tests := []struct {
+		ice iceberg.Type
+		dt  arrow.DataType
+	}{
+		{iceberg.PrimitiveTypes.TimestampNs, &arrow.TimestampType{Unit: arrow.Nanosecond}},
+		{iceberg.PrimitiveTypes.TimestampTzNs, arrow.FixedWidthTypes.Timestamp_ns},
+		{iceberg.PrimitiveTypes.Unknown, arrow.Null},
+		{iceberg.PrimitiveTypes.Variant, arrow.StructOf(
+			arrow.Field{Name: "metadata", Type: arrow.BinaryTypes.Binary},
+			arrow.Field{Name: "value", Type: arrow.BinaryTypes.Binary})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ice.String(), func(t *testing.T) {
+			result, err := table.TypeToArrowType(tt.ice, false, false)
+			require.NoError(t, err)
+			assert.True(t, arrow.TypeEqual(tt.dt, result), tt.dt.String(), result.String())
+		})
+	}
+}
+
 func TestArrowSchemaToIceberg(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,192 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/iceberg-go/internal/telemetry/metrics"
+	iceio "github.com/apache/iceberg-go/io"
+)
+
+// Cache stores the parsed contents of table files that are never modified
+// once written, such as metadata files, manifest lists and manifests, keyed
+// by their location and the identity of the IO they are read with.
+// Implementations must be safe for concurrent use and are expected to bound
+// the total size of the values they hold.
+type Cache interface {
+	// Get returns the value cached for the key, if any.
+	Get(key string) (any, bool)
+	// Add caches the value read from the file of the key, where size is
+	// the size in bytes of the file.
+	Add(key string, value any, size int64)
+}
+
+type cacheHolder struct{ Cache }
+
+var manifestCache, metadataCache atomic.Pointer[cacheHolder]
+
+// SetManifestCache sets the cache used when reading the manifest lists of
+// snapshots and the manifests read while planning scans. Passing nil, the
+// default, disables caching.
+func SetManifestCache(c Cache) { manifestCache.Store(&cacheHolder{c}) }
+
+// SetMetadataCache sets the cache used by NewFromLocation to read metadata
+// files. Passing nil, the default, disables caching.
+func SetMetadataCache(c Cache) { metadataCache.Store(&cacheHolder{c}) }
+
+func loadCache(p *atomic.Pointer[cacheHolder]) Cache {
+	if h := p.Load(); h != nil {
+		return h.Cache
+	}
+
+	return nil
+}
+
+// cacheKey returns the key of the file at the location read with the IO.
+// Files are only shared by IOs reading them from the same storage with the
+// same credentials: the ones of the same key for a KeyedIO, and otherwise
+// the same IO, or equal ones for IOs which are values. The key is empty if
+// the IO can't be identified, in which case its files aren't cached.
+//
+// IOs identified by their address are returned to be kept alive along
+// with the cached values, so that their address isn't reused by another
+// IO.
+func cacheKey(fs iceio.IO, location string) (string, iceio.IO) {
+	if k, ok := fs.(iceio.KeyedIO); ok {
+		if key := k.Key(); key != "" {
+			return key + "|" + location, nil
+		}
+	}
+
+	switch v := reflect.ValueOf(fs); {
+	case !v.IsValid():
+		return "", nil
+	case v.Kind() == reflect.Pointer:
+		return fmt.Sprintf("%T@%x|%s", fs, v.Pointer(), location), fs
+	case v.Comparable():
+		return fmt.Sprintf("%T%v|%s", fs, fs, location), nil
+	default:
+		return "", nil
+	}
+}
+
+type cacheEntry[T any] struct {
+	fs    iceio.IO
+	value T
+}
+
+// cachedRead returns the value cached for the location read with the IO,
+// calling read to read the file and cache its contents on a miss. The name
+// of the cache is used to report hits and misses.
+func cachedRead[T any](c Cache, name string, fs iceio.IO, location string, read func() (T, int64, error)) (T, error) {
+	var key string
+	if c != nil {
+		key, fs = cacheKey(fs, location)
+	}
+
+	if key == "" {
+		v, _, err := read()
+
+		return v, err
+	}
+
+	if v, ok := c.Get(key); ok {
+		if entry, ok := v.(cacheEntry[T]); ok {
+			metrics.AddCacheHit(name)
+
+			return entry.value, nil
+		}
+	}
+
+	metrics.AddCacheMiss(name)
+	v, size, err := read()
+	if err == nil {
+		c.Add(key, cacheEntry[T]{fs: fs, value: v}, size)
+	}
+
+	return v, err
+}
+
+type lruEntry struct {
+	key   string
+	value any
+	size  int64
+}
+
+type lruCache struct {
+	mx       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// NewLRUCache returns a Cache evicting the least recently used values once
+// the total size of the cached files exceeds maxBytes. Files larger than
+// maxBytes are never cached.
+func NewLRUCache(maxBytes int64) Cache {
+	return &lruCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) Get(key string) (any, bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+
+	return elem.Value.(*lruEntry).value, true
+}
+
+func (c *lruCache) Add(key string, value any, size int64) {
+	if size > c.maxBytes {
+		return
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, size: size})
+	c.size += size
+
+	for c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *lruCache) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*lruEntry)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
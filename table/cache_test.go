@@ -0,0 +1,188 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(10)
+
+	c.Add("a", 1, 4)
+	c.Add("b", 2, 4)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	// b is the least recently used
+	c.Add("c", 3, 4)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	v, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+
+	c.Add("d", 4, 11)
+	_, ok = c.Get("d")
+	assert.False(t, ok)
+
+	c.Add("a", 5, 10)
+	v, ok = c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+	_, ok = c.Get("c")
+	assert.False(t, ok)
+}
+
+type countingIO struct {
+	iceio.WriteFileIO
+
+	mx    sync.Mutex
+	opens map[string]int
+}
+
+func (c *countingIO) Open(name string) (iceio.File, error) {
+	c.mx.Lock()
+	c.opens[name]++
+	c.mx.Unlock()
+
+	return c.WriteFileIO.Open(name)
+}
+
+func (c *countingIO) manifestOpens() (lists, manifests int) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	for name, n := range c.opens {
+		switch base := filepath.Base(name); {
+		case strings.HasPrefix(base, "snap-"):
+			lists += n
+		case strings.HasSuffix(base, ".avro"):
+			manifests += n
+		}
+	}
+
+	return lists, manifests
+}
+
+func TestManifestCache(t *testing.T) {
+	location := t.TempDir()
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	meta, err := NewMetadata(sc, iceberg.UnpartitionedSpec, UnsortedSortOrder, location, nil)
+	require.NoError(t, err)
+
+	fs := &countingIO{WriteFileIO: iceio.LocalFS{}, opens: make(map[string]int)}
+	tbl := New(Identifier{"db", "cached"}, meta, filepath.Join(location, "metadata.json"),
+		func(context.Context) (iceio.IO, error) { return fs, nil }, nil)
+
+	arrSc, err := SchemaToArrowSchema(sc, nil, false, false)
+	require.NoError(t, err)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, arrSc)
+	defer bldr.Release()
+	bldr.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	rec := bldr.NewRecord()
+	defer rec.Release()
+	arrTbl := array.NewTableFromRecords(arrSc, []arrow.Record{rec})
+	defer arrTbl.Release()
+
+	ctx := context.Background()
+	tx := tbl.NewTransaction()
+	require.NoError(t, tx.AppendTable(ctx, arrTbl, 3, nil))
+	require.NoError(t, tx.AppendTable(ctx, arrTbl, 3, nil))
+	clear(fs.opens)
+
+	SetManifestCache(NewLRUCache(1 << 20))
+	t.Cleanup(func() { SetManifestCache(nil) })
+
+	for range 3 {
+		scan, err := tx.Scan()
+		require.NoError(t, err)
+
+		tasks, err := scan.PlanFiles(ctx)
+		require.NoError(t, err)
+		assert.Len(t, tasks, 2)
+	}
+
+	lists, manifests := fs.manifestOpens()
+	assert.Equal(t, 1, lists)
+	assert.Equal(t, 2, manifests)
+
+	SetManifestCache(nil)
+	scan, err := tx.Scan()
+	require.NoError(t, err)
+	_, err = scan.PlanFiles(ctx)
+	require.NoError(t, err)
+
+	lists, manifests = fs.manifestOpens()
+	assert.Equal(t, 2, lists)
+	assert.Equal(t, 4, manifests)
+}
+
+type keyedIO struct {
+	iceio.IO
+	key string
+}
+
+func (k keyedIO) Key() string { return k.key }
+
+func TestCacheScopedByIO(t *testing.T) {
+	c := NewLRUCache(1 << 20)
+	read := func(fs iceio.IO, v int) int {
+		out, err := cachedRead(c, "test", fs, "s3://bucket/file", func() (int, int64, error) {
+			return v, 1, nil
+		})
+		require.NoError(t, err)
+
+		return out
+	}
+
+	// IOs without a key only share their files with themselves
+	first, second := &countingIO{}, &countingIO{}
+	assert.Equal(t, 1, read(first, 1))
+	assert.Equal(t, 1, read(first, 2))
+	assert.Equal(t, 3, read(second, 3))
+
+	assert.Equal(t, 4, read(keyedIO{key: "a"}, 4))
+	assert.Equal(t, 4, read(keyedIO{key: "a"}, 5))
+	assert.Equal(t, 6, read(keyedIO{key: "b"}, 6))
+
+	assert.Equal(t, 7, read(iceio.LocalFS{}, 7))
+	assert.Equal(t, 7, read(iceio.LocalFS{}, 8))
+
+	// IOs which can't be compared aren't cached
+	fs := iceio.FSPreProcName(nil, func(name string) string { return name })
+	assert.Equal(t, 9, read(fs, 9))
+	assert.Equal(t, 10, read(fs, 10))
+}
@@ -0,0 +1,493 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/arrow/scalar"
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+)
+
+// ChangeType is the kind of change made to a row by a snapshot.
+type ChangeType string
+
+const (
+	ChangeInsert ChangeType = "INSERT"
+	ChangeDelete ChangeType = "DELETE"
+)
+
+// The names of the metadata columns appended to the rows read by a
+// ChangelogScan.
+const (
+	ChangeTypeColumnName       = "_change_type"
+	ChangeOrdinalColumnName    = "_change_ordinal"
+	CommitSnapshotIDColumnName = "_commit_snapshot_id"
+)
+
+// ChangelogScanTask reads the rows of a data file changed by a snapshot.
+type ChangelogScanTask struct {
+	// FileScanTask reads the data file, applying its delete files.
+	FileScanTask
+
+	ChangeType ChangeType
+	// ChangeOrdinal is the position of the snapshot making the change in
+	// the range of the scan, starting at 0 for the oldest one.
+	ChangeOrdinal    int
+	CommitSnapshotID int64
+	// AddedDeletes are the position delete files added by the snapshot
+	// for a data file it doesn't remove, in which case the rows changed
+	// are the rows they delete rather than the whole file.
+	AddedDeletes []iceberg.DataFile
+}
+
+// ChangelogScan reads the rows inserted and deleted by the snapshots which
+// are ancestors of the end snapshot, inclusive, and descendants of the start
+// snapshot, exclusive, based on the status of the manifest entries written
+// by each snapshot:
+//
+//   - the rows of the data files added are inserts, less the rows deleted
+//     by the position deletes added along with them
+//   - the live rows of the data files removed are deletes
+//   - the rows of existing data files at the positions of the position
+//     deletes added are deletes, unless they were already deleted
+//
+// Each row has the change type, ordinal and commit snapshot columns appended.
+// Equality deletes added in the range aren't supported.
+type ChangelogScan struct {
+	scan           *Scan
+	fromSnapshotID *int64
+}
+
+// ChangelogScan returns a scan of the rows changed by the snapshots of the
+// table. Without calling FromSnapshotExclusive and ToSnapshot, it reads the
+// changes of all the ancestors of the current snapshot. The options are
+// applied as for Scan, using WithSnapshotID sets the end snapshot.
+func (t Table) ChangelogScan(opts ...ScanOption) *ChangelogScan {
+	return &ChangelogScan{scan: t.Scan(opts...)}
+}
+
+// FromSnapshotExclusive sets the snapshot after which the changes are read,
+// it must be an ancestor of the end snapshot.
+func (s *ChangelogScan) FromSnapshotExclusive(id int64) *ChangelogScan {
+	out := *s
+	out.fromSnapshotID = &id
+
+	return &out
+}
+
+// ToSnapshot sets the last snapshot whose changes are read, the current
+// snapshot of the table is used by default.
+func (s *ChangelogScan) ToSnapshot(id int64) *ChangelogScan {
+	out, scan := *s, *s.scan
+	scan.snapshotID = &id
+	out.scan = &scan
+
+	return &out
+}
+
+// Projection returns the schema of the rows read, using the schema of the
+// end snapshot and without the change columns.
+func (s *ChangelogScan) Projection() (*iceberg.Schema, error) {
+	return s.scan.Projection()
+}
+
+// snapshots returns the snapshots of the range of the scan, oldest first.
+func (s *ChangelogScan) snapshots() ([]*Snapshot, error) {
+	to, err := s.scan.Snapshot()
+	if err != nil || to == nil {
+		return nil, err
+	}
+
+	snaps, err := snapshotsInRange(s.scan.metadata, s.fromSnapshotID, to)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(snaps)
+
+	return snaps, nil
+}
+
+// PlanFiles returns the tasks reading the rows changed by each snapshot in
+// the range of the scan, ordered by snapshot.
+func (s *ChangelogScan) PlanFiles(ctx context.Context) ([]ChangelogScanTask, error) {
+	snaps, err := s.snapshots()
+	if err != nil || len(snaps) == 0 {
+		return nil, err
+	}
+
+	fs, err := s.scan.ioF(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metricsEval, err := newInclusiveMetricsEvaluator(
+		s.scan.metadata.CurrentSchema(),
+		s.scan.rowFilter,
+		s.scan.caseSensitive,
+		s.scan.options["include_empty_files"] == "true",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	partitionEvaluators := newKeyDefaultMap(s.scan.buildPartitionEvaluator)
+	keep := func(df iceberg.DataFile) (bool, error) {
+		ok, err := partitionEvaluators.Get(int(df.SpecID()))(df)
+		if err != nil || !ok {
+			return false, err
+		}
+
+		return metricsEval(df)
+	}
+
+	var results []ChangelogScanTask
+	for ordinal, snap := range snaps {
+		tasks, err := s.planSnapshot(ctx, fs, snap, ordinal, keep)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tasks...)
+	}
+
+	return results, nil
+}
+
+// planSnapshot returns the tasks reading the rows changed by the snapshot.
+func (s *ChangelogScan) planSnapshot(ctx context.Context, fs iceio.IO, snap *Snapshot, ordinal int,
+	keep func(iceberg.DataFile) (bool, error),
+) ([]ChangelogScanTask, error) {
+	manifests, err := snap.Manifests(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the manifests written by the snapshot can hold the entries of
+	// the files it added or removed.
+	var added, removed, addedDeletes []iceberg.ManifestEntry
+	for _, mf := range manifests {
+		if mf.SnapshotID() != snap.SnapshotID {
+			continue
+		}
+
+		entries, err := fetchManifestEntries(fs, mf)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			if e.SnapshotID() != snap.SnapshotID {
+				continue
+			}
+
+			df := e.DataFile()
+			switch {
+			case df.ContentType() == iceberg.EntryContentEqDeletes && e.Status() == iceberg.EntryStatusADDED:
+				return nil, fmt.Errorf("%w: changelog scan of equality deletes added by snapshot %d",
+					iceberg.ErrNotImplemented, snap.SnapshotID)
+			case df.ContentType() == iceberg.EntryContentPosDeletes && e.Status() == iceberg.EntryStatusADDED:
+				addedDeletes = append(addedDeletes, e)
+			case df.ContentType() != iceberg.EntryContentData:
+			case e.Status() == iceberg.EntryStatusADDED:
+				added = append(added, e)
+			case e.Status() == iceberg.EntryStatusDELETED:
+				removed = append(removed, e)
+			}
+		}
+	}
+
+	newTask := func(e iceberg.ManifestEntry, changeType ChangeType, deletes []iceberg.DataFile) ChangelogScanTask {
+		return ChangelogScanTask{
+			FileScanTask: FileScanTask{
+				File:        e.DataFile(),
+				DeleteFiles: deletes,
+				Start:       0,
+				Length:      e.DataFile().FileSizeBytes(),
+			},
+			ChangeType:       changeType,
+			ChangeOrdinal:    ordinal,
+			CommitSnapshotID: snap.SnapshotID,
+		}
+	}
+
+	var results []ChangelogScanTask
+	for _, e := range added {
+		ok, err := keep(e.DataFile())
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		deletes, err := matchDeletesToData(e, addedDeletes)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, newTask(e, ChangeInsert, deletes))
+	}
+
+	if len(removed) == 0 && len(addedDeletes) == 0 {
+		return results, nil
+	}
+
+	// the rows deleted are the rows which were live in the parent snapshot
+	var parent *Snapshot
+	if snap.ParentSnapshotID != nil {
+		parent = s.scan.metadata.SnapshotByID(*snap.ParentSnapshotID)
+	}
+
+	live := newManifestEntries()
+	if parent != nil {
+		parentManifests, err := parent.Manifests(fs)
+		if err != nil {
+			return nil, err
+		}
+
+		if live, err = s.scan.collectManifestEntries(ctx, parentManifests); err != nil {
+			return nil, err
+		}
+	}
+
+	bySequenceNum := func(a, b iceberg.ManifestEntry) int {
+		return cmp.Compare(a.SequenceNum(), b.SequenceNum())
+	}
+	slices.SortFunc(live.positionalDeleteEntries, bySequenceNum)
+	slices.SortFunc(live.equalityDeleteEntries, bySequenceNum)
+
+	specs := s.scan.metadata.PartitionSpecs()
+	liveDeletes := func(e iceberg.ManifestEntry) ([]iceberg.DataFile, error) {
+		deletes, err := matchDeletesToData(e, live.positionalDeleteEntries)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(deletes, matchEqualityDeletesToData(e, live.equalityDeleteEntries, specs)...), nil
+	}
+
+	removedPaths := make(set[string], len(removed))
+	for _, e := range removed {
+		removedPaths[e.DataFile().FilePath()] = struct{}{}
+		ok, err := keep(e.DataFile())
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		deletes, err := liveDeletes(e)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, newTask(e, ChangeDelete, deletes))
+	}
+
+	if len(addedDeletes) == 0 {
+		return results, nil
+	}
+
+	for _, e := range live.dataEntries {
+		if _, ok := removedPaths[e.DataFile().FilePath()]; ok {
+			continue
+		}
+
+		matched, err := matchDeletesToData(e, addedDeletes)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matched) == 0 {
+			continue
+		}
+
+		deletes, err := liveDeletes(e)
+		if err != nil {
+			return nil, err
+		}
+
+		task := newTask(e, ChangeDelete, deletes)
+		task.AddedDeletes = matched
+		results = append(results, task)
+	}
+
+	return results, nil
+}
+
+// changelogSchema returns the schema of the rows read with the change
+// columns appended.
+func changelogSchema(sc *arrow.Schema) *arrow.Schema {
+	fields := append(sc.Fields(),
+		arrow.Field{Name: ChangeTypeColumnName, Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: ChangeOrdinalColumnName, Type: arrow.PrimitiveTypes.Int32},
+		arrow.Field{Name: CommitSnapshotIDColumnName, Type: arrow.PrimitiveTypes.Int64})
+
+	return arrow.NewSchema(fields, nil)
+}
+
+// withChangeColumns appends the change columns of the task to the record.
+func withChangeColumns(mem memory.Allocator, sc *arrow.Schema, rec arrow.Record, task ChangelogScanTask) (arrow.Record, error) {
+	n := int(rec.NumRows())
+	values := []scalar.Scalar{
+		scalar.NewStringScalar(string(task.ChangeType)),
+		scalar.NewInt32Scalar(int32(task.ChangeOrdinal)),
+		scalar.NewInt64Scalar(task.CommitSnapshotID),
+	}
+
+	cols := slices.Clone(rec.Columns())
+	for _, v := range values {
+		arr, err := scalar.MakeArrayFromScalar(v, n, mem)
+		if err != nil {
+			return nil, err
+		}
+		defer arr.Release()
+
+		cols = append(cols, arr)
+	}
+
+	return array.NewRecord(sc, cols, int64(n)), nil
+}
+
+// readPositions returns the positions deleted in the data file by the given
+// position delete files.
+func readPositions(ctx context.Context, fs iceio.IO, dataFile iceberg.DataFile, deleteFiles []iceberg.DataFile) (set[int64], error) {
+	positions := set[int64]{}
+	for _, df := range deleteFiles {
+		deletes, err := readDeletes(ctx, fs, df)
+		if err != nil {
+			return nil, err
+		}
+
+		for file, chunked := range deletes {
+			if file == dataFile.FilePath() {
+				for _, a := range chunked.Chunks() {
+					for _, v := range a.(*array.Int64).Int64Values() {
+						positions[v] = struct{}{}
+					}
+				}
+			}
+			chunked.Release()
+		}
+	}
+
+	return positions, nil
+}
+
+// ReadTasks reads the given tasks planned by PlanFiles, returning the schema
+// of the changed rows and an iterator over them. The tasks are read one
+// after the other, in order.
+func (s *ChangelogScan) ReadTasks(ctx context.Context, tasks []ChangelogScanTask) (*arrow.Schema, iter.Seq2[arrow.Record, error], error) {
+	as, err := s.scan.newArrowScan(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultSchema, err := as.resultSchema()
+	if err != nil {
+		return nil, nil, err
+	}
+	sc := changelogSchema(resultSchema)
+
+	limit, mem := s.scan.limit, compute.GetAllocator(ctx)
+
+	return sc, func(yield func(arrow.Record, error) bool) {
+		var read int64
+		for _, task := range tasks {
+			if limit >= 0 && read >= limit {
+				return
+			}
+
+			// each task is read separately as the deletes are combined
+			// per data file across the tasks read together.
+			taskScan := *as
+			if limit >= 0 {
+				taskScan.rowLimit = limit - read
+			}
+
+			if task.AddedDeletes != nil {
+				rows, err := readPositions(ctx, as.fs, task.File, task.AddedDeletes)
+				if err != nil {
+					yield(nil, err)
+
+					return
+				}
+				taskScan.selectedRows = rows
+			}
+
+			_, itr, err := taskScan.GetRecords(ctx, []FileScanTask{task.FileScanTask})
+			if err != nil {
+				yield(nil, err)
+
+				return
+			}
+
+			for rec, err := range itr {
+				if err != nil {
+					yield(nil, err)
+
+					return
+				}
+
+				out, err := withChangeColumns(mem, sc, rec, task)
+				rec.Release()
+				if err != nil {
+					yield(nil, err)
+
+					return
+				}
+
+				read += out.NumRows()
+				if !yield(out, nil) {
+					return
+				}
+			}
+		}
+	}, nil
+}
+
+// ToArrowRecords returns the schema of the changed rows and an iterator
+// over them, see ReadTasks.
+func (s *ChangelogScan) ToArrowRecords(ctx context.Context) (*arrow.Schema, iter.Seq2[arrow.Record, error], error) {
+	tasks, err := s.PlanFiles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.ReadTasks(ctx, tasks)
+}
+
+// ToArrowTable reads all the changed rows into an arrow table.
+func (s *ChangelogScan) ToArrowTable(ctx context.Context) (arrow.Table, error) {
+	schema, itr, err := s.ToArrowRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectRecords(schema, itr)
+}
@@ -0,0 +1,416 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"path"
+	"slices"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/compute/exprs"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/config"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table/internal"
+)
+
+// Delete removes the rows matching the filter from the table, committing
+// a snapshot with the delete operation.
+//
+// Data files whose metrics prove that every row matches the filter are
+// dropped entirely. Any other file that might contain matching rows is read
+// and the positions of the matching rows are written to position delete
+// files, which requires format version 2. Deletion vectors, which replace
+// position delete files in format version 3, are not supported yet.
+//
+// When the commit is retried after a concurrent change, files added
+// concurrently that may contain matching rows fail the commit according
+// to the isolation level of the transaction.
+func (t *Transaction) Delete(ctx context.Context, filter iceberg.BooleanExpression, snapshotProps iceberg.Properties) error {
+	if filter == nil {
+		return fmt.Errorf("%w: delete filter cannot be nil", iceberg.ErrInvalidArgument)
+	}
+
+	fs, err := t.tbl.fsF(ctx)
+	if err != nil {
+		return err
+	}
+
+	matches, err := t.matchRows(ctx, fs, filter)
+	if err != nil {
+		return err
+	}
+
+	if len(matches.files) == 0 && len(matches.partial) == 0 {
+		return nil
+	}
+
+	switch {
+	case len(matches.partial) == 0:
+	case t.meta.formatVersion < 2:
+		return fmt.Errorf("%w: deleting rows from %s requires position deletes which are not supported in format version %d",
+			ErrInvalidOperation, matches.partial[0].task.File.FilePath(), t.meta.formatVersion)
+	case t.meta.formatVersion >= 3:
+		// v3 tables must use deletion vectors instead of position delete files
+		return fmt.Errorf("%w: deleting rows from %s requires deletion vectors in format version %d",
+			iceberg.ErrNotImplemented, matches.partial[0].task.File.FilePath(), t.meta.formatVersion)
+	}
+
+	deleteFiles := t.updateSnapshot(fs, snapshotProps).delete().
+		conflictDetectionFilter(filter)
+	for _, df := range matches.files {
+		deleteFiles.deleteDataFile(df)
+	}
+	for _, m := range matches.partial {
+		deleteFiles.referenceDataFile(m.task.File)
+	}
+
+	locProvider, err := LoadLocationProvider(t.tbl.Location(), t.meta.props)
+	if err != nil {
+		return err
+	}
+
+	var fileCount atomic.Int32
+	writeDeletes := func(m partialFileMatch) (iceberg.DataFile, error) {
+		fileName := fmt.Sprintf("00000-%d-%s-deletes.parquet", fileCount.Add(1), deleteFiles.commitUuid)
+
+		return t.writePositionDeletes(ctx, fs, locProvider, fileName, m.task.File, m.positions)
+	}
+
+	nWorkers := config.EnvConfig.MaxWorkers
+	for df, err := range internal.MapExec(nWorkers, slices.Values(matches.partial), writeDeletes) {
+		if err != nil {
+			return err
+		}
+		deleteFiles.appendDataFile(df)
+	}
+
+	return t.applySnapshot(deleteFiles)
+}
+
+// partialFileMatch holds the positions of the rows of a data file that match
+// a filter when only some of its live rows do.
+type partialFileMatch struct {
+	task      FileScanTask
+	positions []int64
+}
+
+// rowMatches is the result of applying a filter to the data files of the
+// current snapshot.
+type rowMatches struct {
+	// files are the data files in which every live row matches.
+	files []iceberg.DataFile
+	// partial are the data files in which only some live rows match.
+	partial []partialFileMatch
+	// existingDeletes are the position deletes already applying to
+	// the partially matching files.
+	existingDeletes perFilePosDeletes
+}
+
+// matchRows determines which rows of the current snapshot match the filter.
+// Files whose metrics prove that every row matches are not read, while the
+// remaining files which might contain matching rows are read to find the
+// positions of the matching rows.
+func (t *Transaction) matchRows(ctx context.Context, fs iceio.IO, filter iceberg.BooleanExpression) (rowMatches, error) {
+	var result rowMatches
+	snap := t.targetSnapshot()
+	if snap == nil {
+		return result, nil
+	}
+
+	scan, err := t.Scan(WithRowFilter(filter), WithSnapshotID(snap.SnapshotID))
+	if err != nil {
+		return result, err
+	}
+
+	tasks, err := scan.PlanFiles(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	schema := t.meta.CurrentSchema()
+	allRowsMatch, err := newAllRowsMatchEvaluator(schema, filter, true)
+	if err != nil {
+		return result, err
+	}
+
+	seen := make(map[string]struct{})
+	toRead := make([]FileScanTask, 0)
+	for _, task := range tasks {
+		if _, ok := seen[task.File.FilePath()]; ok {
+			continue
+		}
+		seen[task.File.FilePath()] = struct{}{}
+
+		matches, err := allRowsMatch(task.File)
+		if err != nil {
+			return result, err
+		}
+
+		if matches {
+			result.files = append(result.files, task.File)
+		} else {
+			toRead = append(toRead, task)
+		}
+	}
+
+	if len(toRead) == 0 {
+		return result, nil
+	}
+
+	boundFilter, err := iceberg.BindExpr(schema, filter, true)
+	if err != nil {
+		return result, err
+	}
+
+	nWorkers := config.EnvConfig.MaxWorkers
+	result.existingDeletes, err = readAllDeleteFiles(ctx, fs, toRead, nWorkers)
+	if err != nil {
+		return result, err
+	}
+
+	as := &arrowScan{
+		fs:              fs,
+		projectedSchema: schema,
+		boundRowFilter:  boundFilter,
+		caseSensitive:   true,
+		nameMapping:     t.meta.NameMapping(),
+	}
+
+	type fileMatch struct {
+		partialFileMatch
+		allRows bool
+	}
+
+	findPositions := func(task FileScanTask) (fileMatch, error) {
+		deleted := set[int64]{}
+		for _, chunk := range result.existingDeletes[task.File.FilePath()] {
+			for _, a := range chunk.Chunks() {
+				for _, v := range a.(*array.Int64).Int64Values() {
+					deleted[v] = struct{}{}
+				}
+			}
+		}
+
+		positions, err := as.matchingRowPositions(ctx, task.File, deleted)
+		if err != nil {
+			return fileMatch{}, err
+		}
+
+		return fileMatch{
+			partialFileMatch: partialFileMatch{task: task, positions: positions},
+			allRows:          int64(len(positions)+len(deleted)) == task.File.Count(),
+		}, nil
+	}
+
+	for m, err := range internal.MapExec(nWorkers, slices.Values(toRead), findPositions) {
+		if err != nil {
+			return result, err
+		}
+
+		switch {
+		case len(m.positions) == 0:
+		case m.allRows:
+			result.files = append(result.files, m.task.File)
+		default:
+			result.partial = append(result.partial, m.partialFileMatch)
+		}
+	}
+
+	return result, nil
+}
+
+// survivingRows returns an iterator over the rows of the partially matching
+// files that do not match the filter, with any existing deletes applied, so
+// that they can be rewritten into new data files.
+func (t *Transaction) survivingRows(ctx context.Context, fs iceio.IO, matches rowMatches) (*arrow.Schema, iter.Seq2[arrow.Record, error], error) {
+	mem := compute.GetAllocator(ctx)
+	tasks := make([]FileScanTask, 0, len(matches.partial))
+	deletesPerFile := make(perFilePosDeletes)
+	for _, m := range matches.partial {
+		tasks = append(tasks, m.task)
+
+		bldr := array.NewInt64Builder(mem)
+		bldr.AppendValues(m.positions, nil)
+		positions := bldr.NewArray()
+		bldr.Release()
+
+		path := m.task.File.FilePath()
+		deletesPerFile[path] = append(slices.Clone(matches.existingDeletes[path]),
+			arrow.NewChunked(arrow.PrimitiveTypes.Int64, []arrow.Array{positions}))
+		positions.Release()
+	}
+
+	schema := t.meta.CurrentSchema()
+	as := &arrowScan{
+		fs:              fs,
+		projectedSchema: schema,
+		boundRowFilter:  iceberg.AlwaysTrue{},
+		caseSensitive:   true,
+		rowLimit:        ScanNoLimit,
+		concurrency:     config.EnvConfig.MaxWorkers,
+		nameMapping:     t.meta.NameMapping(),
+	}
+
+	eqDeletesPerFile, err := readAllEqualityDeleteFiles(ctx, fs, schema, tasks, as.concurrency, as.nameMapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sc, err := SchemaToArrowSchema(schema, nil, false, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sc, as.recordBatchesFromTasksAndDeletes(ctx, tasks, deletesPerFile, eqDeletesPerFile), nil
+}
+
+// matchingRowPositions reads the columns referenced by the bound row filter
+// from the data file and returns the positions of the rows that match it,
+// skipping any positions which are already deleted.
+func (as *arrowScan) matchingRowPositions(ctx context.Context, file iceberg.DataFile, deleted set[int64]) ([]int64, error) {
+	ids, err := iceberg.ExtractFieldIDs(as.boundRowFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	idset := set[int]{}
+	for _, id := range ids {
+		idset[id] = struct{}{}
+	}
+
+	fileSchema, colIndices, rdr, err := as.prepareToRead(ctx, idset, file)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	ctx, recordFilter, neverMatches, err := as.translateRecordFilter(ctx, fileSchema)
+	if err != nil || neverMatches {
+		return nil, err
+	}
+
+	positions := make([]int64, 0)
+	if recordFilter == nil {
+		for pos := range file.Count() {
+			if _, ok := deleted[pos]; !ok {
+				positions = append(positions, pos)
+			}
+		}
+
+		return positions, nil
+	}
+
+	recRdr, err := rdr.GetRecords(ctx, colIndices, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer recRdr.Release()
+
+	var offset int64
+	for recRdr.Next() {
+		rec := recRdr.Record()
+		mask, err := exprs.ExecuteScalarExpression(ctx, rec.Schema(), recordFilter,
+			compute.NewDatumWithoutOwning(rec))
+		if err != nil {
+			return nil, err
+		}
+
+		matches := mask.(*compute.ArrayDatum).MakeArray().(*array.Boolean)
+		for i := range matches.Len() {
+			pos := offset + int64(i)
+			if matches.IsValid(i) && matches.Value(i) {
+				if _, ok := deleted[pos]; !ok {
+					positions = append(positions, pos)
+				}
+			}
+		}
+		offset += rec.NumRows()
+
+		matches.Release()
+		mask.Release()
+	}
+
+	if err := recRdr.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+// writePositionDeletes writes a position delete file, in the same partition
+// as the data file, marking the given positions of the data file as deleted.
+func (t *Transaction) writePositionDeletes(ctx context.Context, fs iceio.IO, locProvider LocationProvider, fileName string, dataFile iceberg.DataFile, positions []int64) (iceberg.DataFile, error) {
+	spec, err := t.meta.GetSpecByID(int(dataFile.SpecID()))
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := SchemaToArrowSchema(iceberg.PositionalDeleteSchema, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	mem := compute.GetAllocator(ctx)
+	bldr := array.NewRecordBuilder(mem, sc)
+	defer bldr.Release()
+
+	pathBldr := bldr.Field(0).(*array.StringBuilder)
+	for range positions {
+		pathBldr.Append(dataFile.FilePath())
+	}
+	bldr.Field(1).(*array.Int64Builder).AppendValues(positions, nil)
+
+	rec := bldr.NewRecord()
+	defer rec.Release()
+
+	// position delete files always keep full metrics for the file_path
+	// column, so that they can be matched to the data files they apply to.
+	statsCols, err := computeStatsPlan(iceberg.PositionalDeleteSchema,
+		iceberg.Properties{DefaultWriteMetricsModeKey: "full"})
+	if err != nil {
+		return nil, err
+	}
+
+	if !spec.IsUnpartitioned() {
+		schema := t.meta.CurrentSchema()
+		partitionPath := spec.PartitionToPath(
+			getPartitionRecord(dataFile, spec.PartitionType(schema)), schema)
+		fileName = path.Join(partitionPath, fileName)
+	}
+
+	format := internal.GetFileFormat(iceberg.ParquetFile)
+
+	return format.WriteDataFile(ctx, fs.(iceio.WriteFileIO), internal.WriteFileInfo{
+		FileSchema:      iceberg.PositionalDeleteSchema,
+		Spec:            *spec,
+		FileName:        locProvider.NewDataLocation(fileName),
+		StatsCols:       statsCols,
+		WriteProps:      format.GetWriteProperties(t.meta.props),
+		PartitionValues: dataFile.Partition(),
+		Content:         iceberg.EntryContentPosDeletes,
+	}, []arrow.Record{rec})
+}
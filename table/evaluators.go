@@ -18,12 +18,16 @@
 package table
 
 import (
+	"cmp"
 	"fmt"
 	"math"
 	"slices"
 
+	"github.com/apache/arrow-go/v18/parquet"
 	"github.com/apache/arrow-go/v18/parquet/metadata"
+	"github.com/apache/arrow-go/v18/parquet/schema"
 	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table/internal"
 	"github.com/google/uuid"
 )
 
@@ -683,6 +687,54 @@ func newInclusiveMetricsEvaluator(s *iceberg.Schema, expr iceberg.BooleanExpress
 	}).Eval, nil
 }
 
+// newAllRowsMatchEvaluator returns a function reporting whether the metrics
+// of a data file prove that every row in it matches expr. That is the case
+// when the inclusive metrics evaluator shows that no row can match the
+// negation of expr, as long as the referenced columns are known to contain
+// no null or NaN values, since those match neither expr nor its negation.
+func newAllRowsMatchEvaluator(s *iceberg.Schema, expr iceberg.BooleanExpression,
+	caseSensitive bool,
+) (func(iceberg.DataFile) (bool, error), error) {
+	bound, err := iceberg.BindExpr(s, expr, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldIDs, err := iceberg.ExtractFieldIDs(bound)
+	if err != nil {
+		return nil, err
+	}
+
+	negatedMightMatch, err := newInclusiveMetricsEvaluator(s, iceberg.NewNot(expr),
+		caseSensitive, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(df iceberg.DataFile) (bool, error) {
+		for _, id := range fieldIDs {
+			if cnt, ok := df.NullValueCounts()[id]; !ok || cnt > 0 {
+				return false, nil
+			}
+
+			typ, _ := s.FindTypeByID(id)
+			switch typ.(type) {
+			case iceberg.Float32Type, iceberg.Float64Type:
+				if cnt, ok := df.NaNValueCounts()[id]; !ok || cnt > 0 {
+					return false, nil
+				}
+			}
+		}
+
+		mightMatch, err := negatedMightMatch(df)
+		if err != nil {
+			return false, err
+		}
+
+		return !mightMatch, nil
+	}, nil
+}
+
 func newParquetRowGroupStatsEvaluator(fileSchema *iceberg.Schema, expr iceberg.BooleanExpression,
 	includeEmptyFiles bool,
 ) (func(*metadata.RowGroupMetaData, []int) (bool, error), error) {
@@ -1195,3 +1247,308 @@ func (m *inclusiveMetricsEval) VisitNotStartsWith(t iceberg.BoundTerm, lit icebe
 
 	return rowsMightMatch
 }
+
+// newParquetRowSelector returns the function selecting the rows of a parquet
+// file that might match the given filter. On top of the row group
+// statistics, the bloom filters of the columns are checked for equality and
+// IN predicates, and the column indexes are used to only read the pages that
+// might contain matching rows.
+func newParquetRowSelector(fileSchema *iceberg.Schema, expr iceberg.BooleanExpression) (internal.ParquetRowSelector, error) {
+	testStats, err := newParquetRowGroupStatsEvaluator(fileSchema, expr, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten, err := iceberg.RewriteNotExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(rg internal.ParquetRowGroup, colIndices []int) (bool, []internal.RowRange, error) {
+		if use, err := testStats(rg.RowGroupMetaData, colIndices); !use || err != nil {
+			return false, nil, err
+		}
+
+		cols := make(map[int]int, len(colIndices))
+		for _, c := range colIndices {
+			cols[int(rg.Schema.Column(c).SchemaNode().FieldID())] = c
+		}
+
+		use, err := iceberg.VisitExpr(rewritten, &bloomFilterEval{
+			rg: rg, fileSchema: fileSchema, cols: cols,
+			filters: make(map[int]metadata.BloomFilter),
+		})
+		if !use || err != nil {
+			return false, nil, err
+		}
+
+		pageIndex, err := rg.PageIndex()
+		if pageIndex == nil || err != nil {
+			return err == nil, nil, err
+		}
+
+		ranges, err := iceberg.VisitExpr(rewritten, &pageIndexEval{
+			rg: rg, idx: pageIndex, cols: cols,
+		})
+		switch {
+		case err != nil:
+			return false, nil, err
+		case len(ranges) == 0:
+			return rowsCannotMatch, nil, nil
+		case len(ranges) == 1 && ranges[0].Len() == rg.NumRows():
+			return rowsMightMatch, nil, nil
+		}
+
+		return rowsMightMatch, ranges, nil
+	}, nil
+}
+
+// bloomFilterEval tests whether a row group might contain rows matching an
+// expression using the bloom filters of its columns. Only equality and IN
+// predicates can be evaluated, any other predicate might match.
+type bloomFilterEval struct {
+	rg         internal.ParquetRowGroup
+	fileSchema *iceberg.Schema
+	// column index of each field ID
+	cols    map[int]int
+	filters map[int]metadata.BloomFilter
+}
+
+func (e *bloomFilterEval) VisitTrue() bool  { return rowsMightMatch }
+func (e *bloomFilterEval) VisitFalse() bool { return rowsCannotMatch }
+func (e *bloomFilterEval) VisitNot(child bool) bool {
+	panic(fmt.Errorf("%w: NOT should be rewritten %v", iceberg.ErrInvalidArgument, child))
+}
+func (e *bloomFilterEval) VisitAnd(left, right bool) bool { return left && right }
+func (e *bloomFilterEval) VisitOr(left, right bool) bool  { return left || right }
+
+func (e *bloomFilterEval) VisitUnbound(iceberg.UnboundPredicate) bool {
+	panic("need bound predicate")
+}
+
+func (e *bloomFilterEval) VisitBound(pred iceberg.BoundPredicate) bool {
+	switch pred.Op() {
+	case iceberg.OpEQ:
+		return e.mightContain(pred.Term(), pred.(iceberg.BoundLiteralPredicate).Literal())
+	case iceberg.OpIn:
+		for _, lit := range pred.(iceberg.BoundSetPredicate).Literals().Members() {
+			if e.mightContain(pred.Term(), lit) {
+				return rowsMightMatch
+			}
+		}
+
+		return rowsCannotMatch
+	}
+
+	return rowsMightMatch
+}
+
+func (e *bloomFilterEval) mightContain(term iceberg.BoundTerm, lit iceberg.Literal) bool {
+	field := term.Ref().Field()
+	col, ok := e.cols[field.ID]
+	if !ok {
+		return rowsMightMatch
+	}
+
+	// the literal can only be hashed the way the file stores the values
+	// if the column wasn't promoted to another type since then.
+	if typ, ok := e.fileSchema.FindTypeByID(field.ID); !ok || !typ.Equals(field.Type) {
+		return rowsMightMatch
+	}
+
+	value, ok := bloomFilterValue(lit, e.rg.Schema.Column(col))
+	if !ok {
+		return rowsMightMatch
+	}
+
+	bf, ok := e.filters[col]
+	if !ok {
+		var err error
+		if bf, err = e.rg.BloomFilter(col); err != nil {
+			panic(err)
+		}
+		e.filters[col] = bf
+	}
+
+	if bf == nil {
+		return rowsMightMatch
+	}
+
+	return bf.CheckHash(bf.Hasher().Sum64(value))
+}
+
+// bloomFilterValue returns the plain encoded value of the literal as it is
+// hashed in the bloom filters of the given parquet column, or false if the
+// value can't be determined from the literal.
+func bloomFilterValue(lit iceberg.Literal, col *schema.Column) ([]byte, bool) {
+	var size int
+	switch col.PhysicalType() {
+	case parquet.Types.Int32, parquet.Types.Float:
+		size = 4
+	case parquet.Types.Int64, parquet.Types.Double:
+		size = 8
+	case parquet.Types.FixedLenByteArray:
+		size = col.TypeLength()
+	case parquet.Types.ByteArray:
+	default:
+		return nil, false
+	}
+
+	switch lit.Type().(type) {
+	case iceberg.DecimalType:
+		// decimals are stored as unscaled little endian integers or
+		// fixed length big endian values, never as their minimal encoding
+		return nil, false
+	case iceberg.TimeType:
+		if lt, ok := col.LogicalType().(*schema.TimeLogicalType); !ok || lt.TimeUnit() != schema.TimeUnitMicros {
+			return nil, false
+		}
+	case iceberg.TimestampType, iceberg.TimestampTzType:
+		if lt, ok := col.LogicalType().(*schema.TimestampLogicalType); !ok || lt.TimeUnit() != schema.TimeUnitMicros {
+			return nil, false
+		}
+	case iceberg.TimestampNsType, iceberg.TimestampTzNsType:
+		if lt, ok := col.LogicalType().(*schema.TimestampLogicalType); !ok || lt.TimeUnit() != schema.TimeUnitNanos {
+			return nil, false
+		}
+	}
+
+	value, err := lit.MarshalBinary()
+	if err != nil || (size > 0 && len(value) != size) {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// pageIndexEval computes the ranges of rows of a row group that might match
+// an expression using the column indexes of its columns. Each predicate
+// selects the pages of its column whose statistics don't exclude a match,
+// which are then combined according to the expression.
+type pageIndexEval struct {
+	rg  internal.ParquetRowGroup
+	idx *metadata.RowGroupPageIndexReader
+	// column index of each field ID
+	cols map[int]int
+}
+
+func (e *pageIndexEval) allRows() []internal.RowRange {
+	return []internal.RowRange{{Start: 0, End: e.rg.NumRows()}}
+}
+
+func (e *pageIndexEval) VisitTrue() []internal.RowRange  { return e.allRows() }
+func (e *pageIndexEval) VisitFalse() []internal.RowRange { return nil }
+func (e *pageIndexEval) VisitNot([]internal.RowRange) []internal.RowRange {
+	panic(fmt.Errorf("%w: NOT should be rewritten", iceberg.ErrInvalidArgument))
+}
+
+func (e *pageIndexEval) VisitAnd(left, right []internal.RowRange) []internal.RowRange {
+	var out []internal.RowRange
+	for i, j := 0, 0; i < len(left) && j < len(right); {
+		start, end := max(left[i].Start, right[j].Start), min(left[i].End, right[j].End)
+		if start < end {
+			out = append(out, internal.RowRange{Start: start, End: end})
+		}
+
+		if left[i].End < right[j].End {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return out
+}
+
+func (e *pageIndexEval) VisitOr(left, right []internal.RowRange) []internal.RowRange {
+	all := slices.SortedFunc(slices.Values(slices.Concat(left, right)),
+		func(a, b internal.RowRange) int { return cmp.Compare(a.Start, b.Start) })
+
+	var out []internal.RowRange
+	for _, r := range all {
+		out = appendRowRange(out, r)
+	}
+
+	return out
+}
+
+func (e *pageIndexEval) VisitUnbound(iceberg.UnboundPredicate) []internal.RowRange {
+	panic("need bound predicate")
+}
+
+func (e *pageIndexEval) VisitBound(pred iceberg.BoundPredicate) []internal.RowRange {
+	fieldID := pred.Ref().Field().ID
+	col, ok := e.cols[fieldID]
+	// pages of repeated columns don't map to rows
+	if !ok || e.rg.Schema.Column(col).MaxRepetitionLevel() > 0 {
+		return e.allRows()
+	}
+
+	colIndex, err := e.idx.GetColumnIndex(col)
+	if err != nil {
+		panic(err)
+	}
+
+	offsetIndex, err := e.idx.GetOffsetIndex(col)
+	if err != nil {
+		panic(err)
+	}
+
+	if colIndex == nil || offsetIndex == nil {
+		return e.allRows()
+	}
+
+	var (
+		pages      = offsetIndex.GetPageLocations()
+		nullPages  = colIndex.GetNullPages()
+		nullCounts = colIndex.GetNullCounts()
+		mins, maxs = colIndex.GetMinValues(), colIndex.GetMaxValues()
+		out        []internal.RowRange
+	)
+
+	if len(nullPages) != len(pages) || len(mins) != len(pages) || len(maxs) != len(pages) ||
+		(colIndex.IsSetNullCounts() && len(nullCounts) != len(pages)) {
+		return e.allRows()
+	}
+
+	for i, page := range pages {
+		rows := internal.RowRange{Start: page.FirstRowIndex, End: e.rg.NumRows()}
+		if i+1 < len(pages) {
+			rows.End = pages[i+1].FirstRowIndex
+		}
+
+		ev := inclusiveMetricsEval{}
+		ev.valueCounts = map[int]int64{fieldID: rows.Len()}
+		ev.nullCounts = make(map[int]int64)
+		ev.lowerBounds, ev.upperBounds = make(map[int][]byte), make(map[int][]byte)
+
+		switch {
+		case colIndex.IsSetNullCounts():
+			ev.nullCounts[fieldID] = nullCounts[i]
+		case nullPages[i]:
+			ev.nullCounts[fieldID] = rows.Len()
+		}
+
+		if !nullPages[i] {
+			ev.lowerBounds[fieldID], ev.upperBounds[fieldID] = mins[i], maxs[i]
+		}
+
+		if iceberg.VisitBoundPredicate(pred, &ev) {
+			out = appendRowRange(out, rows)
+		}
+	}
+
+	return out
+}
+
+// appendRowRange appends r to the sorted ranges, merging it with the last
+// range if they overlap or are adjacent.
+func appendRowRange(ranges []internal.RowRange, r internal.RowRange) []internal.RowRange {
+	if n := len(ranges); n > 0 && r.Start <= ranges[n-1].End {
+		ranges[n-1].End = max(ranges[n-1].End, r.End)
+
+		return ranges
+	}
+
+	return append(ranges, r)
+}
@@ -1102,6 +1102,10 @@ func (*mockDataFile) KeyMetadata() []byte                       { return nil }
 func (*mockDataFile) SplitOffsets() []int64                     { return nil }
 func (*mockDataFile) EqualityFieldIDs() []int                   { return nil }
 func (*mockDataFile) SortOrderID() *int                         { return nil }
+func (*mockDataFile) FirstRowID() *int64                        { return nil }
+func (*mockDataFile) ReferencedDataFile() *string               { return nil }
+func (*mockDataFile) ContentOffset() *int64                     { return nil }
+func (*mockDataFile) ContentSizeInBytes() *int64                { return nil }
 func (m *mockDataFile) SpecID() int32                           { return m.specid }
 
 type InclusiveMetricsTestSuite struct {
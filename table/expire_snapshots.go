@@ -0,0 +1,501 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/io"
+)
+
+type expireSnapshotsCfg struct {
+	olderThanMs        *int64
+	minSnapshotsToKeep *int
+	cleanExpiredFiles  bool
+}
+
+type ExpireSnapshotsOpt func(*expireSnapshotsCfg)
+
+// WithExpireOlderThan expires snapshots with a timestamp older than the
+// given time, overriding the history.expire.max-snapshot-age-ms table
+// property for branches that do not set their own max snapshot age.
+func WithExpireOlderThan(ts time.Time) ExpireSnapshotsOpt {
+	return func(cfg *expireSnapshotsCfg) {
+		ms := ts.UnixMilli()
+		cfg.olderThanMs = &ms
+	}
+}
+
+// WithRetainLast keeps at least n ancestors of each branch, overriding the
+// history.expire.min-snapshots-to-keep table property for branches that do
+// not set their own minimum.
+func WithRetainLast(n int) ExpireSnapshotsOpt {
+	return func(cfg *expireSnapshotsCfg) {
+		cfg.minSnapshotsToKeep = &n
+	}
+}
+
+// WithCleanExpiredFiles controls whether manifest lists, manifests and data
+// files that are no longer reachable are removed once the transaction is
+// committed. Defaults to true.
+func WithCleanExpiredFiles(clean bool) ExpireSnapshotsOpt {
+	return func(cfg *expireSnapshotsCfg) {
+		cfg.cleanExpiredFiles = clean
+	}
+}
+
+// ExpireSnapshots removes snapshots that are no longer needed from the table
+// metadata along with any snapshot refs that exceeded their max-ref-age-ms.
+//
+// For each branch, ancestors are retained while either fewer than the
+// branch's min-snapshots-to-keep have been kept or they are newer than the
+// branch's max-snapshot-age-ms. Tagged snapshots are always retained, and
+// snapshots that are not referenced by any branch or tag are retained only
+// if they are newer than the table's expiration threshold. The current
+// snapshot is never expired.
+//
+// Unless disabled with [WithCleanExpiredFiles], files that are only
+// reachable from the expired snapshots are deleted after the transaction
+// is successfully committed.
+func (t *Transaction) ExpireSnapshots(opts ...ExpireSnapshotsOpt) error {
+	cfg := expireSnapshotsCfg{cleanExpiredFiles: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var (
+		nowMs            = time.Now().UnixMilli()
+		props            = t.meta.props
+		defaultOlderThan = nowMs - int64(props.GetInt(MaxSnapshotAgeMsKey, MaxSnapshotAgeMsDefault))
+		defaultMinKeep   = props.GetInt(MinSnapshotsToKeepKey, MinSnapshotsToKeepDefault)
+		defaultMaxRefAge = int64(props.GetInt(MaxRefAgeMsKey, MaxRefAgeMsDefault))
+	)
+
+	if cfg.olderThanMs != nil {
+		defaultOlderThan = *cfg.olderThanMs
+	}
+	if cfg.minSnapshotsToKeep != nil {
+		defaultMinKeep = *cfg.minSnapshotsToKeep
+	}
+	if defaultMinKeep < 1 {
+		return fmt.Errorf("%w: min snapshots to keep must be at least 1, got %d",
+			iceberg.ErrInvalidArgument, defaultMinKeep)
+	}
+
+	snapshotByID := func(id int64) *Snapshot {
+		s, err := t.meta.SnapshotByID(id)
+		if err != nil {
+			return nil
+		}
+
+		return s
+	}
+
+	refs := maps.Clone(t.meta.refs)
+	if _, ok := refs[MainBranch]; !ok && t.meta.currentSnapshotID != nil {
+		refs[MainBranch] = SnapshotRef{SnapshotID: *t.meta.currentSnapshotID, SnapshotRefType: BranchRef}
+	}
+
+	updates := make([]Update, 0)
+	retainedRefs := make(map[string]SnapshotRef)
+	for name, ref := range refs {
+		snap := snapshotByID(ref.SnapshotID)
+		if name != MainBranch {
+			maxRefAge := defaultMaxRefAge
+			if ref.MaxRefAgeMs != nil {
+				maxRefAge = *ref.MaxRefAgeMs
+			}
+
+			if snap == nil || nowMs-snap.TimestampMs > maxRefAge {
+				updates = append(updates, NewRemoveSnapshotRefUpdate(name))
+
+				continue
+			}
+		}
+		retainedRefs[name] = ref
+	}
+
+	retained := make(map[int64]struct{})
+	referenced := make(map[int64]struct{})
+	for _, ref := range retainedRefs {
+		if ref.SnapshotRefType == TagRef {
+			retained[ref.SnapshotID] = struct{}{}
+			referenced[ref.SnapshotID] = struct{}{}
+
+			continue
+		}
+
+		minKeep := defaultMinKeep
+		if ref.MinSnapshotsToKeep != nil {
+			minKeep = *ref.MinSnapshotsToKeep
+		}
+		olderThan := defaultOlderThan
+		if ref.MaxSnapshotAgeMs != nil {
+			olderThan = nowMs - *ref.MaxSnapshotAgeMs
+		}
+
+		kept := 0
+		for snap := snapshotByID(ref.SnapshotID); snap != nil; {
+			referenced[snap.SnapshotID] = struct{}{}
+			if kept < minKeep || snap.TimestampMs >= olderThan {
+				retained[snap.SnapshotID] = struct{}{}
+				kept++
+			}
+
+			if snap.ParentSnapshotID == nil {
+				break
+			}
+			snap = snapshotByID(*snap.ParentSnapshotID)
+		}
+	}
+
+	if t.meta.currentSnapshotID != nil {
+		retained[*t.meta.currentSnapshotID] = struct{}{}
+	}
+
+	toRemove := make([]int64, 0)
+	for _, snap := range t.meta.snapshotList {
+		if _, ok := retained[snap.SnapshotID]; ok {
+			continue
+		}
+
+		if _, ok := referenced[snap.SnapshotID]; !ok && snap.TimestampMs >= defaultOlderThan {
+			continue
+		}
+
+		toRemove = append(toRemove, snap.SnapshotID)
+	}
+
+	if len(toRemove) > 0 {
+		updates = append(updates, NewRemoveSnapshotsUpdate(toRemove))
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	if err := t.apply(updates, nil); err != nil {
+		return err
+	}
+
+	t.cleanExpiredFiles = t.cleanExpiredFiles || (cfg.cleanExpiredFiles && len(toRemove) > 0)
+
+	return nil
+}
+
+// deleteExpiredFiles removes the manifest lists, manifests and data files that
+// were reachable from the snapshots of baseMeta but are no longer reachable
+// from any snapshot of newMeta. Like deleteOldMetadata, failures to remove a
+// file are logged instead of returned since the commit already succeeded.
+func deleteExpiredFiles(fs io.IO, baseMeta, newMeta Metadata) error {
+	valid := make(map[int64]struct{})
+	for _, s := range newMeta.Snapshots() {
+		valid[s.SnapshotID] = struct{}{}
+	}
+
+	expired := slices.DeleteFunc(slices.Clone(baseMeta.Snapshots()), func(s Snapshot) bool {
+		_, ok := valid[s.SnapshotID]
+
+		return ok
+	})
+	if len(expired) == 0 {
+		return nil
+	}
+
+	validManifests := make(map[string]iceberg.ManifestFile)
+	validFiles := make(map[string]struct{})
+	for _, s := range newMeta.Snapshots() {
+		manifests, err := s.Manifests(fs)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range manifests {
+			if _, ok := validManifests[m.FilePath()]; ok {
+				continue
+			}
+			validManifests[m.FilePath()] = m
+
+			entries, err := m.FetchEntries(fs, true)
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				validFiles[e.DataFile().FilePath()] = struct{}{}
+			}
+		}
+	}
+
+	toDelete := make([]string, 0)
+	seenManifests := make(map[string]struct{})
+	for _, s := range expired {
+		manifests, err := s.Manifests(fs)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range manifests {
+			if _, ok := validManifests[m.FilePath()]; ok {
+				continue
+			}
+			if _, ok := seenManifests[m.FilePath()]; ok {
+				continue
+			}
+			seenManifests[m.FilePath()] = struct{}{}
+
+			entries, err := m.FetchEntries(fs, false)
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				if _, ok := validFiles[e.DataFile().FilePath()]; !ok {
+					validFiles[e.DataFile().FilePath()] = struct{}{}
+					toDelete = append(toDelete, e.DataFile().FilePath())
+				}
+			}
+			toDelete = append(toDelete, m.FilePath())
+		}
+
+		if s.ManifestList != "" {
+			toDelete = append(toDelete, s.ManifestList)
+		}
+	}
+
+	for _, file := range toDelete {
+		if err := fs.Remove(file); err != nil {
+			log.Printf("Warning: Failed to delete expired file: %s error: %v", file, err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveOrphanFiles lists every file under the table location and removes the
+// ones that are not referenced by the table metadata: the current and
+// previous metadata files, the version hint of hadoop tables, the statistics
+// files, and the manifest lists, manifests, data files and delete files of
+// every snapshot. Only files last modified before olderThan
+// are removed so that files of in-progress writes are left untouched.
+//
+// The table's IO must support listing directories, either by implementing
+// [io.ListIO] or by returning an [io.ReadDirFile] when opening a directory.
+// The files are removed in bulk if the IO implements [io.BulkDeleteIO]. The
+// paths of the removed files are returned, along with the error of the files
+// which could not be removed if any.
+func (t Table) RemoveOrphanFiles(ctx context.Context, olderThan time.Time) ([]string, error) {
+	fsys, err := t.fsF(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	referenced, err := t.referencedFiles(ctx, fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := make([]string, 0)
+	addOrphan := func(name string, modTime time.Time) {
+		if _, ok := referenced[normalizeFilePath(name)]; ok {
+			return
+		}
+		if !modTime.Before(olderThan) {
+			return
+		}
+		orphans = append(orphans, name)
+	}
+
+	location := strings.TrimSuffix(t.Location(), "/")
+	if lister, ok := fsys.(io.ListIO); ok {
+		for f, err := range lister.List(location) {
+			if err != nil {
+				return nil, err
+			}
+			addOrphan(f.Location, f.ModTime)
+		}
+	} else {
+		err = walkFiles(fsys, location, func(name string, info fs.FileInfo) error {
+			addOrphan(name, info.ModTime())
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if bulk, ok := fsys.(io.BulkDeleteIO); ok {
+		removed, err := bulk.DeleteFiles(orphans)
+		if err != nil {
+			return removed, fmt.Errorf("failed to delete orphan files: %w", err)
+		}
+
+		return removed, nil
+	}
+
+	var (
+		removed = make([]string, 0, len(orphans))
+		errs    []error
+	)
+	for _, name := range orphans {
+		if err := ctx.Err(); err != nil {
+			return removed, errors.Join(append(errs, err)...)
+		}
+
+		if err := fsys.Remove(name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete orphan file %s: %w", name, err))
+
+			continue
+		}
+		removed = append(removed, name)
+	}
+
+	return removed, errors.Join(errs...)
+}
+
+// versionHintFile is the file holding the version of the current metadata
+// file of hadoop tables.
+const versionHintFile = "version-hint.text"
+
+func (t Table) referencedFiles(ctx context.Context, fsys io.IO) (map[string]struct{}, error) {
+	referenced := make(map[string]struct{})
+	add := func(p string) { referenced[normalizeFilePath(p)] = struct{}{} }
+
+	if t.metadataLocation != "" {
+		add(t.metadataLocation)
+		// hadoop tables point at their current metadata file with a
+		// version hint next to it
+		add(t.metadataLocation[:strings.LastIndex(t.metadataLocation, "/")+1] + versionHintFile)
+	}
+	locProvider, err := LoadLocationProvider(t.Location(), t.metadata.Properties())
+	if err != nil {
+		return nil, err
+	}
+	add(locProvider.NewMetadataLocation(versionHintFile))
+
+	for entry := range t.metadata.PreviousFiles() {
+		add(entry.MetadataFile)
+	}
+	for stats := range t.metadata.Statistics() {
+		add(stats.StatisticsPath)
+	}
+	for stats := range t.metadata.PartitionStatistics() {
+		add(stats.StatisticsPath)
+	}
+
+	seenManifests := make(map[string]struct{})
+	for _, s := range t.metadata.Snapshots() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if s.ManifestList != "" {
+			add(s.ManifestList)
+		}
+
+		manifests, err := s.Manifests(fsys)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range manifests {
+			if _, ok := seenManifests[m.FilePath()]; ok {
+				continue
+			}
+			seenManifests[m.FilePath()] = struct{}{}
+			add(m.FilePath())
+
+			entries, err := m.FetchEntries(fsys, false)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range entries {
+				add(e.DataFile().FilePath())
+			}
+		}
+	}
+
+	return referenced, nil
+}
+
+// walkFiles recursively lists the regular files under dir calling fn for each.
+func walkFiles(fsys io.IO, dir string, fn func(name string, info fs.FileInfo) error) error {
+	f, err := fsys.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rdr, ok := f.(io.ReadDirFile)
+	if !ok {
+		return fmt.Errorf("%w: listing directories is not supported by %T",
+			iceberg.ErrNotImplemented, fsys)
+	}
+
+	entries, err := rdr.ReadDir(-1)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := dir + "/" + entry.Name()
+		if entry.IsDir() {
+			if err := walkFiles(fsys, name, fn); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if err := fn(name, info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeFilePath strips the scheme from a file location so that paths
+// written with and without a scheme, such as file:///tmp/a and /tmp/a,
+// compare equal.
+func normalizeFilePath(p string) string {
+	u, err := url.Parse(p)
+	if err != nil || u.Scheme == "" {
+		return path.Clean(p)
+	}
+
+	return path.Clean(u.Host + "/" + u.Path)
+}
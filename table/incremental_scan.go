@@ -0,0 +1,252 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/iceberg-go"
+)
+
+// IncrementalAppendScan reads the rows appended to a table between two
+// snapshots: the data files added by the append snapshots which are
+// ancestors of the end snapshot, inclusive, and descendants of the start
+// snapshot, exclusive.
+//
+// Replace snapshots in the range, such as compactions, are ignored as they
+// don't change the data of the table. Overwrite and delete snapshots fail
+// the scan unless SkipNonAppendSnapshots is used, in which case the files
+// they add are not read and the rows they remove are still returned.
+type IncrementalAppendScan struct {
+	scan           *Scan
+	fromSnapshotID *int64
+	skipNonAppends bool
+}
+
+// IncrementalAppendScan returns a scan of the rows appended to the table.
+// Without calling FromSnapshotExclusive and ToSnapshot, it reads the rows
+// appended by all the ancestors of the current snapshot. The options are
+// applied as for Scan, using WithSnapshotID sets the end snapshot.
+func (t Table) IncrementalAppendScan(opts ...ScanOption) *IncrementalAppendScan {
+	return &IncrementalAppendScan{scan: t.Scan(opts...)}
+}
+
+// FromSnapshotExclusive sets the snapshot after which the appended rows are
+// read, it must be an ancestor of the end snapshot.
+func (s *IncrementalAppendScan) FromSnapshotExclusive(id int64) *IncrementalAppendScan {
+	out := *s
+	out.fromSnapshotID = &id
+
+	return &out
+}
+
+// ToSnapshot sets the last snapshot whose appended rows are read, the
+// current snapshot of the table is used by default.
+func (s *IncrementalAppendScan) ToSnapshot(id int64) *IncrementalAppendScan {
+	out, scan := *s, *s.scan
+	scan.snapshotID = &id
+	out.scan = &scan
+
+	return &out
+}
+
+// SkipNonAppendSnapshots makes the scan ignore the overwrite and delete
+// snapshots in its range rather than failing.
+func (s *IncrementalAppendScan) SkipNonAppendSnapshots() *IncrementalAppendScan {
+	out := *s
+	out.skipNonAppends = true
+
+	return &out
+}
+
+// Projection returns the schema of the rows read, using the schema of the
+// end snapshot.
+func (s *IncrementalAppendScan) Projection() (*iceberg.Schema, error) {
+	return s.scan.Projection()
+}
+
+// snapshotsInRange returns the snapshots that are ancestors of the end
+// snapshot, inclusive, and descendants of the start snapshot, exclusive,
+// newest first.
+func snapshotsInRange(meta Metadata, fromSnapshotID *int64, to *Snapshot) ([]*Snapshot, error) {
+	if fromSnapshotID != nil && meta.SnapshotByID(*fromSnapshotID) == nil {
+		return nil, fmt.Errorf("%w: snapshot not found: %d", ErrInvalidOperation, *fromSnapshotID)
+	}
+
+	var snaps []*Snapshot
+	for snap := range ancestorsOf(meta, to) {
+		if fromSnapshotID != nil && snap.SnapshotID == *fromSnapshotID {
+			return snaps, nil
+		}
+		snaps = append(snaps, snap)
+	}
+
+	if fromSnapshotID != nil {
+		return nil, fmt.Errorf("%w: snapshot %d is not an ancestor of snapshot %d",
+			ErrInvalidOperation, *fromSnapshotID, to.SnapshotID)
+	}
+
+	return snaps, nil
+}
+
+// appendSnapshots returns the append snapshots of the range of the scan.
+func (s *IncrementalAppendScan) appendSnapshots() ([]*Snapshot, error) {
+	to, err := s.scan.Snapshot()
+	if err != nil || to == nil {
+		return nil, err
+	}
+
+	snaps, err := snapshotsInRange(s.scan.metadata, s.fromSnapshotID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	appends := make([]*Snapshot, 0, len(snaps))
+	for _, snap := range snaps {
+		var op Operation
+		if snap.Summary != nil {
+			op = snap.Summary.Operation
+		}
+
+		switch {
+		case op == OpAppend:
+			appends = append(appends, snap)
+		case op == OpReplace || s.skipNonAppends:
+		default:
+			return nil, fmt.Errorf("%w: snapshot %d is not an append but a %q operation",
+				ErrInvalidOperation, snap.SnapshotID, op)
+		}
+	}
+
+	return appends, nil
+}
+
+// PlanFiles returns a task for each data file added by the append snapshots
+// in the range of the scan which might contain rows matching the filter.
+func (s *IncrementalAppendScan) PlanFiles(ctx context.Context) ([]FileScanTask, error) {
+	snaps, err := s.appendSnapshots()
+	if err != nil || len(snaps) == 0 {
+		return nil, err
+	}
+
+	fs, err := s.scan.ioF(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotIDs := make(set[int64], len(snaps))
+	for _, snap := range snaps {
+		snapshotIDs[snap.SnapshotID] = struct{}{}
+	}
+
+	// only the manifests written by the append snapshots can hold the
+	// entries of the files they added.
+	var (
+		manifestList       []iceberg.ManifestFile
+		seen               = make(set[string])
+		manifestEvaluators = newKeyDefaultMapWrapErr(s.scan.buildManifestEvaluator)
+	)
+	for _, snap := range snaps {
+		manifests, err := snap.Manifests(fs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mf := range manifests {
+			if _, ok := snapshotIDs[mf.SnapshotID()]; !ok ||
+				mf.ManifestContent() != iceberg.ManifestContentData {
+				continue
+			}
+
+			if _, ok := seen[mf.FilePath()]; ok {
+				continue
+			}
+			seen[mf.FilePath()] = struct{}{}
+
+			use, err := manifestEvaluators.Get(int(mf.PartitionSpecID()))(mf)
+			if err != nil {
+				return nil, err
+			}
+
+			if use {
+				manifestList = append(manifestList, mf)
+			}
+		}
+	}
+
+	entries, err := s.scan.collectManifestEntries(ctx, manifestList)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]FileScanTask, 0, len(entries.dataEntries))
+	for _, e := range entries.dataEntries {
+		if _, ok := snapshotIDs[e.SnapshotID()]; !ok || e.Status() != iceberg.EntryStatusADDED {
+			continue
+		}
+
+		results = append(results, FileScanTask{
+			File:   e.DataFile(),
+			Start:  0,
+			Length: e.DataFile().FileSizeBytes(),
+		})
+	}
+
+	return results, nil
+}
+
+// PlanTasks plans the files to scan like PlanFiles, then splits and
+// combines them as Scan.PlanTasks does.
+func (s *IncrementalAppendScan) PlanTasks(ctx context.Context) ([]CombinedScanTask, error) {
+	tasks, err := s.PlanFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scan.combineTasks(tasks), nil
+}
+
+// ReadTasks reads the given tasks planned by PlanFiles or PlanTasks, see
+// Scan.ReadTasks.
+func (s *IncrementalAppendScan) ReadTasks(ctx context.Context, tasks []FileScanTask) (*arrow.Schema, iter.Seq2[arrow.Record, error], error) {
+	return s.scan.ReadTasks(ctx, tasks)
+}
+
+// ToArrowRecords returns the schema of the appended rows and an iterator
+// over them, see Scan.ToArrowRecords.
+func (s *IncrementalAppendScan) ToArrowRecords(ctx context.Context) (*arrow.Schema, iter.Seq2[arrow.Record, error], error) {
+	tasks, err := s.PlanFiles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.scan.ReadTasks(ctx, tasks)
+}
+
+// ToArrowTable reads all the appended rows into an arrow table.
+func (s *IncrementalAppendScan) ToArrowTable(ctx context.Context) (arrow.Table, error) {
+	schema, itr, err := s.ToArrowRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectRecords(schema, itr)
+}
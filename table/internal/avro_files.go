@@ -0,0 +1,955 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/extensions"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/google/uuid"
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+)
+
+const avroBatchSize = 1 << 14
+
+type avroFormat struct{}
+
+func (avroFormat) Open(ctx context.Context, fs iceio.IO, path string) (FileReader, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAvroFileReader(compute.GetAllocator(ctx), f)
+}
+
+func (avroFormat) PathToIDMapping(*iceberg.Schema) (map[string]int, error) {
+	return nil, fmt.Errorf("%w: writing avro data files", iceberg.ErrNotImplemented)
+}
+
+func (avroFormat) DataFileStatsFromMeta(Metadata, map[int]StatisticsCollector, map[string]int) *DataFileStatistics {
+	return nil
+}
+
+func (avroFormat) GetWriteProperties(iceberg.Properties) any { return nil }
+
+func (avroFormat) WriteDataFile(context.Context, iceio.WriteFileIO, WriteFileInfo, []arrow.Record) (iceberg.DataFile, error) {
+	return nil, fmt.Errorf("%w: writing avro data files", iceberg.ErrNotImplemented)
+}
+
+type AvroFileSource struct {
+	mem  memory.Allocator
+	fs   iceio.IO
+	file iceberg.DataFile
+}
+
+func (afs *AvroFileSource) GetReader(context.Context) (FileReader, error) {
+	f, err := afs.fs.Open(afs.file.FilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	return newAvroFileReader(afs.mem, f)
+}
+
+// avroFileReader reads Avro object container files as arrow records.
+// Fields are identified by the Iceberg field ids stored in the Avro
+// schema, or by the name mapping for files written without them. The
+// column indices used for projection are the indices of the primitive
+// leaves of the schema in depth first order, like parquet columns.
+type avroFileReader struct {
+	mem    memory.Allocator
+	f      iceio.File
+	meta   map[string][]byte
+	schema *avroField
+}
+
+func newAvroFileReader(mem memory.Allocator, f iceio.File) (*avroFileReader, error) {
+	dec, err := ocf.NewDecoder(f, ocf.WithDecoderSchemaCache(&avro.SchemaCache{}))
+	if err != nil {
+		f.Close()
+
+		return nil, err
+	}
+
+	root, err := newAvroRoot(dec.Schema())
+	if err != nil {
+		f.Close()
+
+		return nil, err
+	}
+
+	return &avroFileReader{mem: mem, f: f, meta: dec.Metadata(), schema: root}, nil
+}
+
+func (r *avroFileReader) Metadata() Metadata { return r.meta }
+
+func (r *avroFileReader) SourceFileSize() int64 {
+	info, err := r.f.Stat()
+	if err != nil {
+		return -1
+	}
+
+	return info.Size()
+}
+
+func (r *avroFileReader) Close() error { return r.f.Close() }
+
+func (r *avroFileReader) Schema() (*arrow.Schema, error) {
+	return r.schema.arrowSchema(nil), nil
+}
+
+func (r *avroFileReader) PrunedSchema(projectedIDs map[int]struct{}, mapping iceberg.NameMapping) (*arrow.Schema, []int, error) {
+	leaves := make(map[int]struct{})
+	if err := r.schema.selectLeaves(projectedIDs, &iceberg.MappedField{Fields: mapping}, leaves); err != nil {
+		return nil, nil, err
+	}
+
+	indices := make([]int, 0, len(leaves))
+	for leaf := range leaves {
+		indices = append(indices, leaf)
+	}
+	slices.Sort(indices)
+
+	return r.schema.arrowSchema(leaves), indices, nil
+}
+
+func (r *avroFileReader) GetRecords(_ context.Context, cols []int, _ any) (array.RecordReader, error) {
+	var leaves map[int]struct{}
+	if cols != nil {
+		leaves = make(map[int]struct{}, len(cols))
+		for _, c := range cols {
+			leaves[c] = struct{}{}
+		}
+	}
+
+	// the file is decoded again from the start as the reader may have
+	// been used before
+	if _, err := r.f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	dec, err := ocf.NewDecoder(r.f, ocf.WithDecoderSchemaCache(&avro.SchemaCache{}))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &avroRecordReader{
+		mem:    r.mem,
+		schema: r.schema.arrowSchema(leaves),
+		append: r.schema.recordAppender(leaves),
+		dec:    dec,
+	}
+	out.refCount.Add(1)
+
+	return out, nil
+}
+
+func (r *avroFileReader) ReadTable(ctx context.Context) (arrow.Table, error) {
+	rdr, err := r.GetRecords(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+
+	recs := make([]arrow.Record, 0)
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+
+	for rdr.Next() {
+		rec := rdr.Record()
+		rec.Retain()
+		recs = append(recs, rec)
+	}
+
+	if err := rdr.Err(); err != nil {
+		return nil, err
+	}
+
+	return array.NewTableFromRecords(rdr.Schema(), recs), nil
+}
+
+// avroRecordReader returns records of up to avroBatchSize rows decoded
+// from the file.
+type avroRecordReader struct {
+	refCount atomic.Int64
+
+	mem    memory.Allocator
+	schema *arrow.Schema
+	append func(map[string]any, *array.RecordBuilder) error
+	dec    *ocf.Decoder
+	cur    arrow.Record
+	err    error
+}
+
+func (r *avroRecordReader) Retain() { r.refCount.Add(1) }
+
+func (r *avroRecordReader) Release() {
+	if r.refCount.Add(-1) == 0 && r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+}
+
+func (r *avroRecordReader) Schema() *arrow.Schema { return r.schema }
+
+func (r *avroRecordReader) Record() arrow.Record { return r.cur }
+
+func (r *avroRecordReader) Err() error { return r.err }
+
+func (r *avroRecordReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+
+	if r.err != nil {
+		return false
+	}
+
+	bldr := array.NewRecordBuilder(r.mem, r.schema)
+	defer bldr.Release()
+
+	var rows int
+	for ; rows < avroBatchSize && r.dec.HasNext(); rows++ {
+		var rec map[string]any
+		if err := r.dec.Decode(&rec); err != nil {
+			r.err = err
+
+			return false
+		}
+
+		if err := r.append(rec, bldr); err != nil {
+			r.err = err
+
+			return false
+		}
+	}
+
+	if err := r.dec.Error(); err != nil {
+		r.err = err
+
+		return false
+	}
+
+	if rows == 0 {
+		return false
+	}
+
+	r.cur = bldr.NewRecord()
+
+	return true
+}
+
+type avroKind int8
+
+const (
+	avroKindPrimitive avroKind = iota
+	avroKindStruct
+	avroKindList
+	avroKindMap
+)
+
+// avroAppender appends a value decoded by hamba/avro to the builder.
+type avroAppender func(any, array.Builder) error
+
+// avroField is a field of the Avro schema of a file, along with how to
+// convert it to arrow.
+type avroField struct {
+	name     string
+	fieldID  *int
+	kind     avroKind
+	nullable bool
+	// branch is the name of the value branch if the field is a union,
+	// which values are wrapped in when the type can't be resolved.
+	branch string
+
+	// typ and append are set for primitives, which are the leaves of the
+	// schema numbered by leaf.
+	typ    arrow.DataType
+	append avroAppender
+	leaf   int
+
+	// children are the fields of a struct, the element of a list, or the
+	// key and value of a map.
+	children []*avroField
+	// entryFields are the names of the key and value fields of maps
+	// stored as an array of records.
+	entryFields []string
+}
+
+func newAvroRoot(sc avro.Schema) (*avroField, error) {
+	if ref, ok := sc.(*avro.RefSchema); ok {
+		sc = ref.Schema()
+	}
+
+	if sc.Type() != avro.Record {
+		return nil, fmt.Errorf("%w: avro data files must contain records, got %s",
+			iceberg.ErrInvalidSchema, sc.Type())
+	}
+
+	var leaf int
+
+	return newAvroField("", sc, nil, &leaf)
+}
+
+func avroPropID(sc avro.PropertySchema, key string) *int {
+	switch v := sc.Prop(key).(type) {
+	case float64:
+		id := int(v)
+
+		return &id
+	case int:
+		return &v
+	case string:
+		if id, err := strconv.Atoi(v); err == nil {
+			return &id
+		}
+	}
+
+	return nil
+}
+
+func newAvroField(name string, sc avro.Schema, fieldID *int, leaf *int) (*avroField, error) {
+	f := &avroField{name: name, fieldID: fieldID}
+
+	if union, ok := sc.(*avro.UnionSchema); ok {
+		for _, t := range union.Types() {
+			switch {
+			case t.Type() == avro.Null && !f.nullable:
+				f.nullable = true
+			case f.branch == "":
+				f.branch, sc = avroTypeName(t), t
+			default:
+				return nil, fmt.Errorf("%w: avro union %s of field %s",
+					iceberg.ErrNotImplemented, union, name)
+			}
+		}
+
+		if f.branch == "" {
+			return nil, fmt.Errorf("%w: avro field %s is always null",
+				iceberg.ErrInvalidSchema, name)
+		}
+	}
+
+	if ref, ok := sc.(*avro.RefSchema); ok {
+		sc = ref.Schema()
+	}
+
+	switch sc := sc.(type) {
+	case *avro.RecordSchema:
+		f.kind = avroKindStruct
+		for _, field := range sc.Fields() {
+			child, err := newAvroField(field.Name(), field.Type(), avroPropID(field, "field-id"), leaf)
+			if err != nil {
+				return nil, err
+			}
+			f.children = append(f.children, child)
+		}
+	case *avro.ArraySchema:
+		if entries, ok := avroMapEntries(sc); ok {
+			return f, f.initArrayMap(entries, leaf)
+		}
+
+		f.kind = avroKindList
+		elem, err := newAvroField("element", sc.Items(), avroPropID(sc, "element-id"), leaf)
+		if err != nil {
+			return nil, err
+		}
+		f.children = []*avroField{elem}
+	case *avro.MapSchema:
+		f.kind = avroKindMap
+		key, err := newAvroField("key", avro.NewPrimitiveSchema(avro.String, nil), avroPropID(sc, "key-id"), leaf)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := newAvroField("value", sc.Values(), avroPropID(sc, "value-id"), leaf)
+		if err != nil {
+			return nil, err
+		}
+		f.children = []*avroField{key, val}
+	default:
+		typ, appendFn, err := avroPrimitive(sc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s", err, name)
+		}
+		f.kind, f.typ, f.append, f.leaf = avroKindPrimitive, typ, appendFn, *leaf
+		*leaf++
+	}
+
+	return f, nil
+}
+
+// avroMapEntries returns the key/value record of the array if it is used
+// to store a map with non-string keys, as Iceberg does.
+func avroMapEntries(sc *avro.ArraySchema) (*avro.RecordSchema, bool) {
+	if avroLogicalType(sc) != "map" {
+		return nil, false
+	}
+
+	items := sc.Items()
+	if ref, ok := items.(*avro.RefSchema); ok {
+		items = ref.Schema()
+	}
+
+	rec, ok := items.(*avro.RecordSchema)
+	if !ok || len(rec.Fields()) != 2 {
+		return nil, false
+	}
+
+	return rec, true
+}
+
+func (f *avroField) initArrayMap(entries *avro.RecordSchema, leaf *int) error {
+	f.kind = avroKindMap
+	fields := entries.Fields()
+	if fields[0].Name() == "value" {
+		fields = []*avro.Field{fields[1], fields[0]}
+	}
+	f.entryFields = []string{fields[0].Name(), fields[1].Name()}
+
+	key, err := newAvroField("key", fields[0].Type(), avroPropID(fields[0], "field-id"), leaf)
+	if err != nil {
+		return err
+	}
+
+	val, err := newAvroField("value", fields[1].Type(), avroPropID(fields[1], "field-id"), leaf)
+	if err != nil {
+		return err
+	}
+	f.children = []*avroField{key, val}
+
+	return nil
+}
+
+func avroLogicalType(sc avro.Schema) string {
+	if lts, ok := sc.(avro.LogicalTypeSchema); ok && lts.Logical() != nil {
+		return string(lts.Logical().Type())
+	}
+
+	// logical types unknown to the avro library are kept as properties
+	if ps, ok := sc.(avro.PropertySchema); ok {
+		if lt, ok := ps.Prop("logicalType").(string); ok {
+			return lt
+		}
+	}
+
+	return ""
+}
+
+func avroTimestampType(sc avro.Schema, unit arrow.TimeUnit, local bool) arrow.DataType {
+	adjustToUTC := !local
+	if ps, ok := sc.(avro.PropertySchema); ok {
+		if v, ok := ps.Prop("adjust-to-utc").(bool); ok {
+			adjustToUTC = v
+		}
+	}
+
+	if adjustToUTC {
+		return &arrow.TimestampType{Unit: unit, TimeZone: "UTC"}
+	}
+
+	return &arrow.TimestampType{Unit: unit}
+}
+
+// avroTypeName returns the name hamba/avro wraps values of the union
+// branch in when it can't resolve their type.
+func avroTypeName(sc avro.Schema) string {
+	if ref, ok := sc.(*avro.RefSchema); ok {
+		sc = ref.Schema()
+	}
+
+	if named, ok := sc.(avro.NamedSchema); ok {
+		return named.FullName()
+	}
+
+	if lts, ok := sc.(avro.LogicalTypeSchema); ok && lts.Logical() != nil {
+		return string(sc.Type()) + "." + string(lts.Logical().Type())
+	}
+
+	return string(sc.Type())
+}
+
+// appendAvro returns an appender for values decoded as T.
+func appendAvro[T any](fn func(T, array.Builder)) avroAppender {
+	return func(v any, b array.Builder) error {
+		val, ok := v.(T)
+		if !ok {
+			return fmt.Errorf("%w: unexpected avro value of type %T, expected %T",
+				iceberg.ErrInvalidSchema, v, val)
+		}
+		fn(val, b)
+
+		return nil
+	}
+}
+
+func avroPrimitive(sc avro.Schema) (arrow.DataType, avroAppender, error) {
+	logical := avroLogicalType(sc)
+
+	switch sc.Type() {
+	case avro.Boolean:
+		return arrow.FixedWidthTypes.Boolean, appendAvro(func(v bool, b array.Builder) {
+			b.(*array.BooleanBuilder).Append(v)
+		}), nil
+	case avro.Int:
+		switch logical {
+		case string(avro.Date):
+			return arrow.FixedWidthTypes.Date32, appendAvro(func(v time.Time, b array.Builder) {
+				b.(*array.Date32Builder).Append(arrow.Date32FromTime(v))
+			}), nil
+		case string(avro.TimeMillis):
+			return arrow.FixedWidthTypes.Time64us, appendAvro(func(v time.Duration, b array.Builder) {
+				b.(*array.Time64Builder).Append(arrow.Time64(v.Microseconds()))
+			}), nil
+		}
+
+		return arrow.PrimitiveTypes.Int32, appendAvro(func(v int, b array.Builder) {
+			b.(*array.Int32Builder).Append(int32(v))
+		}), nil
+	case avro.Long:
+		switch logical {
+		case string(avro.TimeMicros):
+			return arrow.FixedWidthTypes.Time64us, appendAvro(func(v time.Duration, b array.Builder) {
+				b.(*array.Time64Builder).Append(arrow.Time64(v.Microseconds()))
+			}), nil
+		case string(avro.TimestampMillis), string(avro.TimestampMicros):
+			return avroTimestampType(sc, arrow.Microsecond, false), appendAvro(func(v time.Time, b array.Builder) {
+				b.(*array.TimestampBuilder).Append(arrow.Timestamp(v.UnixMicro()))
+			}), nil
+		case string(avro.LocalTimestampMillis), string(avro.LocalTimestampMicros):
+			// local timestamps are decoded with the wall clock of the value
+			// in the local time zone
+			return avroTimestampType(sc, arrow.Microsecond, true), appendAvro(func(v time.Time, b array.Builder) {
+				_, offset := v.Zone()
+				b.(*array.TimestampBuilder).Append(arrow.Timestamp(v.UnixMicro() + int64(offset)*1e6))
+			}), nil
+		case "timestamp-nanos", "local-timestamp-nanos":
+			return avroTimestampType(sc, arrow.Nanosecond, logical == "local-timestamp-nanos"),
+				appendAvro(func(v int64, b array.Builder) {
+					b.(*array.TimestampBuilder).Append(arrow.Timestamp(v))
+				}), nil
+		}
+
+		return arrow.PrimitiveTypes.Int64, appendAvro(func(v int64, b array.Builder) {
+			b.(*array.Int64Builder).Append(v)
+		}), nil
+	case avro.Float:
+		return arrow.PrimitiveTypes.Float32, appendAvro(func(v float32, b array.Builder) {
+			b.(*array.Float32Builder).Append(v)
+		}), nil
+	case avro.Double:
+		return arrow.PrimitiveTypes.Float64, appendAvro(func(v float64, b array.Builder) {
+			b.(*array.Float64Builder).Append(v)
+		}), nil
+	case avro.String:
+		if logical == string(avro.UUID) {
+			return extensions.NewUUIDType(), func(v any, b array.Builder) error {
+				s, ok := v.(string)
+				if !ok {
+					return fmt.Errorf("%w: unexpected avro value of type %T, expected string",
+						iceberg.ErrInvalidSchema, v)
+				}
+
+				u, err := uuid.Parse(s)
+				if err != nil {
+					return err
+				}
+				b.(*extensions.UUIDBuilder).Append(u)
+
+				return nil
+			}, nil
+		}
+
+		fallthrough
+	case avro.Enum:
+		return arrow.BinaryTypes.String, appendAvro(func(v string, b array.Builder) {
+			b.(*array.StringBuilder).Append(v)
+		}), nil
+	case avro.Bytes:
+		if logical == string(avro.Decimal) {
+			return avroDecimal(sc.(avro.LogicalTypeSchema).Logical().(*avro.DecimalLogicalSchema))
+		}
+
+		return arrow.BinaryTypes.Binary, appendAvro(func(v []byte, b array.Builder) {
+			b.(*array.BinaryBuilder).Append(v)
+		}), nil
+	case avro.Fixed:
+		size := sc.(*avro.FixedSchema).Size()
+
+		switch {
+		case logical == string(avro.Decimal):
+			return avroDecimal(sc.(avro.LogicalTypeSchema).Logical().(*avro.DecimalLogicalSchema))
+		case logical == string(avro.UUID) && size == 16:
+			return extensions.NewUUIDType(), appendAvro(func(v [16]byte, b array.Builder) {
+				b.(*extensions.UUIDBuilder).Append(uuid.UUID(v))
+			}), nil
+		case logical == string(avro.Duration):
+			return nil, nil, fmt.Errorf("%w: avro logical type %s", iceberg.ErrNotImplemented, logical)
+		}
+
+		// fixed values are decoded as byte arrays of the size of the type
+		return &arrow.FixedSizeBinaryType{ByteWidth: size}, func(v any, b array.Builder) error {
+			val := reflect.ValueOf(v)
+			if val.Kind() != reflect.Array || val.Type().Elem().Kind() != reflect.Uint8 || val.Len() != size {
+				return fmt.Errorf("%w: unexpected avro value of type %T, expected [%d]byte",
+					iceberg.ErrInvalidSchema, v, size)
+			}
+
+			buf := make([]byte, size)
+			reflect.Copy(reflect.ValueOf(buf), val)
+			b.(*array.FixedSizeBinaryBuilder).Append(buf)
+
+			return nil
+		}, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: avro type %s", iceberg.ErrNotImplemented, sc.Type())
+	}
+}
+
+// avroDecimal converts decimals, which hamba/avro decodes as rationals of
+// their unscaled value over 10^scale.
+func avroDecimal(dec *avro.DecimalLogicalSchema) (arrow.DataType, avroAppender, error) {
+	typ := &arrow.Decimal128Type{Precision: int32(dec.Precision()), Scale: int32(dec.Scale())}
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(dec.Scale())), nil)
+
+	return typ, appendAvro(func(v *big.Rat, b array.Builder) {
+		unscaled := new(big.Int).Mul(v.Num(), pow)
+		b.(*array.Decimal128Builder).Append(decimal128.FromBigInt(unscaled.Quo(unscaled, v.Denom())))
+	}), nil
+}
+
+// id returns the field id from the name mapping, falling back to the one
+// in the file, or -1 if there is neither.
+func (f *avroField) id(mapping *iceberg.MappedField) int {
+	if mapping != nil && mapping.FieldID != nil {
+		return *mapping.FieldID
+	}
+
+	if f.fieldID != nil {
+		return *f.fieldID
+	}
+
+	return -1
+}
+
+func childMapping(mapping *iceberg.MappedField, name string) *iceberg.MappedField {
+	if mapping == nil {
+		return nil
+	}
+
+	return mapping.GetField(name)
+}
+
+// selectLeaves adds the leaves needed to read the projected fields, using
+// the same rules as pruning the columns of parquet files.
+func (f *avroField) selectLeaves(selected map[int]struct{}, mapping *iceberg.MappedField, leaves map[int]struct{}) error {
+	isSelected := func(child *avroField, mapping *iceberg.MappedField) (bool, error) {
+		id := child.id(mapping)
+		if id == -1 {
+			return false, fmt.Errorf("%w: cannot convert %s to Iceberg field, missing field_id",
+				iceberg.ErrInvalidSchema, child.name)
+		}
+		_, ok := selected[id]
+
+		return ok, nil
+	}
+
+	switch f.kind {
+	case avroKindStruct:
+		for _, child := range f.children {
+			m := childMapping(mapping, child.name)
+			if child.kind != avroKindPrimitive {
+				if err := child.selectLeaves(selected, m, leaves); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			ok, err := isSelected(child, m)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				leaves[child.leaf] = struct{}{}
+			}
+		}
+	case avroKindList:
+		elem := &avroField{kind: avroKindStruct, children: f.children}
+
+		return elem.selectLeaves(selected, mapping, leaves)
+	case avroKindMap:
+		key, val := f.children[0], f.children[1]
+		before := len(leaves)
+		value := &avroField{kind: avroKindStruct, children: []*avroField{val}}
+		if err := value.selectLeaves(selected, mapping, leaves); err != nil {
+			return err
+		}
+
+		if len(leaves) > before {
+			// the keys are always needed to read the values
+			key.allLeaves(leaves)
+		}
+	}
+
+	return nil
+}
+
+func (f *avroField) allLeaves(leaves map[int]struct{}) {
+	if f.kind == avroKindPrimitive {
+		leaves[f.leaf] = struct{}{}
+	}
+
+	for _, child := range f.children {
+		child.allLeaves(leaves)
+	}
+}
+
+// projected reports whether any of the leaves of the field are read. A nil
+// set of leaves reads all of them.
+func (f *avroField) projected(leaves map[int]struct{}) bool {
+	if leaves == nil {
+		return true
+	}
+
+	if f.kind == avroKindPrimitive {
+		_, ok := leaves[f.leaf]
+
+		return ok
+	}
+
+	return slices.ContainsFunc(f.children, func(c *avroField) bool { return c.projected(leaves) })
+}
+
+func (f *avroField) arrowSchema(leaves map[int]struct{}) *arrow.Schema {
+	return arrow.NewSchema(f.arrowType(leaves).(*arrow.StructType).Fields(), nil)
+}
+
+func (f *avroField) arrowField(leaves map[int]struct{}) arrow.Field {
+	result := arrow.Field{Name: f.name, Type: f.arrowType(leaves), Nullable: f.nullable}
+	if f.fieldID != nil {
+		result.Metadata = arrow.NewMetadata([]string{"PARQUET:field_id"},
+			[]string{strconv.Itoa(*f.fieldID)})
+	}
+
+	return result
+}
+
+func (f *avroField) arrowType(leaves map[int]struct{}) arrow.DataType {
+	switch f.kind {
+	case avroKindStruct:
+		fields := make([]arrow.Field, 0, len(f.children))
+		for _, child := range f.children {
+			if child.projected(leaves) {
+				fields = append(fields, child.arrowField(leaves))
+			}
+		}
+
+		return arrow.StructOf(fields...)
+	case avroKindList:
+		return arrow.ListOfField(f.children[0].arrowField(leaves))
+	case avroKindMap:
+		return arrow.MapOfFields(f.children[0].arrowField(nil), f.children[1].arrowField(leaves))
+	default:
+		return f.typ
+	}
+}
+
+// recordAppender returns a function appending a decoded record of the
+// file to the builder for the projected schema.
+func (f *avroField) recordAppender(leaves map[int]struct{}) func(map[string]any, *array.RecordBuilder) error {
+	appendFields := f.structAppender(leaves)
+
+	return func(rec map[string]any, b *array.RecordBuilder) error { return appendFields(rec, b.Field) }
+}
+
+func (f *avroField) structAppender(leaves map[int]struct{}) func(map[string]any, func(int) array.Builder) error {
+	type childAppender struct {
+		name   string
+		append avroAppender
+	}
+
+	children := make([]childAppender, 0, len(f.children))
+	for _, child := range f.children {
+		if child.projected(leaves) {
+			children = append(children, childAppender{name: child.name, append: child.appender(leaves)})
+		}
+	}
+
+	return func(rec map[string]any, field func(int) array.Builder) error {
+		for i, child := range children {
+			if err := child.append(rec[child.name], field(i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// appender returns a function appending a decoded value of the field to
+// the builder for its projected type. The field must be projected.
+func (f *avroField) appender(leaves map[int]struct{}) avroAppender {
+	var appendFn avroAppender
+
+	switch f.kind {
+	case avroKindStruct:
+		appendFields := f.structAppender(leaves)
+		appendFn = func(v any, b array.Builder) error {
+			rec, ok := v.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%w: unexpected avro value of type %T for record %s",
+					iceberg.ErrInvalidSchema, v, f.name)
+			}
+
+			sb := b.(*array.StructBuilder)
+			sb.Append(true)
+
+			return appendFields(rec, sb.FieldBuilder)
+		}
+	case avroKindList:
+		appendElem := f.children[0].appender(leaves)
+		appendFn = func(v any, b array.Builder) error {
+			items, ok := v.([]any)
+			if !ok {
+				return fmt.Errorf("%w: unexpected avro value of type %T for array %s",
+					iceberg.ErrInvalidSchema, v, f.name)
+			}
+
+			lb := b.(*array.ListBuilder)
+			lb.Append(true)
+			for _, item := range items {
+				if err := appendElem(item, lb.ValueBuilder()); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	case avroKindMap:
+		appendFn = f.mapAppender(leaves)
+	default:
+		appendFn = f.append
+	}
+
+	if f.branch == "" {
+		return appendFn
+	}
+
+	return func(v any, b array.Builder) error {
+		if v == nil {
+			if !f.nullable {
+				return fmt.Errorf("%w: null value for required avro field %s",
+					iceberg.ErrInvalidSchema, f.name)
+			}
+			b.AppendNull()
+
+			return nil
+		}
+
+		if wrapped, ok := v.(map[string]any); ok && len(wrapped) == 1 {
+			if val, ok := wrapped[f.branch]; ok {
+				v = val
+			}
+		}
+
+		return appendFn(v, b)
+	}
+}
+
+func (f *avroField) mapAppender(leaves map[int]struct{}) avroAppender {
+	appendKey := f.children[0].appender(nil)
+	appendVal := f.children[1].appender(leaves)
+
+	appendEntry := func(mb *array.MapBuilder, key, val any) error {
+		if err := appendKey(key, mb.KeyBuilder()); err != nil {
+			return err
+		}
+
+		return appendVal(val, mb.ItemBuilder())
+	}
+
+	if f.entryFields == nil {
+		return func(v any, b array.Builder) error {
+			entries, ok := v.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%w: unexpected avro value of type %T for map %s",
+					iceberg.ErrInvalidSchema, v, f.name)
+			}
+
+			mb := b.(*array.MapBuilder)
+			mb.Append(true)
+			for _, key := range slices.Sorted(maps.Keys(entries)) {
+				if err := appendEntry(mb, key, entries[key]); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	}
+
+	keyName, valName := f.entryFields[0], f.entryFields[1]
+
+	return func(v any, b array.Builder) error {
+		entries, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%w: unexpected avro value of type %T for map %s",
+				iceberg.ErrInvalidSchema, v, f.name)
+		}
+
+		mb := b.(*array.MapBuilder)
+		mb.Append(true)
+		for _, entry := range entries {
+			kv, ok := entry.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%w: unexpected avro value of type %T for map entry of %s",
+					iceberg.ErrInvalidSchema, entry, f.name)
+			}
+
+			if err := appendEntry(mb, kv[keyName], kv[valName]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,288 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal_test
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/apache/iceberg-go/table/internal"
+	"github.com/google/uuid"
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const avroTestSchema = `{"type": "record", "name": "r", "fields": [
+	{"name": "id", "type": "long", "field-id": 1},
+	{"name": "name", "type": ["null", "string"], "field-id": 2},
+	{"name": "ts", "type": {"type": "long", "logicalType": "timestamp-micros", "adjust-to-utc": true}, "field-id": 3},
+	{"name": "price", "type": {"type": "fixed", "name": "dec", "size": 4, "logicalType": "decimal", "precision": 9, "scale": 2}, "field-id": 4},
+	{"name": "uid", "type": {"type": "fixed", "name": "uuid_fixed", "size": 16, "logicalType": "uuid"}, "field-id": 5},
+	{"name": "tags", "type": ["null", {"type": "array", "items": "string", "element-id": 7}], "field-id": 6},
+	{"name": "props", "type": {"type": "map", "values": "int", "key-id": 9, "value-id": 10}, "field-id": 8},
+	{"name": "location", "type": {"type": "record", "name": "loc", "fields": [
+		{"name": "lat", "type": "double", "field-id": 12},
+		{"name": "lon", "type": "double", "field-id": 13}
+	]}, "field-id": 11},
+	{"name": "day", "type": {"type": "int", "logicalType": "date"}, "field-id": 14}
+]}`
+
+var avroTestUUID = uuid.MustParse("f79c3e09-677c-4d1b-ab70-58ac1c2d0c2a")
+
+func writeAvroTestFile(t *testing.T, schema string, codec ocf.CodecName) string {
+	fname := filepath.Join(t.TempDir(), "data.avro")
+	f, err := os.Create(fname)
+	require.NoError(t, err)
+	defer f.Close()
+
+	enc, err := ocf.NewEncoderWithSchema(avro.MustParse(schema), f,
+		ocf.WithSchemaMarshaler(ocf.FullSchemaMarshaler), ocf.WithCodec(codec),
+		ocf.WithBlockLength(2))
+	require.NoError(t, err)
+
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		rec := map[string]any{
+			"id":       int64(i),
+			"name":     map[string]any{"string": "row" + string(rune('a'+i))},
+			"ts":       ts.Add(time.Duration(i) * time.Hour),
+			"price":    big.NewRat(int64(1050+i), 100),
+			"uid":      [16]byte(avroTestUUID),
+			"tags":     map[string]any{"array": []any{"x", "y"}},
+			"props":    map[string]any{"k": i},
+			"location": map[string]any{"lat": 1.5, "lon": -2.5},
+			"day":      ts,
+		}
+
+		if i == 1 {
+			rec["name"], rec["tags"] = nil, nil
+		}
+		require.NoError(t, enc.Encode(rec))
+	}
+	require.NoError(t, enc.Close())
+
+	return fname
+}
+
+func openTestFile(t *testing.T, fname string, format iceberg.FileFormat) internal.FileReader {
+	bldr, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentData,
+		fname, format, nil, 3, 100)
+	require.NoError(t, err)
+
+	ctx := compute.WithAllocator(context.Background(), memory.DefaultAllocator)
+	src, err := internal.GetFile(ctx, iceio.LocalFS{}, bldr.Build(), false)
+	require.NoError(t, err)
+
+	rdr, err := src.GetReader(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { rdr.Close() })
+
+	return rdr
+}
+
+func columnStrings(tbl arrow.Table, col int) []string {
+	out := make([]string, 0, tbl.NumRows())
+	for _, chunk := range tbl.Column(col).Data().Chunks() {
+		for i := range chunk.Len() {
+			out = append(out, chunk.ValueStr(i))
+		}
+	}
+
+	return out
+}
+
+func TestAvroReadTable(t *testing.T) {
+	for _, codec := range []ocf.CodecName{ocf.Null, ocf.Deflate, ocf.Snappy, ocf.ZStandard} {
+		t.Run(string(codec), func(t *testing.T) {
+			rdr := openTestFile(t, writeAvroTestFile(t, avroTestSchema, codec), iceberg.AvroFile)
+
+			tbl, err := rdr.ReadTable(context.Background())
+			require.NoError(t, err)
+			defer tbl.Release()
+
+			sc, err := table.ArrowSchemaToIceberg(tbl.Schema(), false, nil)
+			require.NoError(t, err)
+			assert.Equal(t, `table {
+	1: id: required long
+	2: name: optional string
+	3: ts: required timestamptz
+	4: price: required decimal(9, 2)
+	5: uid: required uuid
+	6: tags: optional list<string>
+	8: props: required map<string, int>
+	11: location: required struct<12: lat: required double, 13: lon: required double>
+	14: day: required date
+}`, sc.String())
+
+			assert.EqualValues(t, 3, tbl.NumRows())
+			assert.Equal(t, []string{"0", "1", "2"}, columnStrings(tbl, 0))
+			assert.Equal(t, []string{"rowa", array.NullValueStr, "rowc"}, columnStrings(tbl, 1))
+			assert.Equal(t, []string{"10.5", "10.51", "10.52"}, columnStrings(tbl, 3))
+			assert.Equal(t, slices.Repeat([]string{avroTestUUID.String()}, 3), columnStrings(tbl, 4))
+			assert.Equal(t, []string{`["x","y"]`, array.NullValueStr, `["x","y"]`}, columnStrings(tbl, 5))
+			assert.Equal(t, slices.Repeat([]string{"2024-05-01"}, 3), columnStrings(tbl, 8))
+		})
+	}
+}
+
+func TestAvroProjection(t *testing.T) {
+	rdr := openTestFile(t, writeAvroTestFile(t, avroTestSchema, ocf.Deflate), iceberg.AvroFile)
+
+	projected, cols, err := rdr.PrunedSchema(map[int]struct{}{2: {}, 10: {}, 13: {}}, nil)
+	require.NoError(t, err)
+
+	sc, err := table.ArrowSchemaToIceberg(projected, false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `table {
+	2: name: optional string
+	8: props: required map<string, int>
+	11: location: required struct<13: lon: required double>
+}`, sc.String())
+
+	recs, err := rdr.GetRecords(context.Background(), cols, nil)
+	require.NoError(t, err)
+	defer recs.Release()
+
+	assert.True(t, recs.Schema().Equal(projected))
+
+	var props, locations []string
+	for recs.Next() {
+		rec := recs.Record()
+		assert.True(t, rec.Schema().Equal(projected))
+		for i := range int(rec.NumRows()) {
+			props = append(props, rec.Column(1).ValueStr(i))
+			locations = append(locations, rec.Column(2).ValueStr(i))
+		}
+	}
+	require.NoError(t, recs.Err())
+	assert.Equal(t, []string{`[{"key":"k","value":0}]`, `[{"key":"k","value":1}]`,
+		`[{"key":"k","value":2}]`}, props)
+	assert.Equal(t, slices.Repeat([]string{`{"lon":-2.5}`}, 3), locations)
+}
+
+func TestAvroNameMapping(t *testing.T) {
+	fname := writeAvroTestFile(t, `{"type": "record", "name": "r", "fields": [
+		{"name": "id", "type": "long"},
+		{"name": "name", "type": ["null", "string"]},
+		{"name": "location", "type": {"type": "record", "name": "loc", "fields": [
+			{"name": "lat", "type": "double"},
+			{"name": "lon", "type": "double"}
+		]}}
+	]}`, ocf.Null)
+	rdr := openTestFile(t, fname, iceberg.AvroFile)
+
+	_, _, err := rdr.PrunedSchema(map[int]struct{}{1: {}}, nil)
+	assert.ErrorIs(t, err, iceberg.ErrInvalidSchema)
+
+	id := func(i int) *int { return &i }
+	mapping := iceberg.NameMapping{
+		{FieldID: id(1), Names: []string{"id"}},
+		{FieldID: id(2), Names: []string{"name"}},
+		{FieldID: id(3), Names: []string{"location"}, Fields: []iceberg.MappedField{
+			{FieldID: id(4), Names: []string{"lat"}},
+			{FieldID: id(5), Names: []string{"lon"}},
+		}},
+	}
+
+	projected, cols, err := rdr.PrunedSchema(map[int]struct{}{1: {}, 4: {}}, mapping)
+	require.NoError(t, err)
+
+	sc, err := table.ArrowSchemaToIceberg(projected, false, mapping)
+	require.NoError(t, err)
+	assert.Equal(t, `table {
+	1: id: required long
+	3: location: required struct<4: lat: required double>
+}`, sc.String())
+
+	recs, err := rdr.GetRecords(context.Background(), cols, nil)
+	require.NoError(t, err)
+	defer recs.Release()
+
+	var ids []string
+	for recs.Next() {
+		for i := range recs.Record().NumRows() {
+			ids = append(ids, recs.Record().Column(0).ValueStr(int(i)))
+		}
+	}
+	require.NoError(t, recs.Err())
+	assert.Equal(t, []string{"0", "1", "2"}, ids)
+}
+
+func TestAvroUnionsAndArrayMaps(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "data.avro")
+	f, err := os.Create(fname)
+	require.NoError(t, err)
+
+	enc, err := ocf.NewEncoder(`{"type": "record", "name": "r", "fields": [
+		{"name": "point", "type": ["null", {"type": "record", "name": "pt", "fields": [
+			{"name": "x", "type": "int", "field-id": 3}
+		]}], "field-id": 1},
+		{"name": "counts", "type": {"type": "array", "logicalType": "map", "items": {
+			"type": "record", "name": "kv", "fields": [
+				{"name": "key", "type": "int", "field-id": 5},
+				{"name": "value", "type": ["null", "string"], "field-id": 6}
+			]}}, "field-id": 4},
+		{"name": "local_ts", "type": {"type": "long", "logicalType": "local-timestamp-micros"}, "field-id": 7}
+	]}`, f, ocf.WithSchemaMarshaler(ocf.FullSchemaMarshaler))
+	require.NoError(t, err)
+
+	// local timestamps are written with the wall clock in the local zone
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	require.NoError(t, enc.Encode(map[string]any{
+		"point":    map[string]any{"pt": map[string]any{"x": 7}},
+		"counts":   []any{map[string]any{"key": 1, "value": map[string]any{"string": "a"}}},
+		"local_ts": ts,
+	}))
+	require.NoError(t, enc.Encode(map[string]any{
+		"point":    nil,
+		"counts":   []any{map[string]any{"key": 2, "value": nil}},
+		"local_ts": ts,
+	}))
+	require.NoError(t, enc.Close())
+	require.NoError(t, f.Close())
+
+	tbl, err := openTestFile(t, fname, iceberg.AvroFile).ReadTable(context.Background())
+	require.NoError(t, err)
+	defer tbl.Release()
+
+	sc, err := table.ArrowSchemaToIceberg(tbl.Schema(), false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `table {
+	1: point: optional struct<3: x: required int>
+	4: counts: required map<int, string>
+	7: local_ts: required timestamp
+}`, sc.String())
+
+	assert.Equal(t, []string{`{"x":7}`, array.NullValueStr}, columnStrings(tbl, 0))
+	assert.Equal(t, []string{`[{"key":1,"value":"a"}]`, `[{"key":2,"value":null}]`}, columnStrings(tbl, 1))
+	assert.Equal(t, slices.Repeat([]string{"2024-05-01 12:00:00Z"}, 2), columnStrings(tbl, 2))
+}
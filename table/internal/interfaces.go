@@ -42,8 +42,20 @@ func GetFile(ctx context.Context, fs iceio.IO, dataFile iceberg.DataFile, isPosD
 			fs:   fs,
 			file: dataFile,
 		}, nil
+	case iceberg.AvroFile:
+		return &AvroFileSource{
+			mem:  compute.GetAllocator(ctx),
+			fs:   fs,
+			file: dataFile,
+		}, nil
+	case iceberg.OrcFile:
+		return &OrcFileSource{
+			mem:  compute.GetAllocator(ctx),
+			fs:   fs,
+			file: dataFile,
+		}, nil
 	default:
-		return nil, fmt.Errorf("%w: only parquet format is implemented, got %s",
+		return nil, fmt.Errorf("%w: unsupported file format %s",
 			iceberg.ErrNotImplemented, dataFile.FileFormat())
 	}
 }
@@ -85,6 +97,10 @@ func GetFileFormat(format iceberg.FileFormat) FileFormat {
 	switch format {
 	case iceberg.ParquetFile:
 		return parquetFormat{}
+	case iceberg.AvroFile:
+		return avroFormat{}
+	case iceberg.OrcFile:
+		return orcFormat{}
 	default:
 		return nil
 	}
@@ -105,4 +121,14 @@ type WriteFileInfo struct {
 	FileName   string
 	StatsCols  map[int]StatisticsCollector
 	WriteProps any
+	// PartitionValues are the partition values of the records being written
+	// keyed by partition field id. If nil, the partition values are inferred
+	// from the column statistics of the written file.
+	PartitionValues map[int]any
+	// Content is the type of file being written, the zero value writes
+	// a data file.
+	Content iceberg.ManifestEntryContent
+	// SortOrderID is the id of the sort order the records being written
+	// are sorted by, if nil no sort order id is recorded for the file.
+	SortOrderID *int
 }
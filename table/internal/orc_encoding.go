@@ -0,0 +1,937 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/iceberg-go"
+)
+
+// This file decodes the parts of the ORC format needed to read data
+// files: the protobuf messages of the file tail and stripe footers, the
+// compression framing of streams and the run length encodings of values.
+// Streams compressed with zlib, snappy, lz4 and zstd are supported, but not
+// those compressed with lzo.
+// See https://orc.apache.org/specification/ORCv1/
+
+type orcCompression int
+
+const (
+	orcCompressionNone orcCompression = iota
+	orcCompressionZlib
+	orcCompressionSnappy
+	orcCompressionLzo
+	orcCompressionLz4
+	orcCompressionZstd
+)
+
+type orcKind int
+
+const (
+	orcBoolean orcKind = iota
+	orcByte
+	orcShort
+	orcInt
+	orcLong
+	orcFloat
+	orcDouble
+	orcString
+	orcBinary
+	orcTimestamp
+	orcList
+	orcMap
+	orcStruct
+	orcUnion
+	orcDecimal
+	orcDate
+	orcVarchar
+	orcChar
+	orcTimestampInstant
+)
+
+type orcStreamKind int
+
+const (
+	orcStreamPresent orcStreamKind = iota
+	orcStreamData
+	orcStreamLength
+	orcStreamDictionaryData
+	orcStreamDictionaryCount
+	orcStreamSecondary
+)
+
+type orcEncodingKind int
+
+const (
+	orcEncodingDirect orcEncodingKind = iota
+	orcEncodingDictionary
+	orcEncodingDirectV2
+	orcEncodingDictionaryV2
+)
+
+type orcPostScript struct {
+	footerLength         uint64
+	compression          orcCompression
+	compressionBlockSize uint64
+	metadataLength       uint64
+}
+
+type orcStripeInfo struct {
+	offset       uint64
+	indexLength  uint64
+	dataLength   uint64
+	footerLength uint64
+	numRows      uint64
+}
+
+type orcType struct {
+	kind       orcKind
+	subtypes   []uint32
+	fieldNames []string
+	precision  uint32
+	scale      uint32
+	attributes map[string]string
+}
+
+type orcFooter struct {
+	stripes  []orcStripeInfo
+	types    []orcType
+	metadata map[string][]byte
+	numRows  uint64
+}
+
+type orcStream struct {
+	kind   orcStreamKind
+	column uint32
+	length uint64
+}
+
+type orcColumnEncoding struct {
+	kind           orcEncodingKind
+	dictionarySize uint32
+}
+
+type orcStripeFooter struct {
+	streams        []orcStream
+	columns        []orcColumnEncoding
+	writerTimezone string
+}
+
+var errInvalidProto = errors.New("invalid orc protobuf message")
+
+// protoReader decodes the protobuf wire format.
+type protoReader struct {
+	buf []byte
+	err error
+}
+
+func (p *protoReader) more() bool { return p.err == nil && len(p.buf) > 0 }
+
+func (p *protoReader) varint() uint64 {
+	v, n := binary.Uvarint(p.buf)
+	if n <= 0 {
+		p.err, p.buf = errInvalidProto, nil
+
+		return 0
+	}
+	p.buf = p.buf[n:]
+
+	return v
+}
+
+func (p *protoReader) tag() (int, int) {
+	t := p.varint()
+
+	return int(t >> 3), int(t & 7)
+}
+
+func (p *protoReader) advance(n uint64) {
+	if n > uint64(len(p.buf)) {
+		p.err, p.buf = errInvalidProto, nil
+
+		return
+	}
+	p.buf = p.buf[n:]
+}
+
+func (p *protoReader) bytes() []byte {
+	n := p.varint()
+	if n > uint64(len(p.buf)) {
+		p.err, p.buf = errInvalidProto, nil
+
+		return nil
+	}
+	out := p.buf[:n]
+	p.buf = p.buf[n:]
+
+	return out
+}
+
+func (p *protoReader) skip(wireType int) {
+	switch wireType {
+	case 0:
+		p.varint()
+	case 1:
+		p.advance(8)
+	case 2:
+		p.bytes()
+	case 5:
+		p.advance(4)
+	default:
+		p.err, p.buf = errInvalidProto, nil
+	}
+}
+
+// uint32s appends the values of a repeated integer field, which may or
+// may not be packed.
+func (p *protoReader) uint32s(wireType int, out []uint32) []uint32 {
+	if wireType != 2 {
+		return append(out, uint32(p.varint()))
+	}
+
+	packed := protoReader{buf: p.bytes()}
+	for packed.more() {
+		out = append(out, uint32(packed.varint()))
+	}
+	if packed.err != nil {
+		p.err = packed.err
+	}
+
+	return out
+}
+
+func parseOrcPostScript(buf []byte) (orcPostScript, error) {
+	ps := orcPostScript{compressionBlockSize: 256 * 1024}
+
+	var magic string
+	p := protoReader{buf: buf}
+	for p.more() {
+		switch field, wireType := p.tag(); field {
+		case 1:
+			ps.footerLength = p.varint()
+		case 2:
+			ps.compression = orcCompression(p.varint())
+		case 3:
+			ps.compressionBlockSize = p.varint()
+		case 5:
+			ps.metadataLength = p.varint()
+		case 8000:
+			magic = string(p.bytes())
+		default:
+			p.skip(wireType)
+		}
+	}
+
+	if p.err != nil {
+		return ps, p.err
+	}
+
+	if magic != "ORC" {
+		return ps, errors.New("invalid orc file: bad magic in postscript")
+	}
+
+	// there is no maintained Go decoder of the LZO streams of the Hadoop
+	// codec written by ORC, so those files can't be read
+	switch ps.compression {
+	case orcCompressionNone, orcCompressionZlib, orcCompressionSnappy, orcCompressionLz4, orcCompressionZstd:
+	case orcCompressionLzo:
+		return ps, fmt.Errorf("%w: reading orc files compressed with lzo", iceberg.ErrNotImplemented)
+	default:
+		return ps, fmt.Errorf("%w: orc compression %d", iceberg.ErrNotImplemented, ps.compression)
+	}
+
+	return ps, nil
+}
+
+func parseOrcFooter(buf []byte) (orcFooter, error) {
+	footer := orcFooter{metadata: make(map[string][]byte)}
+
+	p := protoReader{buf: buf}
+	for p.more() {
+		switch field, wireType := p.tag(); field {
+		case 3:
+			footer.stripes = append(footer.stripes, parseOrcStripeInfo(&p))
+		case 4:
+			footer.types = append(footer.types, parseOrcType(&p))
+		case 5:
+			item := protoReader{buf: p.bytes()}
+			var key string
+			var value []byte
+			for item.more() {
+				switch field, wireType := item.tag(); field {
+				case 1:
+					key = string(item.bytes())
+				case 2:
+					value = item.bytes()
+				default:
+					item.skip(wireType)
+				}
+			}
+			if item.err != nil {
+				return footer, item.err
+			}
+			footer.metadata[key] = value
+		case 6:
+			footer.numRows = p.varint()
+		default:
+			p.skip(wireType)
+		}
+	}
+
+	return footer, p.err
+}
+
+func parseOrcStripeInfo(p *protoReader) orcStripeInfo {
+	var info orcStripeInfo
+
+	msg := protoReader{buf: p.bytes()}
+	for msg.more() {
+		switch field, wireType := msg.tag(); field {
+		case 1:
+			info.offset = msg.varint()
+		case 2:
+			info.indexLength = msg.varint()
+		case 3:
+			info.dataLength = msg.varint()
+		case 4:
+			info.footerLength = msg.varint()
+		case 5:
+			info.numRows = msg.varint()
+		default:
+			msg.skip(wireType)
+		}
+	}
+	if msg.err != nil {
+		p.err = msg.err
+	}
+
+	return info
+}
+
+func parseOrcType(p *protoReader) orcType {
+	typ := orcType{attributes: make(map[string]string)}
+
+	msg := protoReader{buf: p.bytes()}
+	for msg.more() {
+		switch field, wireType := msg.tag(); field {
+		case 1:
+			typ.kind = orcKind(msg.varint())
+		case 2:
+			typ.subtypes = msg.uint32s(wireType, typ.subtypes)
+		case 3:
+			typ.fieldNames = append(typ.fieldNames, string(msg.bytes()))
+		case 5:
+			typ.precision = uint32(msg.varint())
+		case 6:
+			typ.scale = uint32(msg.varint())
+		case 7:
+			attr := protoReader{buf: msg.bytes()}
+			var key, value string
+			for attr.more() {
+				switch field, wireType := attr.tag(); field {
+				case 1:
+					key = string(attr.bytes())
+				case 2:
+					value = string(attr.bytes())
+				default:
+					attr.skip(wireType)
+				}
+			}
+			if attr.err != nil {
+				msg.err = attr.err
+			}
+			typ.attributes[key] = value
+		default:
+			msg.skip(wireType)
+		}
+	}
+	if msg.err != nil {
+		p.err = msg.err
+	}
+
+	return typ
+}
+
+func parseOrcStripeFooter(buf []byte) (orcStripeFooter, error) {
+	var footer orcStripeFooter
+
+	p := protoReader{buf: buf}
+	for p.more() {
+		switch field, wireType := p.tag(); field {
+		case 1:
+			var stream orcStream
+			msg := protoReader{buf: p.bytes()}
+			for msg.more() {
+				switch field, wireType := msg.tag(); field {
+				case 1:
+					stream.kind = orcStreamKind(msg.varint())
+				case 2:
+					stream.column = uint32(msg.varint())
+				case 3:
+					stream.length = msg.varint()
+				default:
+					msg.skip(wireType)
+				}
+			}
+			if msg.err != nil {
+				return footer, msg.err
+			}
+			footer.streams = append(footer.streams, stream)
+		case 2:
+			var enc orcColumnEncoding
+			msg := protoReader{buf: p.bytes()}
+			for msg.more() {
+				switch field, wireType := msg.tag(); field {
+				case 1:
+					enc.kind = orcEncodingKind(msg.varint())
+				case 2:
+					enc.dictionarySize = uint32(msg.varint())
+				default:
+					msg.skip(wireType)
+				}
+			}
+			if msg.err != nil {
+				return footer, msg.err
+			}
+			footer.columns = append(footer.columns, enc)
+		case 3:
+			footer.writerTimezone = string(p.bytes())
+		default:
+			p.skip(wireType)
+		}
+	}
+
+	return footer, p.err
+}
+
+// orcDecompress returns the content of a stream, which is split in chunks
+// that are each compressed unless they are stored as is.
+func orcDecompress(codec orcCompression, blockSize uint64, data []byte) ([]byte, error) {
+	if codec == orcCompressionNone {
+		return data, nil
+	}
+
+	out := make([]byte, 0, len(data))
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		header := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+		size, original := header>>1, header&1 == 1
+		if size > len(data)-3 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		chunk := data[3 : 3+size]
+		data = data[3+size:]
+
+		if original {
+			out = append(out, chunk...)
+
+			continue
+		}
+
+		decoded, err := orcDecompressChunk(codec, blockSize, chunk)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, decoded...)
+	}
+
+	return out, nil
+}
+
+func orcDecompressChunk(codec orcCompression, blockSize uint64, chunk []byte) (out []byte, err error) {
+	var (
+		dst  []byte
+		kind compress.Compression
+	)
+
+	switch codec {
+	case orcCompressionZlib:
+		// ORC uses raw deflate without the zlib header
+		r := flate.NewReader(bytes.NewReader(chunk))
+		defer r.Close()
+
+		return io.ReadAll(r)
+	case orcCompressionSnappy:
+		kind = compress.Codecs.Snappy
+	case orcCompressionZstd:
+		kind = compress.Codecs.Zstd
+	case orcCompressionLz4:
+		kind, dst = compress.Codecs.Lz4Raw, make([]byte, blockSize)
+	default:
+		return nil, fmt.Errorf("%w: orc compression %d", iceberg.ErrNotImplemented, codec)
+	}
+
+	c, err := compress.GetCodec(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	// the parquet codecs panic on corrupted input
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid orc compression chunk: %v", r)
+		}
+	}()
+
+	return c.Decode(dst, chunk), nil
+}
+
+// orcByteReader decodes byte run length encoded streams.
+type orcByteReader struct {
+	buf     []byte
+	n       int
+	literal bool
+	val     byte
+}
+
+func (r *orcByteReader) next() (byte, error) {
+	if r.n == 0 {
+		if len(r.buf) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+
+		ctrl := int8(r.buf[0])
+		r.buf = r.buf[1:]
+		if ctrl < 0 {
+			r.n, r.literal = -int(ctrl), true
+		} else {
+			if len(r.buf) == 0 {
+				return 0, io.ErrUnexpectedEOF
+			}
+			r.n, r.literal, r.val = int(ctrl)+3, false, r.buf[0]
+			r.buf = r.buf[1:]
+		}
+	}
+
+	r.n--
+	if !r.literal {
+		return r.val, nil
+	}
+
+	if len(r.buf) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	v := r.buf[0]
+	r.buf = r.buf[1:]
+
+	return v, nil
+}
+
+// orcBoolReader decodes boolean streams, which are byte run length
+// encoded with the most significant bit first.
+type orcBoolReader struct {
+	bytes orcByteReader
+	cur   byte
+	bit   int
+}
+
+func newOrcBoolReader(buf []byte) *orcBoolReader {
+	return &orcBoolReader{bytes: orcByteReader{buf: buf}, bit: 8}
+}
+
+func (r *orcBoolReader) next() (bool, error) {
+	if r.bit == 8 {
+		b, err := r.bytes.next()
+		if err != nil {
+			return false, err
+		}
+		r.cur, r.bit = b, 0
+	}
+
+	v := r.cur&(0x80>>r.bit) != 0
+	r.bit++
+
+	return v, nil
+}
+
+// orcIntReader decodes integer streams.
+type orcIntReader interface {
+	next() (int64, error)
+}
+
+func newOrcIntReader(buf []byte, signed bool, enc orcEncodingKind) orcIntReader {
+	if enc == orcEncodingDirectV2 || enc == orcEncodingDictionaryV2 {
+		return &orcRLEv2{buf: buf, signed: signed}
+	}
+
+	return &orcRLEv1{buf: buf, signed: signed}
+}
+
+func zigzag(v uint64) int64 { return int64(v>>1) ^ -int64(v&1) }
+
+func orcVarint(buf []byte, signed bool) (int64, []byte, error) {
+	v, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+
+	if signed {
+		return zigzag(v), buf[n:], nil
+	}
+
+	return int64(v), buf[n:], nil
+}
+
+// orcBigVarint decodes a zigzag encoded varint of any size, as used for
+// decimals.
+func orcBigVarint(buf []byte) (*big.Int, []byte, error) {
+	if v, n := binary.Uvarint(buf); n > 0 {
+		return big.NewInt(zigzag(v)), buf[n:], nil
+	}
+
+	end := -1
+	for i, b := range buf {
+		if b&0x80 == 0 {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	v := new(big.Int)
+	for i := end; i >= 0; i-- {
+		v.Lsh(v, 7).Or(v, big.NewInt(int64(buf[i]&0x7f)))
+	}
+
+	negative := v.Bit(0) == 1
+	v.Rsh(v, 1)
+	if negative {
+		v.Neg(v).Sub(v, big.NewInt(1))
+	}
+
+	return v, buf[end+1:], nil
+}
+
+// orcRLEv1 decodes the first version of integer run length encoding.
+type orcRLEv1 struct {
+	buf     []byte
+	signed  bool
+	n       int
+	literal bool
+	val     int64
+	delta   int64
+}
+
+func (r *orcRLEv1) next() (int64, error) {
+	if r.n == 0 {
+		if len(r.buf) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+
+		ctrl := int8(r.buf[0])
+		r.buf = r.buf[1:]
+		if ctrl < 0 {
+			r.n, r.literal = -int(ctrl), true
+		} else {
+			if len(r.buf) == 0 {
+				return 0, io.ErrUnexpectedEOF
+			}
+			r.n, r.literal, r.delta = int(ctrl)+3, false, int64(int8(r.buf[0]))
+
+			var err error
+			if r.val, r.buf, err = orcVarint(r.buf[1:], r.signed); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	r.n--
+	if r.literal {
+		v, rest, err := orcVarint(r.buf, r.signed)
+		r.buf = rest
+
+		return v, err
+	}
+
+	v := r.val
+	r.val += r.delta
+
+	return v, nil
+}
+
+// orcBitWidths are the bit widths of the 5 bit width codes of run length
+// encoding v2.
+var orcBitWidths = [32]int{
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+	17, 18, 19, 20, 21, 22, 23, 24, 26, 28, 30, 32, 40, 48, 56, 64,
+}
+
+func orcClosestFixedBits(n int) int {
+	for _, w := range orcBitWidths {
+		if w >= n {
+			return w
+		}
+	}
+
+	return 64
+}
+
+// orcRLEv2 decodes the second version of integer run length encoding,
+// decoding a run at a time.
+type orcRLEv2 struct {
+	buf    []byte
+	signed bool
+	vals   []int64
+	idx    int
+}
+
+func (r *orcRLEv2) next() (int64, error) {
+	if r.idx == len(r.vals) {
+		if err := r.readRun(); err != nil {
+			return 0, err
+		}
+	}
+
+	v := r.vals[r.idx]
+	r.idx++
+
+	return v, nil
+}
+
+func (r *orcRLEv2) value(v uint64) int64 {
+	if r.signed {
+		return zigzag(v)
+	}
+
+	return int64(v)
+}
+
+func (r *orcRLEv2) readRun() error {
+	if len(r.buf) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.vals, r.idx = r.vals[:0], 0
+	switch r.buf[0] >> 6 {
+	case 0:
+		return r.shortRepeat()
+	case 1:
+		return r.direct()
+	case 2:
+		return r.patchedBase()
+	default:
+		return r.delta()
+	}
+}
+
+// bigEndian reads an n byte big endian value.
+func (r *orcRLEv2) bigEndian(n int) (uint64, error) {
+	if len(r.buf) < n {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	var v uint64
+	for _, b := range r.buf[:n] {
+		v = v<<8 | uint64(b)
+	}
+	r.buf = r.buf[n:]
+
+	return v, nil
+}
+
+// unpack reads n bit packed values of the given width, which start on a
+// byte boundary and are padded to the next one.
+func (r *orcRLEv2) unpack(n, width int) ([]uint64, error) {
+	size := (n*width + 7) / 8
+	if len(r.buf) < size {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	out, pos := make([]uint64, n), 0
+	for i := range out {
+		var v uint64
+		for remaining := width; remaining > 0; {
+			avail := 8 - pos%8
+			take := min(avail, remaining)
+			bits := (r.buf[pos/8] >> (avail - take)) & byte(1<<take-1)
+			v = v<<take | uint64(bits)
+			pos, remaining = pos+take, remaining-take
+		}
+		out[i] = v
+	}
+	r.buf = r.buf[size:]
+
+	return out, nil
+}
+
+func (r *orcRLEv2) shortRepeat() error {
+	width, count := int(r.buf[0]>>3&7)+1, int(r.buf[0]&7)+3
+	r.buf = r.buf[1:]
+
+	v, err := r.bigEndian(width)
+	if err != nil {
+		return err
+	}
+
+	val := r.value(v)
+	for range count {
+		r.vals = append(r.vals, val)
+	}
+
+	return nil
+}
+
+// header reads the two byte header of the direct, patched base and delta
+// runs, returning the width code and the length of the run.
+func (r *orcRLEv2) header(size int) ([]byte, byte, int, error) {
+	if len(r.buf) < size {
+		return nil, 0, 0, io.ErrUnexpectedEOF
+	}
+
+	h := r.buf[:size]
+	r.buf = r.buf[size:]
+
+	return h, h[0] >> 1 & 0x1f, int(h[0]&1)<<8 | int(h[1]) + 1, nil
+}
+
+func (r *orcRLEv2) direct() error {
+	_, code, length, err := r.header(2)
+	if err != nil {
+		return err
+	}
+
+	vals, err := r.unpack(length, orcBitWidths[code])
+	if err != nil {
+		return err
+	}
+
+	for _, v := range vals {
+		r.vals = append(r.vals, r.value(v))
+	}
+
+	return nil
+}
+
+func (r *orcRLEv2) patchedBase() error {
+	h, code, length, err := r.header(4)
+	if err != nil {
+		return err
+	}
+
+	width := orcBitWidths[code]
+	baseWidth, patchWidth := int(h[2]>>5)+1, orcBitWidths[h[2]&0x1f]
+	gapWidth, patchCount := int(h[3]>>5)+1, int(h[3]&0x1f)
+
+	// the base is stored in sign magnitude form
+	base, err := r.bigEndian(baseWidth)
+	if err != nil {
+		return err
+	}
+
+	sign := uint64(1) << (baseWidth*8 - 1)
+	baseVal := int64(base &^ sign)
+	if base&sign != 0 {
+		baseVal = -baseVal
+	}
+
+	vals, err := r.unpack(length, width)
+	if err != nil {
+		return err
+	}
+
+	patches, err := r.unpack(patchCount, orcClosestFixedBits(gapWidth+patchWidth))
+	if err != nil {
+		return err
+	}
+
+	// each patch is the gap from the previous patched value and the high
+	// bits of the value
+	idx := 0
+	for _, p := range patches {
+		idx += int(p >> patchWidth)
+		if idx >= length {
+			return errors.New("invalid orc patched base run: patch out of range")
+		}
+		vals[idx] |= (p & (uint64(1)<<patchWidth - 1)) << width
+	}
+
+	for _, v := range vals {
+		r.vals = append(r.vals, baseVal+int64(v))
+	}
+
+	return nil
+}
+
+func (r *orcRLEv2) delta() error {
+	_, code, length, err := r.header(2)
+	if err != nil {
+		return err
+	}
+
+	// a width code of 0 means all deltas are the delta base
+	width := 0
+	if code != 0 {
+		width = orcBitWidths[code]
+	}
+
+	base, rest, err := orcVarint(r.buf, r.signed)
+	if err != nil {
+		return err
+	}
+
+	deltaBase, rest, err := orcVarint(rest, true)
+	if err != nil {
+		return err
+	}
+	r.buf = rest
+
+	r.vals = append(r.vals, base)
+	if length == 1 {
+		return nil
+	}
+
+	prev := base + deltaBase
+	r.vals = append(r.vals, prev)
+	if width == 0 {
+		for range length - 2 {
+			prev += deltaBase
+			r.vals = append(r.vals, prev)
+		}
+
+		return nil
+	}
+
+	deltas, err := r.unpack(length-2, width)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deltas {
+		if deltaBase < 0 {
+			prev -= int64(d)
+		} else {
+			prev += int64(d)
+		}
+		r.vals = append(r.vals, prev)
+	}
+
+	return nil
+}
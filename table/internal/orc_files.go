@@ -0,0 +1,1104 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/extensions"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/google/uuid"
+)
+
+const (
+	orcBatchSize = 1 << 14
+	// orcMaxPostScriptSize is the most the postscript, whose size is
+	// stored in a single byte, can take at the end of the file.
+	orcMaxPostScriptSize = 256
+
+	// the attributes Iceberg stores in the ORC types of the schema
+	orcIcebergID         = "iceberg.id"
+	orcIcebergRequired   = "iceberg.required"
+	orcIcebergLongType   = "iceberg.long-type"
+	orcIcebergBinaryType = "iceberg.binary-type"
+	orcIcebergLength     = "iceberg.length"
+	orcIcebergTimeUnit   = "iceberg.timestamp-unit"
+)
+
+// orcEpoch is the epoch of the seconds of timestamps, 2015-01-01, in the
+// time zone of the writer for timestamps without one.
+func orcEpoch(loc *time.Location) int64 {
+	return time.Date(2015, time.January, 1, 0, 0, 0, 0, loc).Unix()
+}
+
+type orcFormat struct{}
+
+func (orcFormat) Open(ctx context.Context, fs iceio.IO, path string) (FileReader, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrcFileReader(compute.GetAllocator(ctx), f)
+}
+
+func (orcFormat) PathToIDMapping(*iceberg.Schema) (map[string]int, error) {
+	return nil, fmt.Errorf("%w: writing orc data files", iceberg.ErrNotImplemented)
+}
+
+func (orcFormat) DataFileStatsFromMeta(Metadata, map[int]StatisticsCollector, map[string]int) *DataFileStatistics {
+	return nil
+}
+
+func (orcFormat) GetWriteProperties(iceberg.Properties) any { return nil }
+
+func (orcFormat) WriteDataFile(context.Context, iceio.WriteFileIO, WriteFileInfo, []arrow.Record) (iceberg.DataFile, error) {
+	return nil, fmt.Errorf("%w: writing orc data files", iceberg.ErrNotImplemented)
+}
+
+type OrcFileSource struct {
+	mem  memory.Allocator
+	fs   iceio.IO
+	file iceberg.DataFile
+}
+
+func (ofs *OrcFileSource) GetReader(context.Context) (FileReader, error) {
+	f, err := ofs.fs.Open(ofs.file.FilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrcFileReader(ofs.mem, f)
+}
+
+// orcFileReader reads ORC files as arrow records. Fields are identified
+// by the Iceberg field ids stored in the attributes of the ORC types, or
+// by the name mapping for files written without them. The column indices
+// used for projection are the ORC column ids of the primitive leaves.
+type orcFileReader struct {
+	mem    memory.Allocator
+	f      iceio.File
+	size   int64
+	ps     orcPostScript
+	footer orcFooter
+	schema *orcField
+}
+
+func newOrcFileReader(mem memory.Allocator, f iceio.File) (*orcFileReader, error) {
+	r := &orcFileReader{mem: mem, f: f}
+	if err := r.readTail(); err != nil {
+		f.Close()
+
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *orcFileReader) readTail() error {
+	info, err := r.f.Stat()
+	if err != nil {
+		return err
+	}
+	r.size = info.Size()
+
+	tail := make([]byte, min(r.size, orcMaxPostScriptSize))
+	if _, err := r.f.ReadAt(tail, r.size-int64(len(tail))); err != nil {
+		return err
+	}
+
+	psLen := int(tail[len(tail)-1])
+	if psLen+1 > len(tail) {
+		return errors.New("invalid orc file: truncated postscript")
+	}
+
+	if r.ps, err = parseOrcPostScript(tail[len(tail)-1-psLen : len(tail)-1]); err != nil {
+		return err
+	}
+
+	footerOffset := r.size - 1 - int64(psLen) - int64(r.ps.footerLength)
+	footer, err := r.readStream(uint64(footerOffset), r.ps.footerLength)
+	if err != nil {
+		return err
+	}
+
+	if r.footer, err = parseOrcFooter(footer); err != nil {
+		return err
+	}
+
+	if len(r.footer.types) == 0 {
+		return errors.New("invalid orc file: missing schema")
+	}
+
+	if r.footer.types[0].kind != orcStruct {
+		return fmt.Errorf("%w: orc data files must contain structs, got kind %d",
+			iceberg.ErrInvalidSchema, r.footer.types[0].kind)
+	}
+
+	r.schema, err = newOrcField("", 0, r.footer.types)
+
+	return err
+}
+
+// readStream reads and decompresses the length bytes of the file at
+// offset.
+func (r *orcFileReader) readStream(offset, length uint64) ([]byte, error) {
+	if offset+length > uint64(r.size) {
+		return nil, errors.New("invalid orc file: stream out of range")
+	}
+
+	buf := make([]byte, length)
+	if _, err := r.f.ReadAt(buf, int64(offset)); err != nil {
+		return nil, err
+	}
+
+	return orcDecompress(r.ps.compression, r.ps.compressionBlockSize, buf)
+}
+
+func (r *orcFileReader) Metadata() Metadata { return r.footer.metadata }
+
+func (r *orcFileReader) SourceFileSize() int64 { return r.size }
+
+func (r *orcFileReader) Close() error { return r.f.Close() }
+
+func (r *orcFileReader) Schema() (*arrow.Schema, error) {
+	return r.schema.arrowSchema(nil), nil
+}
+
+func (r *orcFileReader) PrunedSchema(projectedIDs map[int]struct{}, mapping iceberg.NameMapping) (*arrow.Schema, []int, error) {
+	leaves := make(map[int]struct{})
+	if err := r.schema.selectLeaves(projectedIDs, &iceberg.MappedField{Fields: mapping}, leaves); err != nil {
+		return nil, nil, err
+	}
+
+	indices := make([]int, 0, len(leaves))
+	for leaf := range leaves {
+		indices = append(indices, leaf)
+	}
+	slices.Sort(indices)
+
+	return r.schema.arrowSchema(leaves), indices, nil
+}
+
+func (r *orcFileReader) GetRecords(_ context.Context, cols []int, _ any) (array.RecordReader, error) {
+	var leaves map[int]struct{}
+	if cols != nil {
+		leaves = make(map[int]struct{}, len(cols))
+		for _, c := range cols {
+			leaves[c] = struct{}{}
+		}
+	}
+
+	out := &orcRecordReader{
+		file:   r,
+		leaves: leaves,
+		schema: r.schema.arrowSchema(leaves),
+	}
+	out.refCount.Add(1)
+
+	return out, nil
+}
+
+func (r *orcFileReader) ReadTable(ctx context.Context) (arrow.Table, error) {
+	rdr, err := r.GetRecords(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+
+	recs := make([]arrow.Record, 0)
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+
+	for rdr.Next() {
+		rec := rdr.Record()
+		rec.Retain()
+		recs = append(recs, rec)
+	}
+
+	if err := rdr.Err(); err != nil {
+		return nil, err
+	}
+
+	return array.NewTableFromRecords(rdr.Schema(), recs), nil
+}
+
+// orcRecordReader returns records of up to orcBatchSize rows of a stripe
+// at a time.
+type orcRecordReader struct {
+	refCount atomic.Int64
+
+	file   *orcFileReader
+	leaves map[int]struct{}
+	schema *arrow.Schema
+
+	stripe  int
+	rows    uint64
+	columns []orcColumn
+	cur     arrow.Record
+	err     error
+}
+
+func (r *orcRecordReader) Retain() { r.refCount.Add(1) }
+
+func (r *orcRecordReader) Release() {
+	if r.refCount.Add(-1) == 0 && r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+}
+
+func (r *orcRecordReader) Schema() *arrow.Schema { return r.schema }
+
+func (r *orcRecordReader) Record() arrow.Record { return r.cur }
+
+func (r *orcRecordReader) Err() error { return r.err }
+
+func (r *orcRecordReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+
+	if r.err != nil {
+		return false
+	}
+
+	for r.rows == 0 {
+		if r.stripe == len(r.file.footer.stripes) {
+			return false
+		}
+
+		if r.err = r.loadStripe(r.file.footer.stripes[r.stripe]); r.err != nil {
+			return false
+		}
+		r.stripe++
+	}
+
+	bldr := array.NewRecordBuilder(r.file.mem, r.schema)
+	defer bldr.Release()
+
+	n := min(r.rows, orcBatchSize)
+	for range n {
+		for i, col := range r.columns {
+			if r.err = col(bldr.Field(i)); r.err != nil {
+				return false
+			}
+		}
+	}
+	r.rows -= n
+	r.cur = bldr.NewRecord()
+
+	return true
+}
+
+func (r *orcRecordReader) loadStripe(info orcStripeInfo) error {
+	buf, err := r.file.readStream(info.offset+info.indexLength+info.dataLength, info.footerLength)
+	if err != nil {
+		return err
+	}
+
+	footer, err := parseOrcStripeFooter(buf)
+	if err != nil {
+		return err
+	}
+
+	loc := time.UTC
+	if footer.writerTimezone != "" {
+		if loc, err = time.LoadLocation(footer.writerTimezone); err != nil {
+			return err
+		}
+	}
+
+	stripe := &orcStripe{
+		file:    r.file,
+		footer:  footer,
+		loc:     loc,
+		streams: make(map[orcStreamKey][2]uint64, len(footer.streams)),
+	}
+
+	// the streams are stored one after the other in the order of the footer
+	offset := info.offset
+	for _, s := range footer.streams {
+		stripe.streams[orcStreamKey{column: int(s.column), kind: s.kind}] = [2]uint64{offset, s.length}
+		offset += s.length
+	}
+
+	r.columns = r.columns[:0]
+	for _, child := range r.file.schema.children {
+		if !child.projected(r.leaves) {
+			continue
+		}
+
+		col, err := child.reader(stripe, r.leaves)
+		if err != nil {
+			return err
+		}
+		r.columns = append(r.columns, col)
+	}
+	r.rows = info.numRows
+
+	return nil
+}
+
+type orcStreamKey struct {
+	column int
+	kind   orcStreamKind
+}
+
+// orcStripe gives access to the streams of the columns of a stripe.
+type orcStripe struct {
+	file    *orcFileReader
+	footer  orcStripeFooter
+	loc     *time.Location
+	streams map[orcStreamKey][2]uint64
+}
+
+// stream returns the content of the stream, or nil if the column doesn't
+// have it.
+func (s *orcStripe) stream(column int, kind orcStreamKind) ([]byte, error) {
+	loc, ok := s.streams[orcStreamKey{column: column, kind: kind}]
+	if !ok {
+		return nil, nil
+	}
+
+	return s.file.readStream(loc[0], loc[1])
+}
+
+func (s *orcStripe) requiredStream(column int, kind orcStreamKind) ([]byte, error) {
+	if _, ok := s.streams[orcStreamKey{column: column, kind: kind}]; !ok {
+		return nil, fmt.Errorf("invalid orc file: missing stream %d of column %d", kind, column)
+	}
+
+	return s.stream(column, kind)
+}
+
+func (s *orcStripe) encoding(column int) orcColumnEncoding {
+	if column < len(s.footer.columns) {
+		return s.footer.columns[column]
+	}
+
+	return orcColumnEncoding{}
+}
+
+func (s *orcStripe) intReader(column int, kind orcStreamKind, signed bool) (orcIntReader, error) {
+	buf, err := s.requiredStream(column, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrcIntReader(buf, signed, s.encoding(column).kind), nil
+}
+
+// orcColumn appends the next value of a column of a stripe to the
+// builder.
+type orcColumn func(array.Builder) error
+
+// orcField is a field of the ORC schema of a file, along with how to
+// convert it to arrow.
+type orcField struct {
+	name     string
+	fieldID  *int
+	column   int
+	kind     orcKind
+	nullable bool
+	// typ is the arrow type of primitives, which are the leaves of the
+	// schema identified by their column.
+	typ      arrow.DataType
+	children []*orcField
+}
+
+func orcAttrID(typ orcType) *int {
+	if id, err := strconv.Atoi(typ.attributes[orcIcebergID]); err == nil {
+		return &id
+	}
+
+	return nil
+}
+
+func newOrcField(name string, column int, types []orcType) (*orcField, error) {
+	if column >= len(types) {
+		return nil, fmt.Errorf("invalid orc file: unknown column %d", column)
+	}
+
+	typ := types[column]
+	f := &orcField{
+		name:     name,
+		fieldID:  orcAttrID(typ),
+		column:   column,
+		kind:     typ.kind,
+		nullable: typ.attributes[orcIcebergRequired] != "true",
+	}
+
+	childNames := typ.fieldNames
+	switch typ.kind {
+	case orcStruct:
+		if len(childNames) != len(typ.subtypes) {
+			return nil, fmt.Errorf("invalid orc file: struct column %d has %d names for %d fields",
+				column, len(childNames), len(typ.subtypes))
+		}
+	case orcList:
+		childNames = []string{"element"}
+	case orcMap:
+		childNames = []string{"key", "value"}
+	case orcUnion:
+		return nil, fmt.Errorf("%w: orc union of field %s", iceberg.ErrNotImplemented, name)
+	default:
+		var err error
+		if f.typ, err = orcArrowType(typ); err != nil {
+			return nil, fmt.Errorf("%w: field %s", err, name)
+		}
+
+		return f, nil
+	}
+
+	if len(typ.subtypes) != len(childNames) {
+		return nil, fmt.Errorf("invalid orc file: column %d has %d children", column, len(typ.subtypes))
+	}
+
+	for i, sub := range typ.subtypes {
+		if int(sub) <= column {
+			return nil, fmt.Errorf("invalid orc file: column %d has child %d", column, sub)
+		}
+
+		child, err := newOrcField(childNames[i], int(sub), types)
+		if err != nil {
+			return nil, err
+		}
+		f.children = append(f.children, child)
+	}
+
+	return f, nil
+}
+
+func orcArrowType(typ orcType) (arrow.DataType, error) {
+	unit := arrow.Microsecond
+	if typ.attributes[orcIcebergTimeUnit] == "NANOS" {
+		unit = arrow.Nanosecond
+	}
+
+	switch typ.kind {
+	case orcBoolean:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case orcByte, orcShort, orcInt:
+		return arrow.PrimitiveTypes.Int32, nil
+	case orcLong:
+		if typ.attributes[orcIcebergLongType] == "TIME" {
+			return arrow.FixedWidthTypes.Time64us, nil
+		}
+
+		return arrow.PrimitiveTypes.Int64, nil
+	case orcFloat:
+		return arrow.PrimitiveTypes.Float32, nil
+	case orcDouble:
+		return arrow.PrimitiveTypes.Float64, nil
+	case orcString, orcVarchar, orcChar:
+		return arrow.BinaryTypes.String, nil
+	case orcBinary:
+		switch typ.attributes[orcIcebergBinaryType] {
+		case "UUID":
+			return extensions.NewUUIDType(), nil
+		case "FIXED":
+			size, err := strconv.Atoi(typ.attributes[orcIcebergLength])
+			if err != nil {
+				return nil, fmt.Errorf("%w: orc fixed type without a valid length", iceberg.ErrInvalidSchema)
+			}
+
+			return &arrow.FixedSizeBinaryType{ByteWidth: size}, nil
+		}
+
+		return arrow.BinaryTypes.Binary, nil
+	case orcTimestamp:
+		return &arrow.TimestampType{Unit: unit}, nil
+	case orcTimestampInstant:
+		return &arrow.TimestampType{Unit: unit, TimeZone: "UTC"}, nil
+	case orcDate:
+		return arrow.FixedWidthTypes.Date32, nil
+	case orcDecimal:
+		// files written by Hive before decimals had a precision use the
+		// widest one
+		if typ.precision == 0 {
+			return &arrow.Decimal128Type{Precision: 38, Scale: 10}, nil
+		}
+
+		return &arrow.Decimal128Type{Precision: int32(typ.precision), Scale: int32(typ.scale)}, nil
+	default:
+		return nil, fmt.Errorf("%w: orc type kind %d", iceberg.ErrNotImplemented, typ.kind)
+	}
+}
+
+func (f *orcField) isPrimitive() bool { return f.typ != nil }
+
+// id returns the field id from the name mapping, falling back to the one
+// in the file, or -1 if there is neither.
+func (f *orcField) id(mapping *iceberg.MappedField) int {
+	if mapping != nil && mapping.FieldID != nil {
+		return *mapping.FieldID
+	}
+
+	if f.fieldID != nil {
+		return *f.fieldID
+	}
+
+	return -1
+}
+
+// selectLeaves adds the leaves needed to read the projected fields, using
+// the same rules as pruning the columns of parquet files.
+func (f *orcField) selectLeaves(selected map[int]struct{}, mapping *iceberg.MappedField, leaves map[int]struct{}) error {
+	switch f.kind {
+	case orcStruct:
+		for _, child := range f.children {
+			m := childMapping(mapping, child.name)
+			if !child.isPrimitive() {
+				if err := child.selectLeaves(selected, m, leaves); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			id := child.id(m)
+			if id == -1 {
+				return fmt.Errorf("%w: cannot convert %s to Iceberg field, missing field_id",
+					iceberg.ErrInvalidSchema, child.name)
+			}
+
+			if _, ok := selected[id]; ok {
+				leaves[child.column] = struct{}{}
+			}
+		}
+	case orcList:
+		elem := &orcField{kind: orcStruct, children: f.children}
+
+		return elem.selectLeaves(selected, mapping, leaves)
+	case orcMap:
+		key, val := f.children[0], f.children[1]
+		before := len(leaves)
+		value := &orcField{kind: orcStruct, children: []*orcField{val}}
+		if err := value.selectLeaves(selected, mapping, leaves); err != nil {
+			return err
+		}
+
+		if len(leaves) > before {
+			// the keys are always needed to read the values
+			key.allLeaves(leaves)
+		}
+	}
+
+	return nil
+}
+
+func (f *orcField) allLeaves(leaves map[int]struct{}) {
+	if f.isPrimitive() {
+		leaves[f.column] = struct{}{}
+	}
+
+	for _, child := range f.children {
+		child.allLeaves(leaves)
+	}
+}
+
+// projected reports whether any of the leaves of the field are read. A nil
+// set of leaves reads all of them.
+func (f *orcField) projected(leaves map[int]struct{}) bool {
+	if leaves == nil {
+		return true
+	}
+
+	if f.isPrimitive() {
+		_, ok := leaves[f.column]
+
+		return ok
+	}
+
+	return slices.ContainsFunc(f.children, func(c *orcField) bool { return c.projected(leaves) })
+}
+
+func (f *orcField) arrowSchema(leaves map[int]struct{}) *arrow.Schema {
+	return arrow.NewSchema(f.arrowType(leaves).(*arrow.StructType).Fields(), nil)
+}
+
+func (f *orcField) arrowField(leaves map[int]struct{}) arrow.Field {
+	result := arrow.Field{Name: f.name, Type: f.arrowType(leaves), Nullable: f.nullable}
+	if f.fieldID != nil {
+		result.Metadata = arrow.NewMetadata([]string{"PARQUET:field_id"},
+			[]string{strconv.Itoa(*f.fieldID)})
+	}
+
+	return result
+}
+
+func (f *orcField) arrowType(leaves map[int]struct{}) arrow.DataType {
+	switch f.kind {
+	case orcStruct:
+		fields := make([]arrow.Field, 0, len(f.children))
+		for _, child := range f.children {
+			if child.projected(leaves) {
+				fields = append(fields, child.arrowField(leaves))
+			}
+		}
+
+		return arrow.StructOf(fields...)
+	case orcList:
+		return arrow.ListOfField(f.children[0].arrowField(leaves))
+	case orcMap:
+		return arrow.MapOfFields(f.children[0].arrowField(nil), f.children[1].arrowField(leaves))
+	default:
+		return f.typ
+	}
+}
+
+// reader returns the column appending the values of the field in the
+// stripe to the builder for its projected type. The field must be
+// projected.
+func (f *orcField) reader(s *orcStripe, leaves map[int]struct{}) (orcColumn, error) {
+	present, err := s.stream(f.column, orcStreamPresent)
+	if err != nil {
+		return nil, err
+	}
+
+	appendValue, err := f.valueReader(s, leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	if present == nil {
+		return appendValue, nil
+	}
+
+	// the values of the field and of its children are only stored for the
+	// rows where it is present
+	isPresent := newOrcBoolReader(present)
+
+	return func(b array.Builder) error {
+		ok, err := isPresent.next()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			b.AppendNull()
+
+			return nil
+		}
+
+		return appendValue(b)
+	}, nil
+}
+
+func (f *orcField) valueReader(s *orcStripe, leaves map[int]struct{}) (orcColumn, error) {
+	switch f.kind {
+	case orcStruct:
+		children := make([]orcColumn, 0, len(f.children))
+		for _, child := range f.children {
+			if !child.projected(leaves) {
+				continue
+			}
+
+			col, err := child.reader(s, leaves)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, col)
+		}
+
+		return func(b array.Builder) error {
+			sb := b.(*array.StructBuilder)
+			sb.Append(true)
+			for i, child := range children {
+				if err := child(sb.FieldBuilder(i)); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}, nil
+	case orcList:
+		lengths, err := s.intReader(f.column, orcStreamLength, false)
+		if err != nil {
+			return nil, err
+		}
+
+		elem, err := f.children[0].reader(s, leaves)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(b array.Builder) error {
+			n, err := lengths.next()
+			if err != nil {
+				return err
+			}
+
+			lb := b.(*array.ListBuilder)
+			lb.Append(true)
+			for range n {
+				if err := elem(lb.ValueBuilder()); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}, nil
+	case orcMap:
+		lengths, err := s.intReader(f.column, orcStreamLength, false)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := f.children[0].reader(s, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := f.children[1].reader(s, leaves)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(b array.Builder) error {
+			n, err := lengths.next()
+			if err != nil {
+				return err
+			}
+
+			mb := b.(*array.MapBuilder)
+			mb.Append(true)
+			for range n {
+				if err := key(mb.KeyBuilder()); err != nil {
+					return err
+				}
+
+				if err := val(mb.ItemBuilder()); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}, nil
+	default:
+		return f.primitiveReader(s)
+	}
+}
+
+func (f *orcField) primitiveReader(s *orcStripe) (orcColumn, error) {
+	switch f.kind {
+	case orcBoolean:
+		data, err := s.requiredStream(f.column, orcStreamData)
+		if err != nil {
+			return nil, err
+		}
+
+		values := newOrcBoolReader(data)
+
+		return func(b array.Builder) error {
+			v, err := values.next()
+			if err != nil {
+				return err
+			}
+			b.(*array.BooleanBuilder).Append(v)
+
+			return nil
+		}, nil
+	case orcByte:
+		data, err := s.requiredStream(f.column, orcStreamData)
+		if err != nil {
+			return nil, err
+		}
+
+		values := &orcByteReader{buf: data}
+
+		return func(b array.Builder) error {
+			v, err := values.next()
+			if err != nil {
+				return err
+			}
+			b.(*array.Int32Builder).Append(int32(int8(v)))
+
+			return nil
+		}, nil
+	case orcShort, orcInt, orcLong, orcDate:
+		values, err := s.intReader(f.column, orcStreamData, true)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(b array.Builder) error {
+			v, err := values.next()
+			if err != nil {
+				return err
+			}
+
+			switch b := b.(type) {
+			case *array.Int32Builder:
+				b.Append(int32(v))
+			case *array.Int64Builder:
+				b.Append(v)
+			case *array.Time64Builder:
+				b.Append(arrow.Time64(v))
+			case *array.Date32Builder:
+				b.Append(arrow.Date32(v))
+			}
+
+			return nil
+		}, nil
+	case orcFloat, orcDouble:
+		data, err := s.requiredStream(f.column, orcStreamData)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(b array.Builder) error {
+			switch b := b.(type) {
+			case *array.Float32Builder:
+				if len(data) < 4 {
+					return io.ErrUnexpectedEOF
+				}
+				b.Append(math.Float32frombits(binary.LittleEndian.Uint32(data)))
+				data = data[4:]
+			case *array.Float64Builder:
+				if len(data) < 8 {
+					return io.ErrUnexpectedEOF
+				}
+				b.Append(math.Float64frombits(binary.LittleEndian.Uint64(data)))
+				data = data[8:]
+			}
+
+			return nil
+		}, nil
+	case orcString, orcVarchar, orcChar, orcBinary:
+		return f.bytesReader(s)
+	case orcDecimal:
+		return f.decimalReader(s)
+	case orcTimestamp, orcTimestampInstant:
+		return f.timestampReader(s)
+	default:
+		return nil, fmt.Errorf("%w: orc type kind %d", iceberg.ErrNotImplemented, f.kind)
+	}
+}
+
+func appendOrcBytes(b array.Builder, v []byte) error {
+	switch b := b.(type) {
+	case *array.StringBuilder:
+		b.Append(string(v))
+	case *array.BinaryBuilder:
+		b.Append(v)
+	case *extensions.UUIDBuilder:
+		if len(v) != 16 {
+			return fmt.Errorf("invalid orc uuid of %d bytes", len(v))
+		}
+		b.Append(uuid.UUID(v))
+	case *array.FixedSizeBinaryBuilder:
+		if size := b.Type().(*arrow.FixedSizeBinaryType).ByteWidth; len(v) != size {
+			return fmt.Errorf("invalid orc fixed value of %d bytes, expected %d", len(v), size)
+		}
+		b.Append(v)
+	}
+
+	return nil
+}
+
+func (f *orcField) bytesReader(s *orcStripe) (orcColumn, error) {
+	data, err := s.stream(f.column, orcStreamData)
+	if err != nil {
+		return nil, err
+	}
+
+	lengths, err := s.intReader(f.column, orcStreamLength, false)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := s.encoding(f.column)
+	if enc.kind == orcEncodingDirect || enc.kind == orcEncodingDirectV2 {
+		return func(b array.Builder) error {
+			n, err := lengths.next()
+			if err != nil {
+				return err
+			}
+
+			if n < 0 || n > int64(len(data)) {
+				return io.ErrUnexpectedEOF
+			}
+
+			v := data[:n]
+			data = data[n:]
+
+			return appendOrcBytes(b, v)
+		}, nil
+	}
+
+	// dictionary encoded values are indices of the dictionary, whose
+	// entries are stored one after the other along with their lengths
+	dictData, err := s.stream(f.column, orcStreamDictionaryData)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := make([][]byte, enc.dictionarySize)
+	for i := range dict {
+		n, err := lengths.next()
+		if err != nil {
+			return nil, err
+		}
+
+		if n < 0 || n > int64(len(dictData)) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		dict[i], dictData = dictData[:n], dictData[n:]
+	}
+
+	indices := newOrcIntReader(data, false, enc.kind)
+
+	return func(b array.Builder) error {
+		idx, err := indices.next()
+		if err != nil {
+			return err
+		}
+
+		if idx < 0 || idx >= int64(len(dict)) {
+			return fmt.Errorf("invalid orc dictionary index %d", idx)
+		}
+
+		return appendOrcBytes(b, dict[idx])
+	}, nil
+}
+
+func (f *orcField) decimalReader(s *orcStripe) (orcColumn, error) {
+	data, err := s.requiredStream(f.column, orcStreamData)
+	if err != nil {
+		return nil, err
+	}
+
+	// the scale of each value is stored along with its unscaled value
+	scales, err := s.intReader(f.column, orcStreamSecondary, true)
+	if err != nil {
+		return nil, err
+	}
+
+	typeScale := int64(f.typ.(*arrow.Decimal128Type).Scale)
+
+	return func(b array.Builder) error {
+		v, rest, err := orcBigVarint(data)
+		if err != nil {
+			return err
+		}
+		data = rest
+
+		scale, err := scales.next()
+		if err != nil {
+			return err
+		}
+
+		num := decimal128.FromBigInt(v)
+		switch {
+		case scale < typeScale:
+			num = num.IncreaseScaleBy(int32(typeScale - scale))
+		case scale > typeScale:
+			num = num.ReduceScaleBy(int32(scale-typeScale), false)
+		}
+		b.(*array.Decimal128Builder).Append(num)
+
+		return nil
+	}, nil
+}
+
+func orcNanos(v int64) int64 {
+	// the low 3 bits are the number of trailing zeros removed, minus one
+	zeros := v & 7
+	v >>= 3
+	if zeros != 0 {
+		for range zeros + 1 {
+			v *= 10
+		}
+	}
+
+	return v
+}
+
+func (f *orcField) timestampReader(s *orcStripe) (orcColumn, error) {
+	seconds, err := s.intReader(f.column, orcStreamData, true)
+	if err != nil {
+		return nil, err
+	}
+
+	nanos, err := s.intReader(f.column, orcStreamSecondary, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// timestamps without a time zone are stored as the instant of their
+	// wall clock in the time zone of the writer
+	loc := s.loc
+	if f.kind == orcTimestampInstant {
+		loc = time.UTC
+	}
+	epoch := orcEpoch(loc)
+	unit := f.typ.(*arrow.TimestampType).Unit
+
+	return func(b array.Builder) error {
+		secs, err := seconds.next()
+		if err != nil {
+			return err
+		}
+
+		ns, err := nanos.next()
+		if err != nil {
+			return err
+		}
+
+		secs, ns = secs+epoch, orcNanos(ns)
+		// writers truncate the seconds of times before the epoch towards
+		// zero
+		if secs < 0 && ns > 999999 {
+			secs--
+		}
+
+		if loc != time.UTC {
+			_, offset := time.Unix(secs, 0).In(loc).Zone()
+			secs += int64(offset)
+		}
+
+		if unit == arrow.Nanosecond {
+			b.(*array.TimestampBuilder).Append(arrow.Timestamp(secs*1e9 + ns))
+		} else {
+			b.(*array.TimestampBuilder).Append(arrow.Timestamp(secs*1e6 + ns/1e3))
+		}
+
+		return nil
+	}, nil
+}
@@ -0,0 +1,155 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"io"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readOrcInts(t *testing.T, r orcIntReader, n int) []int64 {
+	out := make([]int64, n)
+	for i := range out {
+		v, err := r.next()
+		require.NoError(t, err)
+		out[i] = v
+	}
+
+	_, err := r.next()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	return out
+}
+
+func TestOrcRLEv2(t *testing.T) {
+	// the examples of the ORC specification
+	tests := []struct {
+		name     string
+		data     []byte
+		signed   bool
+		expected []int64
+	}{
+		{"short repeat", []byte{0x0a, 0x27, 0x10}, false,
+			[]int64{10000, 10000, 10000, 10000, 10000}},
+		{"direct", []byte{0x5e, 0x03, 0x5c, 0xa1, 0xab, 0x1e, 0xde, 0xad, 0xbe, 0xef}, false,
+			[]int64{23713, 43806, 57005, 48879}},
+		{"patched base", []byte{
+			0x8e, 0x13, 0x2b, 0x21, 0x07, 0xd0, 0x1e, 0x00, 0x14, 0x70, 0x28, 0x32, 0x3c, 0x46,
+			0x50, 0x5a, 0x64, 0x6e, 0x78, 0x82, 0x8c, 0x96, 0xa0, 0xaa, 0xb4, 0xbe, 0xfc, 0xe8,
+		}, false, []int64{
+			2030, 2000, 2020, 1000000, 2040, 2050, 2060, 2070, 2080, 2090,
+			2100, 2110, 2120, 2130, 2140, 2150, 2160, 2170, 2180, 2190,
+		}},
+		{"delta", []byte{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46}, false,
+			[]int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}},
+		{"fixed delta", []byte{0xc0, 0x04, 0x13, 0x03}, true,
+			[]int64{-10, -12, -14, -16, -18}},
+		{"signed short repeat", []byte{0x00, 0x05}, true, []int64{-3, -3, -3}},
+		{"runs", []byte{0x00, 0x05, 0x0a, 0x27, 0x10}, false,
+			[]int64{5, 5, 5, 10000, 10000, 10000, 10000, 10000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newOrcIntReader(tt.data, tt.signed, orcEncodingDirectV2)
+			assert.Equal(t, tt.expected, readOrcInts(t, r, len(tt.expected)))
+		})
+	}
+}
+
+func TestOrcRLEv1(t *testing.T) {
+	t.Run("run", func(t *testing.T) {
+		r := newOrcIntReader([]byte{0x61, 0x00, 0x07}, false, orcEncodingDirect)
+		assert.Equal(t, slices.Repeat([]int64{7}, 100), readOrcInts(t, r, 100))
+	})
+
+	t.Run("delta run", func(t *testing.T) {
+		r := newOrcIntReader([]byte{0x00, 0xff, 0x09}, true, orcEncodingDirect)
+		assert.Equal(t, []int64{-5, -6, -7}, readOrcInts(t, r, 3))
+	})
+
+	t.Run("literals", func(t *testing.T) {
+		r := newOrcIntReader([]byte{0xfb, 0x02, 0x03, 0x06, 0x07, 0x0b}, false, orcEncodingDirect)
+		assert.Equal(t, []int64{2, 3, 6, 7, 11}, readOrcInts(t, r, 5))
+	})
+}
+
+func TestOrcByteAndBoolRLE(t *testing.T) {
+	bytesRdr := &orcByteReader{buf: []byte{0x61, 0x00, 0xfe, 0x44, 0x45}}
+	for i := range 100 {
+		v, err := bytesRdr.next()
+		require.NoError(t, err)
+		require.Zero(t, v, i)
+	}
+
+	for _, expected := range []byte{0x44, 0x45} {
+		v, err := bytesRdr.next()
+		require.NoError(t, err)
+		assert.Equal(t, expected, v)
+	}
+
+	_, err := bytesRdr.next()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	bools := newOrcBoolReader([]byte{0xff, 0x80})
+	var got []bool
+	for range 8 {
+		v, err := bools.next()
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+	assert.Equal(t, []bool{true, false, false, false, false, false, false, false}, got)
+}
+
+func TestOrcBigVarint(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+
+	// zigzag encode the value in base 128
+	encoded := new(big.Int).Lsh(huge, 1)
+	encoded.Neg(encoded).Sub(encoded, big.NewInt(1))
+	var buf []byte
+	for encoded.BitLen() > 7 {
+		buf = append(buf, byte(encoded.Uint64()&0x7f)|0x80)
+		encoded.Rsh(encoded, 7)
+	}
+	buf = append(buf, byte(encoded.Uint64()), 0x03)
+
+	v, rest, err := orcBigVarint(buf)
+	require.NoError(t, err)
+	assert.Zero(t, huge.Cmp(v), v.String())
+
+	v, rest, err = orcBigVarint(rest)
+	require.NoError(t, err)
+	assert.EqualValues(t, -2, v.Int64())
+	assert.Empty(t, rest)
+
+	_, _, err = orcBigVarint([]byte{0x80, 0x80})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestOrcNanos(t *testing.T) {
+	assert.EqualValues(t, 0, orcNanos(0))
+	assert.EqualValues(t, 500000000, orcNanos(5<<3|7))
+	assert.EqualValues(t, 1000, orcNanos(1<<3|2))
+	assert.EqualValues(t, 123456789, orcNanos(123456789<<3))
+}
@@ -0,0 +1,744 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/apache/iceberg-go/table/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The tests below read files produced by a minimal ORC writer, which
+// stores integers as direct runs of 64 bit values and everything else
+// with the simplest encodings of the specification, or the streams of
+// the examples of the specification.
+
+type orcTestEntry struct {
+	key   string
+	value *int64
+}
+
+type orcTestRow struct {
+	id       int64
+	name     *string
+	category int64 // index in orcTestCategories
+	unscaled int64
+	scale    int64
+	ts       time.Time
+	day      int64
+	tags     []string
+	props    []orcTestEntry
+	location *[2]float64
+	flag     bool
+}
+
+var orcTestCategories = []string{"x", "y"}
+
+func orcTestRows() []orcTestRow {
+	str := func(s string) *string { return &s }
+	num := func(v int64) *int64 { return &v }
+	loc := &[2]float64{1.5, -2.5}
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	return []orcTestRow{
+		{id: 0, name: str("a"), category: 0, unscaled: 1050, scale: 2, ts: ts, day: 19844,
+			tags: []string{"x", "y"}, props: []orcTestEntry{{"k", num(0)}}, location: loc, flag: true},
+		{id: 1, category: 1, unscaled: 105, scale: 1, ts: time.Unix(-2, 5e8).UTC(), day: 19845,
+			props: []orcTestEntry{{"a", num(1)}, {"b", nil}}, location: loc},
+		{id: 2, name: str("c"), category: 0, unscaled: -325, scale: 2, ts: ts.Add(time.Hour), day: 19846,
+			tags: []string{}, location: loc, flag: true},
+		{id: 3, name: str("d"), category: 0, unscaled: 0, scale: 2, ts: ts.Add(2 * time.Hour), day: 19847,
+			tags: []string{"z"}, props: []orcTestEntry{{"k", num(3)}}},
+		{id: 4, category: 1, unscaled: 9999, scale: 2, ts: ts.Add(3 * time.Hour), day: 19848,
+			tags: []string{"x"}, props: []orcTestEntry{{"k", num(4)}}, location: loc, flag: true},
+	}
+}
+
+func pbVarint(b []byte, field int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3)
+
+	return binary.AppendUvarint(b, v)
+}
+
+func pbBytes(b []byte, field int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+
+	return append(b, v...)
+}
+
+func orcZigzag(v int64) uint64 { return uint64(v<<1) ^ uint64(v>>63) }
+
+func orcByteLiterals(vals []byte) []byte {
+	var out []byte
+	for len(vals) > 0 {
+		n := min(len(vals), 128)
+		out = append(out, byte(256-n))
+		out = append(out, vals[:n]...)
+		vals = vals[n:]
+	}
+
+	return out
+}
+
+func orcBools(vals []bool) []byte {
+	packed := make([]byte, (len(vals)+7)/8)
+	for i, v := range vals {
+		if v {
+			packed[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+
+	return orcByteLiterals(packed)
+}
+
+// orcIntsV2 encodes the values as direct runs of 64 bit integers.
+func orcIntsV2(vals []int64, signed bool) []byte {
+	var out []byte
+	for len(vals) > 0 {
+		n := min(len(vals), 512)
+		out = append(out, 0x40|31<<1|byte((n-1)>>8), byte(n-1))
+		for _, v := range vals[:n] {
+			u := uint64(v)
+			if signed {
+				u = orcZigzag(v)
+			}
+			out = binary.BigEndian.AppendUint64(out, u)
+		}
+		vals = vals[n:]
+	}
+
+	return out
+}
+
+// orcIntsV1 encodes the values as literals of the first version of the
+// integer run length encoding.
+func orcIntsV1(vals []int64, signed bool) []byte {
+	var out []byte
+	for len(vals) > 0 {
+		n := min(len(vals), 128)
+		out = append(out, byte(256-n))
+		for _, v := range vals[:n] {
+			u := uint64(v)
+			if signed {
+				u = orcZigzag(v)
+			}
+			out = binary.AppendUvarint(out, u)
+		}
+		vals = vals[n:]
+	}
+
+	return out
+}
+
+type orcTestStream struct {
+	column int
+	kind   int
+	data   []byte
+}
+
+const (
+	orcTestPresent = iota
+	orcTestData
+	orcTestLength
+	orcTestDictionaryData
+	_
+	orcTestSecondary
+)
+
+// orcTestStripe encodes the rows as the streams of a stripe, along with
+// the encoding of each column.
+func orcTestStripe(rows []orcTestRow) ([]orcTestStream, [][2]uint64) {
+	var (
+		ids, catIdx, scales, secs, nanos, days         []int64
+		nameLens, tagLens, elemLens, propLens, keyLens []int64
+		values                                         []int64
+		namePresent, tagsPresent, valuePresent         []bool
+		locPresent, flags                              []bool
+		names, elems, keys, decimals, lats, lons       []byte
+	)
+
+	epoch := time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	for _, r := range rows {
+		ids = append(ids, r.id)
+
+		namePresent = append(namePresent, r.name != nil)
+		if r.name != nil {
+			names = append(names, *r.name...)
+			nameLens = append(nameLens, int64(len(*r.name)))
+		}
+
+		catIdx = append(catIdx, r.category)
+		decimals = binary.AppendUvarint(decimals, orcZigzag(r.unscaled))
+		scales = append(scales, r.scale)
+
+		// writers truncate the seconds towards zero
+		nano := r.ts.UnixNano()
+		secs = append(secs, nano/1e9-epoch)
+		nanos = append(nanos, (nano%1e9+1e9)%1e9<<3)
+		days = append(days, r.day)
+
+		tagsPresent = append(tagsPresent, r.tags != nil)
+		if r.tags != nil {
+			tagLens = append(tagLens, int64(len(r.tags)))
+			for _, tag := range r.tags {
+				elems = append(elems, tag...)
+				elemLens = append(elemLens, int64(len(tag)))
+			}
+		}
+
+		propLens = append(propLens, int64(len(r.props)))
+		for _, e := range r.props {
+			keys = append(keys, e.key...)
+			keyLens = append(keyLens, int64(len(e.key)))
+			valuePresent = append(valuePresent, e.value != nil)
+			if e.value != nil {
+				values = append(values, *e.value)
+			}
+		}
+
+		locPresent = append(locPresent, r.location != nil)
+		if r.location != nil {
+			lats = binary.LittleEndian.AppendUint64(lats, math.Float64bits(r.location[0]))
+			lons = binary.LittleEndian.AppendUint64(lons, math.Float64bits(r.location[1]))
+		}
+		flags = append(flags, r.flag)
+	}
+
+	var dict []byte
+	dictLens := make([]int64, len(orcTestCategories))
+	for i, c := range orcTestCategories {
+		dict = append(dict, c...)
+		dictLens[i] = int64(len(c))
+	}
+
+	streams := []orcTestStream{
+		{1, orcTestData, orcIntsV2(ids, true)},
+		{2, orcTestPresent, orcBools(namePresent)},
+		{2, orcTestData, names},
+		{2, orcTestLength, orcIntsV2(nameLens, false)},
+		{3, orcTestData, orcIntsV2(catIdx, false)},
+		{3, orcTestLength, orcIntsV2(dictLens, false)},
+		{3, orcTestDictionaryData, dict},
+		{4, orcTestData, decimals},
+		{4, orcTestSecondary, orcIntsV2(scales, true)},
+		{5, orcTestData, orcIntsV1(secs, true)},
+		{5, orcTestSecondary, orcIntsV1(nanos, false)},
+		{6, orcTestData, orcIntsV2(days, true)},
+		{7, orcTestPresent, orcBools(tagsPresent)},
+		{7, orcTestLength, orcIntsV2(tagLens, false)},
+		{8, orcTestData, elems},
+		{8, orcTestLength, orcIntsV2(elemLens, false)},
+		{9, orcTestLength, orcIntsV2(propLens, false)},
+		{10, orcTestData, keys},
+		{10, orcTestLength, orcIntsV2(keyLens, false)},
+		{11, orcTestPresent, orcBools(valuePresent)},
+		{11, orcTestData, orcIntsV2(values, true)},
+		{12, orcTestPresent, orcBools(locPresent)},
+		{13, orcTestData, lats},
+		{14, orcTestData, lons},
+		{15, orcTestData, orcBools(flags)},
+	}
+
+	const direct, directV2, dictionaryV2 = 0, 2, 3
+	encodings := make([][2]uint64, 16)
+	for i := range encodings {
+		encodings[i] = [2]uint64{directV2, 0}
+	}
+	encodings[3] = [2]uint64{dictionaryV2, uint64(len(orcTestCategories))}
+	encodings[5] = [2]uint64{direct, 0}
+
+	return streams, encodings
+}
+
+type orcTestType struct {
+	kind     uint64
+	subtypes []uint64
+	names    []string
+	required bool
+}
+
+// orcTestTypes returns the schema of the test files, with the columns
+// numbered by their field id.
+func orcTestTypes(withIDs bool) [][]byte {
+	return orcTestTypeMessages([]orcTestType{
+		{kind: 12, subtypes: []uint64{1, 2, 3, 4, 5, 6, 7, 9, 12, 15}, names: []string{
+			"id", "name", "category", "price", "ts", "day", "tags", "props", "location", "flag",
+		}},
+		{kind: 4, required: true},  // id: long
+		{kind: 7},                  // name: string
+		{kind: 7, required: true},  // category: string
+		{kind: 14, required: true}, // price: decimal(9, 2)
+		{kind: 18, required: true}, // ts: timestamp with local time zone
+		{kind: 15, required: true}, // day: date
+		{kind: 10, subtypes: []uint64{8}},
+		{kind: 7, required: true},
+		{kind: 11, subtypes: []uint64{10, 11}, required: true},
+		{kind: 7, required: true},
+		{kind: 3}, // int
+		{kind: 12, subtypes: []uint64{13, 14}, names: []string{"lat", "lon"}},
+		{kind: 6, required: true}, // double
+		{kind: 6, required: true},
+		{kind: 0, required: true}, // boolean
+	}, withIDs)
+}
+
+func orcTestTypeMessages(types []orcTestType, withIDs bool) [][]byte {
+	out := make([][]byte, len(types))
+	for i, typ := range types {
+		msg := pbVarint(nil, 1, typ.kind)
+		if len(typ.subtypes) > 0 {
+			var packed []byte
+			for _, sub := range typ.subtypes {
+				packed = binary.AppendUvarint(packed, sub)
+			}
+			msg = pbBytes(msg, 2, packed)
+		}
+		for _, name := range typ.names {
+			msg = pbBytes(msg, 3, []byte(name))
+		}
+		if typ.kind == 14 {
+			msg = pbVarint(pbVarint(msg, 5, 9), 6, 2)
+		}
+		if withIDs && i > 0 {
+			msg = pbBytes(msg, 7, pbBytes(pbBytes(nil, 1, []byte("iceberg.id")), 2, []byte(strconv.Itoa(i))))
+			msg = pbBytes(msg, 7, pbBytes(pbBytes(nil, 1, []byte("iceberg.required")), 2,
+				[]byte(strconv.FormatBool(typ.required))))
+		}
+		out[i] = msg
+	}
+
+	return out
+}
+
+const orcTestBlockSize = 64
+
+// the compression kinds of the postscript
+const (
+	orcTestNone = iota
+	orcTestZlib
+	orcTestSnappy
+	orcTestLzo
+	orcTestLz4
+	orcTestZstd
+)
+
+var orcTestCodecs = map[string]int{
+	"none": orcTestNone, "zlib": orcTestZlib, "snappy": orcTestSnappy,
+	"lz4": orcTestLz4, "zstd": orcTestZstd,
+}
+
+// orcTestCompress splits the data in chunks compressed with the codec,
+// storing the ones that don't shrink as is.
+func orcTestCompress(t *testing.T, codec int, data []byte) []byte {
+	if codec == orcTestNone {
+		return data
+	}
+
+	var out []byte
+	for len(data) > 0 {
+		chunk := data[:min(len(data), orcTestBlockSize)]
+		data = data[len(chunk):]
+
+		var body []byte
+		switch codec {
+		case orcTestZlib:
+			var buf bytes.Buffer
+			w, _ := flate.NewWriter(&buf, flate.BestCompression)
+			w.Write(chunk)
+			w.Close()
+			body = buf.Bytes()
+		case orcTestLzo:
+			// without an encoder every chunk is stored as is
+			body = chunk
+		default:
+			kind := map[int]compress.Compression{
+				orcTestSnappy: compress.Codecs.Snappy,
+				orcTestLz4:    compress.Codecs.Lz4Raw,
+				orcTestZstd:   compress.Codecs.Zstd,
+			}[codec]
+			c, err := compress.GetCodec(kind)
+			require.NoError(t, err)
+			body = c.Encode(make([]byte, c.CompressBound(int64(len(chunk)))), chunk)
+		}
+
+		header := len(body) << 1
+		// lz4 returns nothing for incompressible chunks
+		if len(body) == 0 || len(body) >= len(chunk) {
+			header, body = len(chunk)<<1|1, chunk
+		}
+		out = append(out, byte(header), byte(header>>8), byte(header>>16))
+		out = append(out, body...)
+	}
+
+	return out
+}
+
+// orcTestStripeData holds the streams of a stripe along with the encoding
+// of each column.
+type orcTestStripeData struct {
+	rows      int
+	streams   []orcTestStream
+	encodings [][2]uint64
+}
+
+func writeOrcTestFile(t *testing.T, withIDs bool, codec int) string {
+	rows := orcTestRows()
+
+	var stripes []orcTestStripeData
+	for _, stripeRows := range [][]orcTestRow{rows[:3], rows[3:]} {
+		streams, encodings := orcTestStripe(stripeRows)
+		stripes = append(stripes, orcTestStripeData{len(stripeRows), streams, encodings})
+	}
+
+	return writeOrcFile(t, codec, orcTestTypes(withIDs), stripes)
+}
+
+func writeOrcFile(t *testing.T, codec int, types [][]byte, stripes []orcTestStripeData) string {
+	file := []byte("ORC")
+
+	var (
+		infos   [][]byte
+		numRows int
+	)
+	for _, stripe := range stripes {
+		offset := len(file)
+		var footer []byte
+		for _, s := range stripe.streams {
+			data := orcTestCompress(t, codec, s.data)
+			file = append(file, data...)
+
+			stream := pbVarint(pbVarint(nil, 1, uint64(s.kind)), 2, uint64(s.column))
+			footer = pbBytes(footer, 1, pbVarint(stream, 3, uint64(len(data))))
+		}
+		dataLen := len(file) - offset
+
+		for _, enc := range stripe.encodings {
+			footer = pbBytes(footer, 2, pbVarint(pbVarint(nil, 1, enc[0]), 2, enc[1]))
+		}
+		// ignored by timestamps with a time zone
+		footer = pbBytes(footer, 3, []byte("America/New_York"))
+
+		footer = orcTestCompress(t, codec, footer)
+		file = append(file, footer...)
+
+		info := pbVarint(pbVarint(nil, 1, uint64(offset)), 3, uint64(dataLen))
+		info = pbVarint(pbVarint(info, 4, uint64(len(footer))), 5, uint64(stripe.rows))
+		infos = append(infos, info)
+		numRows += stripe.rows
+	}
+
+	footer := pbVarint(pbVarint(nil, 1, 3), 2, uint64(len(file)-3))
+	for _, info := range infos {
+		footer = pbBytes(footer, 3, info)
+	}
+	for _, typ := range types {
+		footer = pbBytes(footer, 4, typ)
+	}
+	footer = pbBytes(footer, 5, pbBytes(pbBytes(nil, 1, []byte("writer")), 2, []byte("test")))
+	footer = pbVarint(footer, 6, uint64(numRows))
+
+	footer = orcTestCompress(t, codec, footer)
+	file = append(file, footer...)
+
+	ps := pbVarint(pbVarint(nil, 1, uint64(len(footer))), 2, uint64(codec))
+	ps = pbVarint(pbVarint(ps, 3, orcTestBlockSize), 5, 0)
+	ps = pbBytes(ps, 8000, []byte("ORC"))
+	file = append(append(file, ps...), byte(len(ps)))
+
+	fname := filepath.Join(t.TempDir(), "data.orc")
+	require.NoError(t, os.WriteFile(fname, file, 0o644))
+
+	return fname
+}
+
+func TestOrcReadTable(t *testing.T) {
+	for name, codec := range orcTestCodecs {
+		t.Run(name, func(t *testing.T) {
+			rdr := openTestFile(t, writeOrcTestFile(t, true, codec), iceberg.OrcFile)
+			assert.Equal(t, map[string][]byte{"writer": []byte("test")}, rdr.Metadata())
+
+			tbl, err := rdr.ReadTable(context.Background())
+			require.NoError(t, err)
+			defer tbl.Release()
+
+			sc, err := table.ArrowSchemaToIceberg(tbl.Schema(), false, nil)
+			require.NoError(t, err)
+			assert.Equal(t, `table {
+	1: id: required long
+	2: name: optional string
+	3: category: required string
+	4: price: required decimal(9, 2)
+	5: ts: required timestamptz
+	6: day: required date
+	7: tags: optional list<string>
+	9: props: required map<string, int>
+	12: location: optional struct<13: lat: required double, 14: lon: required double>
+	15: flag: required boolean
+}`, sc.String())
+
+			assert.EqualValues(t, 5, tbl.NumRows())
+			assert.Equal(t, []string{"0", "1", "2", "3", "4"}, columnStrings(tbl, 0))
+			assert.Equal(t, []string{"a", array.NullValueStr, "c", "d", array.NullValueStr}, columnStrings(tbl, 1))
+			assert.Equal(t, []string{"x", "y", "x", "x", "y"}, columnStrings(tbl, 2))
+			assert.Equal(t, []string{"10.5", "10.5", "-3.25", "0", "99.99"}, columnStrings(tbl, 3))
+			assert.Equal(t, []string{
+				"2024-05-01 12:00:00Z", "1969-12-31 23:59:58.5Z", "2024-05-01 13:00:00Z",
+				"2024-05-01 14:00:00Z", "2024-05-01 15:00:00Z",
+			}, columnStrings(tbl, 4))
+			assert.Equal(t, []string{"2024-05-01", "2024-05-02", "2024-05-03", "2024-05-04", "2024-05-05"},
+				columnStrings(tbl, 5))
+			assert.Equal(t, []string{`["x","y"]`, array.NullValueStr, `[]`, `["z"]`, `["x"]`}, columnStrings(tbl, 6))
+			assert.Equal(t, []string{
+				`[{"key":"k","value":0}]`, `[{"key":"a","value":1},{"key":"b","value":null}]`, `[]`,
+				`[{"key":"k","value":3}]`, `[{"key":"k","value":4}]`,
+			}, columnStrings(tbl, 7))
+			assert.Equal(t, []string{
+				`{"lat":1.5,"lon":-2.5}`, `{"lat":1.5,"lon":-2.5}`, `{"lat":1.5,"lon":-2.5}`,
+				array.NullValueStr, `{"lat":1.5,"lon":-2.5}`,
+			}, columnStrings(tbl, 8))
+			assert.Equal(t, []string{"true", "false", "true", "false", "true"}, columnStrings(tbl, 9))
+		})
+	}
+}
+
+func TestOrcProjection(t *testing.T) {
+	rdr := openTestFile(t, writeOrcTestFile(t, true, orcTestZlib), iceberg.OrcFile)
+
+	projected, cols, err := rdr.PrunedSchema(map[int]struct{}{2: {}, 11: {}, 14: {}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 10, 11, 14}, cols)
+
+	sc, err := table.ArrowSchemaToIceberg(projected, false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `table {
+	2: name: optional string
+	9: props: required map<string, int>
+	12: location: optional struct<14: lon: required double>
+}`, sc.String())
+
+	recs, err := rdr.GetRecords(context.Background(), cols, nil)
+	require.NoError(t, err)
+	defer recs.Release()
+
+	assert.True(t, recs.Schema().Equal(projected))
+
+	var names, props, locations []string
+	for recs.Next() {
+		rec := recs.Record()
+		assert.True(t, rec.Schema().Equal(projected))
+		for i := range int(rec.NumRows()) {
+			names = append(names, rec.Column(0).ValueStr(i))
+			props = append(props, rec.Column(1).ValueStr(i))
+			locations = append(locations, rec.Column(2).ValueStr(i))
+		}
+	}
+	require.NoError(t, recs.Err())
+	assert.Equal(t, []string{"a", array.NullValueStr, "c", "d", array.NullValueStr}, names)
+	assert.Equal(t, []string{
+		`[{"key":"k","value":0}]`, `[{"key":"a","value":1},{"key":"b","value":null}]`, `[]`,
+		`[{"key":"k","value":3}]`, `[{"key":"k","value":4}]`,
+	}, props)
+	assert.Equal(t, []string{
+		`{"lon":-2.5}`, `{"lon":-2.5}`, `{"lon":-2.5}`, array.NullValueStr, `{"lon":-2.5}`,
+	}, locations)
+}
+
+func TestOrcNameMapping(t *testing.T) {
+	rdr := openTestFile(t, writeOrcTestFile(t, false, orcTestNone), iceberg.OrcFile)
+
+	_, _, err := rdr.PrunedSchema(map[int]struct{}{1: {}}, nil)
+	assert.ErrorIs(t, err, iceberg.ErrInvalidSchema)
+
+	id := func(i int) *int { return &i }
+	mapping := iceberg.NameMapping{
+		{FieldID: id(101), Names: []string{"id"}},
+		{FieldID: id(102), Names: []string{"name"}},
+		{FieldID: id(103), Names: []string{"category"}},
+		{FieldID: id(104), Names: []string{"price"}},
+		{FieldID: id(105), Names: []string{"ts"}},
+		{FieldID: id(106), Names: []string{"day"}},
+		{FieldID: id(107), Names: []string{"tags"}, Fields: []iceberg.MappedField{
+			{FieldID: id(108), Names: []string{"element"}},
+		}},
+		{FieldID: id(109), Names: []string{"props"}, Fields: []iceberg.MappedField{
+			{FieldID: id(110), Names: []string{"key"}},
+			{FieldID: id(111), Names: []string{"value"}},
+		}},
+		{FieldID: id(112), Names: []string{"location"}, Fields: []iceberg.MappedField{
+			{FieldID: id(113), Names: []string{"lat"}},
+			{FieldID: id(114), Names: []string{"lon"}},
+		}},
+		{FieldID: id(115), Names: []string{"flag"}},
+	}
+
+	projected, cols, err := rdr.PrunedSchema(map[int]struct{}{101: {}, 108: {}, 113: {}}, mapping)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 8, 13}, cols)
+
+	sc, err := table.ArrowSchemaToIceberg(projected, false, mapping)
+	require.NoError(t, err)
+	assert.Equal(t, `table {
+	101: id: optional long
+	107: tags: optional list<string>
+	112: location: optional struct<113: lat: optional double>
+}`, sc.String())
+
+	recs, err := rdr.GetRecords(context.Background(), cols, nil)
+	require.NoError(t, err)
+	defer recs.Release()
+
+	var ids, tags []string
+	for recs.Next() {
+		for i := range int(recs.Record().NumRows()) {
+			ids = append(ids, recs.Record().Column(0).ValueStr(i))
+			tags = append(tags, recs.Record().Column(1).ValueStr(i))
+		}
+	}
+	require.NoError(t, recs.Err())
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, ids)
+	assert.Equal(t, []string{`["x","y"]`, array.NullValueStr, `[]`, `["z"]`, `["x"]`}, tags)
+}
+
+// orcSpecStripe returns a stripe of 20 rows whose streams use the run
+// length encodings of the examples of the specification, the way writers
+// encode them, rather than the simplest ones.
+func orcSpecStripe() (orcTestStripeData, []orcTestType) {
+	// the ids are the patched base example
+	ids := []byte{
+		0x8e, 0x13, 0x2b, 0x21, 0x07, 0xd0, 0x1e, 0x00, 0x14, 0x70, 0x28, 0x32, 0x3c, 0x46,
+		0x50, 0x5a, 0x64, 0x6e, 0x78, 0x82, 0x8c, 0x96, 0xa0, 0xaa, 0xb4, 0xbe, 0xfc, 0xe8,
+	}
+
+	// the lengths of the direct v2 strings are the delta example twice
+	nameLens := slices.Repeat([]byte{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46}, 2)
+	var names []byte
+	for i, n := range slices.Repeat([]int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}, 2) {
+		names = append(names, strings.Repeat(string(rune('a'+i)), n)...)
+	}
+
+	// the unscaled decimals are zigzag varints, with the scales as two
+	// short repeats of 10 values
+	var decimals []byte
+	for i := range 20 {
+		decimals = binary.AppendVarint(decimals, int64(i-10)*1001)
+	}
+	scales := []byte{0x07, 0x04, 0x07, 0x04}
+
+	// the seconds are a fixed delta of an hour and the nanoseconds two
+	// short repeats of zero
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Unix() -
+		time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	secs := binary.AppendVarint(binary.AppendVarint([]byte{0xc0, 0x13}, base), 3600)
+	nanos := []byte{0x07, 0x00, 0x07, 0x00}
+
+	const directV2 = 2
+	stripe := orcTestStripeData{
+		rows: 20,
+		streams: []orcTestStream{
+			{1, orcTestData, ids},
+			{2, orcTestData, names},
+			{2, orcTestLength, nameLens},
+			{3, orcTestData, decimals},
+			{3, orcTestSecondary, scales},
+			{4, orcTestData, secs},
+			{4, orcTestSecondary, nanos},
+		},
+		encodings: slices.Repeat([][2]uint64{{directV2, 0}}, 5),
+	}
+
+	return stripe, []orcTestType{
+		{kind: 12, subtypes: []uint64{1, 2, 3, 4}, names: []string{"id", "name", "price", "ts"}},
+		{kind: 4, required: true},  // id: long
+		{kind: 7, required: true},  // name: string
+		{kind: 14, required: true}, // price: decimal(9, 2)
+		{kind: 18, required: true}, // ts: timestamp with local time zone
+	}
+}
+
+func TestOrcSpecEncodings(t *testing.T) {
+	stripe, types := orcSpecStripe()
+
+	var ids, names, prices, timestamps []string
+	for i := range 20 {
+		names = append(names, strings.Repeat(string(rune('a'+i)),
+			[]int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}[i%10]))
+		timestamps = append(timestamps, time.Date(2024, 5, 1, 12+i, 0, 0, 0, time.UTC).Format("2006-01-02 15:04:05Z"))
+	}
+	for _, id := range []int{
+		2030, 2000, 2020, 1000000, 2040, 2050, 2060, 2070, 2080, 2090,
+		2100, 2110, 2120, 2130, 2140, 2150, 2160, 2170, 2180, 2190,
+	} {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	prices = []string{
+		"-100.1", "-90.09", "-80.08", "-70.07", "-60.06", "-50.05", "-40.04", "-30.03", "-20.02", "-10.01",
+		"0", "10.01", "20.02", "30.03", "40.04", "50.05", "60.06", "70.07", "80.08", "90.09",
+	}
+
+	for name, codec := range orcTestCodecs {
+		t.Run(name, func(t *testing.T) {
+			fname := writeOrcFile(t, codec, orcTestTypeMessages(types, true), []orcTestStripeData{stripe})
+			rdr := openTestFile(t, fname, iceberg.OrcFile)
+
+			tbl, err := rdr.ReadTable(context.Background())
+			require.NoError(t, err)
+			defer tbl.Release()
+
+			assert.EqualValues(t, 20, tbl.NumRows())
+			assert.Equal(t, ids, columnStrings(tbl, 0))
+			assert.Equal(t, names, columnStrings(tbl, 1))
+			assert.Equal(t, prices, columnStrings(tbl, 2))
+			assert.Equal(t, timestamps, columnStrings(tbl, 3))
+		})
+	}
+}
+
+func TestOrcLzoNotImplemented(t *testing.T) {
+	stripe, types := orcSpecStripe()
+	fname := writeOrcFile(t, orcTestLzo, orcTestTypeMessages(types, true), []orcTestStripeData{stripe})
+
+	bldr, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentData,
+		fname, iceberg.OrcFile, nil, 20, 100)
+	require.NoError(t, err)
+
+	src, err := internal.GetFile(context.Background(), iceio.LocalFS{}, bldr.Build(), false)
+	require.NoError(t, err)
+
+	_, err = src.GetReader(context.Background())
+	assert.ErrorIs(t, err, iceberg.ErrNotImplemented)
+	assert.ErrorContains(t, err, "lzo")
+}
@@ -21,10 +21,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"unsafe"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -61,6 +63,8 @@ const (
 	ParquetBloomFilterMaxBytesKey            = "write.parquet.bloom-filter-max-bytes"
 	ParquetBloomFilterMaxBytesDefault        = 1024 * 1024
 	ParquetBloomFilterColumnEnabledKeyPrefix = "write.parquet.bloom-filter-enabled.column"
+	ParquetPageIndexEnabledKey               = "write.parquet.page-index-enabled"
+	ParquetPageIndexEnabledDefault           = false
 )
 
 type parquetFormat struct{}
@@ -177,6 +181,8 @@ func (parquetFormat) PrimitiveTypeToPhysicalType(typ iceberg.PrimitiveType) stri
 		return "INT64"
 	case iceberg.TimestampTzType:
 		return "INT64"
+	case iceberg.TimestampNsType, iceberg.TimestampTzNsType:
+		return "INT64"
 	case iceberg.StringType:
 		return "BYTE_ARRAY"
 	case iceberg.UUIDType:
@@ -204,6 +210,21 @@ func (parquetFormat) GetWriteProperties(props iceberg.Properties) any {
 			ParquetPageRowLimitDefault))),
 		parquet.WithDictionaryPageSizeLimit(int64(props.GetInt(ParquetDictSizeBytesKey,
 			ParquetDictSizeBytesDefault))),
+		parquet.WithPageIndexEnabled(props.GetBool(ParquetPageIndexEnabledKey,
+			ParquetPageIndexEnabledDefault)),
+		parquet.WithMaxBloomFilterBytes(int64(props.GetInt(ParquetBloomFilterMaxBytesKey,
+			ParquetBloomFilterMaxBytesDefault))),
+	}
+
+	for k, v := range props {
+		col, ok := strings.CutPrefix(k, ParquetBloomFilterColumnEnabledKeyPrefix+".")
+		if !ok {
+			continue
+		}
+
+		if enabled, err := strconv.ParseBool(v); err == nil && enabled {
+			writerProps = append(writerProps, parquet.WithBloomFilterEnabledFor(col, true))
+		}
 	}
 
 	compression := props.Get(ParquetCompressionKey, ParquetCompressionDefault)
@@ -273,8 +294,19 @@ func (p parquetFormat) WriteDataFile(ctx context.Context, fs iceio.WriteFileIO,
 		return nil, err
 	}
 
-	return p.DataFileStatsFromMeta(filemeta, info.StatsCols, colMapping).
-		ToDataFile(info.FileSchema, info.Spec, info.FileName, iceberg.ParquetFile, cntWriter.Count), nil
+	stats := p.DataFileStatsFromMeta(filemeta, info.StatsCols, colMapping)
+	stats.SortOrderID = info.SortOrderID
+	if info.Content != iceberg.EntryContentData {
+		return stats.ToContentFile(info.Content, info.Spec, info.FileName, iceberg.ParquetFile,
+			cntWriter.Count, info.PartitionValues), nil
+	}
+
+	if info.PartitionValues != nil {
+		return stats.ToPartitionedDataFile(info.Spec, info.FileName, iceberg.ParquetFile,
+			cntWriter.Count, info.PartitionValues), nil
+	}
+
+	return stats.ToDataFile(info.FileSchema, info.Spec, info.FileName, iceberg.ParquetFile, cntWriter.Count), nil
 }
 
 type decAsIntAgg[T int32 | int64] struct {
@@ -399,6 +431,22 @@ func (w wrappedDecStats) Max() iceberg.Decimal {
 	return iceberg.Decimal{Val: dec, Scale: w.scale}
 }
 
+// RowGroupOffset returns the file offset at which the row group starts,
+// which is what is recorded in the split offsets of a data file.
+func RowGroupOffset(rowGroup *metadata.RowGroupMetaData) (int64, error) {
+	colChunk, err := rowGroup.ColumnChunk(0)
+	if err != nil {
+		return 0, err
+	}
+
+	dataOffset, dictOffset := colChunk.DataPageOffset(), colChunk.DictionaryPageOffset()
+	if colChunk.HasDictionaryPage() && dictOffset < dataOffset {
+		return dictOffset, nil
+	}
+
+	return dataOffset, nil
+}
+
 func (p parquetFormat) DataFileStatsFromMeta(meta Metadata, statsCols map[int]StatisticsCollector, colMapping map[string]int) *DataFileStatistics {
 	pqmeta := meta.(*metadata.FileMetaData)
 	var (
@@ -414,20 +462,14 @@ func (p parquetFormat) DataFileStatsFromMeta(meta Metadata, statsCols map[int]St
 	for rg := range pqmeta.NumRowGroups() {
 		// reference: https://github.com/apache/iceberg-python/blob/main/pyiceberg/io/pyarrow.py#L2285
 		rowGroup := pqmeta.RowGroup(rg)
-		colChunk, err := rowGroup.ColumnChunk(0)
+		offset, err := RowGroupOffset(rowGroup)
 		if err != nil {
 			panic(err)
 		}
-
-		dataOffset, dictOffset := colChunk.DataPageOffset(), colChunk.DictionaryPageOffset()
-		if colChunk.HasDictionaryPage() && dictOffset < dataOffset {
-			splitOffsets = append(splitOffsets, dictOffset)
-		} else {
-			splitOffsets = append(splitOffsets, dataOffset)
-		}
+		splitOffsets = append(splitOffsets, offset)
 
 		for pos := range rowGroup.NumColumns() {
-			colChunk, err = rowGroup.ColumnChunk(pos)
+			colChunk, err := rowGroup.ColumnChunk(pos)
 			if err != nil {
 				panic(err)
 			}
@@ -544,36 +586,201 @@ func (w wrapPqArrowReader) PrunedSchema(projectedIDs map[int]struct{}, mapping i
 }
 
 func (w wrapPqArrowReader) GetRecords(ctx context.Context, cols []int, tester any) (array.RecordReader, error) {
+	var selectRows ParquetRowSelector
+	switch tester := tester.(type) {
+	case nil:
+		return w.GetRecordReader(ctx, cols, nil)
+	case ParquetRowSelector:
+		selectRows = tester
+	case func(*metadata.RowGroupMetaData, []int) (bool, error):
+		selectRows = func(rg ParquetRowGroup, cols []int) (bool, []RowRange, error) {
+			use, err := tester(rg.RowGroupMetaData, cols)
+
+			return use, nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: invalid tester function", iceberg.ErrInvalidArgument)
+	}
+
 	var (
-		testRg func(*metadata.RowGroupMetaData, []int) (bool, error)
-		ok     bool
+		pqRdr    = w.ParquetReader()
+		rgList   = make([]int, 0)
+		ranges   []RowRange
+		partial  bool
+		firstRow int64
+		maxRange int64
 	)
 
-	if tester != nil {
-		testRg, ok = tester.(func(*metadata.RowGroupMetaData, []int) (bool, error))
-		if !ok {
-			return nil, fmt.Errorf("%w: invalid tester function", iceberg.ErrInvalidArgument)
+	for i := range pqRdr.NumRowGroups() {
+		rg := ParquetRowGroup{RowGroupMetaData: pqRdr.MetaData().RowGroup(i), rdr: pqRdr, ordinal: i}
+		use, rows, err := selectRows(rg, cols)
+		if err != nil {
+			return nil, err
+		}
+
+		if !use || (rows != nil && len(rows) == 0) {
+			continue
+		}
+
+		rgList = append(rgList, i)
+		if rows == nil {
+			rows = []RowRange{{Start: 0, End: rg.NumRows()}}
+		} else {
+			partial = true
+		}
+
+		// rows are counted across the selected row groups when seeking
+		for _, r := range rows {
+			r.Start, r.End = r.Start+firstRow, r.End+firstRow
+			if n := len(ranges); n > 0 && ranges[n-1].End == r.Start {
+				ranges[n-1].End = r.End
+			} else {
+				ranges = append(ranges, r)
+			}
+			maxRange = max(maxRange, ranges[len(ranges)-1].Len())
 		}
+		firstRow += rg.NumRows()
 	}
 
-	var rgList []int
-	if testRg != nil {
-		rgList = make([]int, 0)
-		fileMeta, numRg := w.ParquetReader().MetaData(), w.ParquetReader().NumRowGroups()
-		for rg := 0; rg < numRg; rg++ {
-			rgMeta := fileMeta.RowGroup(rg)
-			use, err := testRg(rgMeta, cols)
-			if err != nil {
-				return nil, err
+	if !partial {
+		return w.GetRecordReader(ctx, cols, rgList)
+	}
+
+	// avoid decoding pages that would be skipped by reading batches
+	// no larger than the ranges to read
+	fr := *w.FileReader
+	if fr.Props.BatchSize <= 0 || fr.Props.BatchSize > maxRange {
+		fr.Props.BatchSize = maxRange
+	}
+
+	rdr, err := fr.GetRecordReader(ctx, cols, rgList)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &rowRangeReader{rdr: rdr, ranges: ranges}
+	out.refCount.Add(1)
+
+	return out, nil
+}
+
+// ParquetRowGroup provides access to the metadata, bloom filters and page
+// indexes of a row group when selecting the rows of a parquet file to read.
+type ParquetRowGroup struct {
+	*metadata.RowGroupMetaData
+
+	rdr     *file.Reader
+	ordinal int
+}
+
+// BloomFilter returns the bloom filter of the column at the given index, or
+// nil if the column chunk doesn't have one.
+func (rg ParquetRowGroup) BloomFilter(col int) (metadata.BloomFilter, error) {
+	bf, err := rg.rdr.GetBloomFilterReader().RowGroup(rg.ordinal)
+	if err != nil {
+		return nil, err
+	}
+
+	return bf.GetColumnBloomFilter(col)
+}
+
+// PageIndex returns the reader for the column and offset indexes of the
+// row group, or nil if the row group doesn't have a page index.
+func (rg ParquetRowGroup) PageIndex() (*metadata.RowGroupPageIndexReader, error) {
+	return rg.rdr.GetPageIndexReader().RowGroup(rg.ordinal)
+}
+
+// RowRange is the range [Start, End) of row positions within a row group.
+type RowRange struct {
+	Start, End int64
+}
+
+func (r RowRange) Len() int64 { return r.End - r.Start }
+
+// ParquetRowSelector can be passed as the tester of [FileReader.GetRecords]
+// for parquet files to select the rows to read from each row group. It
+// returns whether the row group should be read and, if only some of its rows
+// can be needed, the sorted and non-overlapping ranges of those rows. A nil
+// slice of ranges reads the whole row group.
+//
+// A func(*metadata.RowGroupMetaData, []int) (bool, error) is also accepted as
+// tester to select entire row groups.
+type ParquetRowSelector func(ParquetRowGroup, []int) (bool, []RowRange, error)
+
+// rowRangeReader only returns the rows of the underlying reader within the
+// given ranges, seeking over the rows between them so that the pages holding
+// only skipped rows are never read.
+type rowRangeReader struct {
+	rdr      pqarrow.RecordReader
+	ranges   []RowRange
+	refCount atomic.Int64
+
+	// the last batch read from rdr and the positions of its first row and
+	// of the row following it
+	batch           arrow.Record
+	batchStart, pos int64
+
+	cur arrow.Record
+	err error
+}
+
+func (r *rowRangeReader) Retain() { r.refCount.Add(1) }
+
+func (r *rowRangeReader) Release() {
+	if r.refCount.Add(-1) == 0 {
+		if r.cur != nil {
+			r.cur.Release()
+			r.cur = nil
+		}
+		r.rdr.Release()
+	}
+}
+
+func (r *rowRangeReader) Schema() *arrow.Schema { return r.rdr.Schema() }
+func (r *rowRangeReader) Record() arrow.Record  { return r.cur }
+func (r *rowRangeReader) Err() error            { return r.err }
+
+func (r *rowRangeReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+
+	if r.err != nil || len(r.ranges) == 0 {
+		return false
+	}
+
+	rng := &r.ranges[0]
+	if r.batch == nil || rng.Start >= r.pos {
+		if rng.Start != r.pos {
+			if r.err = r.rdr.SeekToRow(rng.Start); r.err != nil {
+				return false
 			}
+			r.pos = rng.Start
+		}
 
-			if use {
-				rgList = append(rgList, rg)
+		if !r.rdr.Next() {
+			if r.err = r.rdr.Err(); errors.Is(r.err, io.EOF) {
+				r.err = nil
 			}
+
+			return false
 		}
+
+		r.batch, r.batchStart = r.rdr.Record(), r.pos
+		r.pos += r.batch.NumRows()
 	}
 
-	return w.GetRecordReader(ctx, cols, rgList)
+	start, end := max(rng.Start, r.batchStart), min(rng.End, r.pos)
+	if end == rng.End {
+		r.ranges = r.ranges[1:]
+	} else {
+		rng.Start = end
+	}
+
+	r.cur = r.batch.NewSlice(start-r.batchStart, end-r.batchStart)
+
+	return true
 }
 
 func (pfs *ParquetFileSource) GetReader(ctx context.Context) (FileReader, error) {
@@ -323,3 +323,19 @@ func TestMetricsPrimitiveTypes(t *testing.T) {
 		Scale: 2,
 	})
 }
+
+func TestParquetWriteProperties(t *testing.T) {
+	format := internal.GetFileFormat(iceberg.ParquetFile)
+
+	props := parquet.NewWriterProperties(format.GetWriteProperties(iceberg.Properties{}).([]parquet.WriterProperty)...)
+	assert.False(t, props.PageIndexEnabled())
+	assert.False(t, props.BloomFilterEnabledFor("id"))
+
+	props = parquet.NewWriterProperties(format.GetWriteProperties(iceberg.Properties{
+		internal.ParquetPageIndexEnabledKey:                       "true",
+		internal.ParquetBloomFilterColumnEnabledKeyPrefix + ".id": "true",
+	}).([]parquet.WriterProperty)...)
+	assert.True(t, props.PageIndexEnabled())
+	assert.True(t, props.BloomFilterEnabledFor("id"))
+	assert.False(t, props.BloomFilterEnabledFor("data"))
+}
@@ -203,6 +203,7 @@ type DataFileStatistics struct {
 	NanValueCounts  map[int]int64
 	ColAggs         map[int]StatsAgg
 	SplitOffsets    []int64
+	SortOrderID     *int
 }
 
 func (d *DataFileStatistics) PartitionValue(field iceberg.PartitionField, sc *iceberg.Schema) any {
@@ -246,7 +247,20 @@ func (d *DataFileStatistics) ToDataFile(schema *iceberg.Schema, spec iceberg.Par
 		}
 	}
 
-	bldr, err := iceberg.NewDataFileBuilder(spec, iceberg.EntryContentData,
+	return d.ToPartitionedDataFile(spec, path, format, filesize, fieldIDToPartitionData)
+}
+
+// ToPartitionedDataFile is like ToDataFile, but uses the provided partition
+// values keyed by partition field id rather than inferring them from the
+// column statistics.
+func (d *DataFileStatistics) ToPartitionedDataFile(spec iceberg.PartitionSpec, path string, format iceberg.FileFormat, filesize int64, fieldIDToPartitionData map[int]any) iceberg.DataFile {
+	return d.ToContentFile(iceberg.EntryContentData, spec, path, format, filesize, fieldIDToPartitionData)
+}
+
+// ToContentFile is like ToPartitionedDataFile, but allows specifying the
+// content of the file so that it can also be used for delete files.
+func (d *DataFileStatistics) ToContentFile(content iceberg.ManifestEntryContent, spec iceberg.PartitionSpec, path string, format iceberg.FileFormat, filesize int64, fieldIDToPartitionData map[int]any) iceberg.DataFile {
+	bldr, err := iceberg.NewDataFileBuilder(spec, content,
 		path, format, fieldIDToPartitionData, d.RecordCount, filesize)
 	if err != nil {
 		panic(err)
@@ -278,6 +292,9 @@ func (d *DataFileStatistics) ToDataFile(schema *iceberg.Schema, spec iceberg.Par
 	bldr.NullValueCounts(d.NullValueCounts)
 	bldr.NaNValueCounts(d.NanValueCounts)
 	bldr.SplitOffsets(d.SplitOffsets)
+	if d.SortOrderID != nil {
+		bldr.SortOrderID(*d.SortOrderID)
+	}
 
 	return bldr.Build()
 }
@@ -0,0 +1,438 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/apache/iceberg-go"
+)
+
+const (
+	// SourceSnapshotIDKey is the snapshot summary property recording the
+	// snapshot a cherry-picked snapshot was created from.
+	SourceSnapshotIDKey = "source-snapshot-id"
+	// WapIDKey is the snapshot property identifying the write of a
+	// snapshot staged with write-audit-publish, see WriteWapEnabledKey.
+	WapIDKey = "wap.id"
+	// PublishedWapIDKey is the snapshot summary property recording the
+	// write-audit-publish id of the staged snapshot which was published.
+	PublishedWapIDKey = "published-wap-id"
+)
+
+// ManageSnapshots implements a builder for managing the snapshots and refs
+// of a table: rolling back the table, setting its current snapshot,
+// cherry-picking snapshots and creating, replacing, fast-forwarding and
+// removing branches and tags.
+//
+// The operations are run in order when calling Commit, each one seeing the
+// changes of the ones before it, and their updates are then staged at once
+// on the transaction, requiring the refs they change to be unchanged when
+// the transaction is committed. A cherry-pick creating a new snapshot is
+// staged on its own after the operations before it. If an operation fails,
+// Commit returns its error and nothing is staged since the previous
+// cherry-pick.
+type ManageSnapshots struct {
+	txn *Transaction
+	ops []manageSnapshotsOp
+}
+
+type manageSnapshotsOp func(meta Metadata, changes *refChanges) error
+
+// refChanges collects the updates and requirements of the operations. The
+// updates are applied to a copy of the metadata of the transaction so that
+// the operations see the changes of the ones before them.
+type refChanges struct {
+	meta    *MetadataBuilder
+	updates []Update
+	reqs    []Requirement
+	// snapshot stages the snapshot created by the last operation, once
+	// the changes of the ones before it are staged.
+	snapshot func() error
+}
+
+func (c *refChanges) add(update Update, req Requirement) error {
+	if err := update.Apply(c.meta); err != nil {
+		return err
+	}
+	c.updates = append(c.updates, update)
+
+	// the refs are required to be as they were before the first change
+	// to them
+	key := requirementKey(req)
+	if !slices.ContainsFunc(c.reqs, func(r Requirement) bool { return requirementKey(r) == key }) {
+		c.reqs = append(c.reqs, req)
+	}
+
+	return nil
+}
+
+// ManageSnapshots returns a builder for managing the snapshots and refs of
+// the table in the transaction.
+func (t *Transaction) ManageSnapshots() *ManageSnapshots {
+	return &ManageSnapshots{txn: t}
+}
+
+func (ms *ManageSnapshots) add(op manageSnapshotsOp) *ManageSnapshots {
+	ms.ops = append(ms.ops, op)
+
+	return ms
+}
+
+// Commit stages the operations on the transaction.
+func (ms *ManageSnapshots) Commit() error {
+	for ops := ms.ops; len(ops) > 0; {
+		base, err := ms.txn.meta.Build()
+		if err != nil {
+			return err
+		}
+
+		scratch, err := MetadataBuilderFromBase(base)
+		if err != nil {
+			return err
+		}
+
+		changes := &refChanges{meta: scratch}
+		for len(ops) > 0 && changes.snapshot == nil {
+			meta, err := scratch.Build()
+			if err != nil {
+				return err
+			}
+
+			if err := ops[0](meta, changes); err != nil {
+				return err
+			}
+			ops = ops[1:]
+		}
+
+		if len(changes.updates) > 0 {
+			if err := ms.txn.apply(changes.updates, changes.reqs); err != nil {
+				return err
+			}
+		}
+
+		if changes.snapshot != nil {
+			if err := changes.snapshot(); err != nil {
+				return err
+			}
+		}
+	}
+	ms.ops = nil
+
+	return nil
+}
+
+func lookupRef(meta Metadata, name string) (SnapshotRef, bool) {
+	for n, ref := range meta.Refs() {
+		if n == name {
+			return ref, true
+		}
+	}
+
+	return SnapshotRef{}, false
+}
+
+func lookupSnapshot(meta Metadata, id int64) (*Snapshot, error) {
+	snap := meta.SnapshotByID(id)
+	if snap == nil {
+		return nil, fmt.Errorf("%w: snapshot not found: %d", iceberg.ErrInvalidArgument, id)
+	}
+
+	return snap, nil
+}
+
+func isAncestorOf(meta Metadata, snap *Snapshot, ancestorID int64) bool {
+	for s := range ancestorsOf(meta, snap) {
+		if s.SnapshotID == ancestorID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setRef sets the ref to the snapshot, with the retention of the given
+// ref, asserting the ref still points to expected.
+func (c *refChanges) setRef(name string, snapshotID int64, retention SnapshotRef, expected *int64) error {
+	return c.add(newSetSnapshotRefUpdateFrom(name, snapshotID, retention),
+		AssertRefSnapshotID(name, expected))
+}
+
+// setBranch moves the existing branch to the snapshot, keeping its
+// retention settings. The main branch is created if the table has no
+// current snapshot.
+func (c *refChanges) setBranch(meta Metadata, name string, snapshotID int64) error {
+	ref, ok := lookupRef(meta, name)
+	switch {
+	case !ok && name == MainBranch:
+		return c.setRef(name, snapshotID, SnapshotRef{SnapshotRefType: BranchRef}, nil)
+	case !ok:
+		return fmt.Errorf("%w: branch %s does not exist", iceberg.ErrInvalidArgument, name)
+	case ref.SnapshotRefType != BranchRef:
+		return fmt.Errorf("%w: ref %s is a %s, not a branch", ErrInvalidOperation, name, ref.SnapshotRefType)
+	case ref.SnapshotID == snapshotID:
+		return nil
+	}
+
+	return c.setRef(name, snapshotID, ref, &ref.SnapshotID)
+}
+
+// RollbackTo sets the current snapshot of the table to the given snapshot,
+// which must be an ancestor of the current snapshot.
+func (ms *ManageSnapshots) RollbackTo(snapshotID int64) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		if _, err := lookupSnapshot(meta, snapshotID); err != nil {
+			return err
+		}
+
+		if !isAncestorOf(meta, meta.CurrentSnapshot(), snapshotID) {
+			return fmt.Errorf("%w: cannot roll back to snapshot %d, it is not an ancestor of the current snapshot",
+				ErrInvalidOperation, snapshotID)
+		}
+
+		return changes.setBranch(meta, MainBranch, snapshotID)
+	})
+}
+
+// RollbackToTime sets the current snapshot of the table to the latest
+// ancestor of the current snapshot older than the given time, in
+// milliseconds since the epoch.
+func (ms *ManageSnapshots) RollbackToTime(timestampMs int64) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		for snap := range ancestorsOf(meta, meta.CurrentSnapshot()) {
+			if snap.TimestampMs < timestampMs {
+				return changes.setBranch(meta, MainBranch, snap.SnapshotID)
+			}
+		}
+
+		return fmt.Errorf("%w: cannot roll back, no ancestor of the current snapshot is older than %d",
+			ErrInvalidOperation, timestampMs)
+	})
+}
+
+// SetCurrentSnapshot sets the current snapshot of the table to any of its
+// snapshots.
+func (ms *ManageSnapshots) SetCurrentSnapshot(snapshotID int64) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		if _, err := lookupSnapshot(meta, snapshotID); err != nil {
+			return err
+		}
+
+		return changes.setBranch(meta, MainBranch, snapshotID)
+	})
+}
+
+// createRef creates a new ref to the snapshot with the retention settings
+// of the options, see WithMaxRefAgeMs, WithMaxSnapshotAgeMs and
+// WithMinSnapshotsToKeep.
+func (c *refChanges) createRef(meta Metadata, name string, snapshotID int64, refType RefType, opts []setSnapshotRefOption) error {
+	if _, ok := lookupRef(meta, name); ok {
+		return fmt.Errorf("%w: ref %s already exists", ErrInvalidOperation, name)
+	}
+
+	if _, err := lookupSnapshot(meta, snapshotID); err != nil {
+		return err
+	}
+
+	ref := SnapshotRef{SnapshotID: snapshotID, SnapshotRefType: refType}
+	for _, opt := range opts {
+		if err := opt(&ref); err != nil {
+			return fmt.Errorf("invalid snapshot ref option: %w", err)
+		}
+	}
+
+	if refType == TagRef && (ref.MinSnapshotsToKeep != nil || ref.MaxSnapshotAgeMs != nil) {
+		return fmt.Errorf("%w: tags only support the max ref age", iceberg.ErrInvalidArgument)
+	}
+
+	return c.setRef(name, snapshotID, ref, nil)
+}
+
+// CreateBranch creates a branch of the given snapshot, the options set the
+// retention of the branch.
+func (ms *ManageSnapshots) CreateBranch(name string, snapshotID int64, opts ...setSnapshotRefOption) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		return changes.createRef(meta, name, snapshotID, BranchRef, opts)
+	})
+}
+
+// CreateTag tags the given snapshot, WithMaxRefAgeMs sets how long the
+// tag is kept.
+func (ms *ManageSnapshots) CreateTag(name string, snapshotID int64, opts ...setSnapshotRefOption) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		return changes.createRef(meta, name, snapshotID, TagRef, opts)
+	})
+}
+
+// ReplaceBranch sets the existing branch to the given snapshot, keeping its
+// retention settings.
+func (ms *ManageSnapshots) ReplaceBranch(name string, snapshotID int64) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		if _, err := lookupSnapshot(meta, snapshotID); err != nil {
+			return err
+		}
+
+		return changes.setBranch(meta, name, snapshotID)
+	})
+}
+
+// FastForward sets the branch to the snapshot of the target ref, the
+// snapshot of the branch must be an ancestor of it.
+func (ms *ManageSnapshots) FastForward(name, target string) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		targetRef, ok := lookupRef(meta, target)
+		if !ok {
+			return fmt.Errorf("%w: ref %s does not exist", iceberg.ErrInvalidArgument, target)
+		}
+
+		ref, ok := lookupRef(meta, name)
+		if ok {
+			to, err := lookupSnapshot(meta, targetRef.SnapshotID)
+			if err != nil {
+				return err
+			}
+
+			if !isAncestorOf(meta, to, ref.SnapshotID) {
+				return fmt.Errorf("%w: cannot fast-forward %s to %s, snapshot %d is not an ancestor of %d",
+					ErrInvalidOperation, name, target, ref.SnapshotID, to.SnapshotID)
+			}
+		}
+
+		return changes.setBranch(meta, name, targetRef.SnapshotID)
+	})
+}
+
+// removeRef removes the ref, which must be of the given type.
+func (c *refChanges) removeRef(meta Metadata, name string, refType RefType) error {
+	ref, ok := lookupRef(meta, name)
+	switch {
+	case !ok:
+		return fmt.Errorf("%w: %s %s does not exist", iceberg.ErrInvalidArgument, refType, name)
+	case ref.SnapshotRefType != refType:
+		return fmt.Errorf("%w: ref %s is a %s, not a %s", ErrInvalidOperation, name, ref.SnapshotRefType, refType)
+	case name == MainBranch:
+		return fmt.Errorf("%w: cannot remove the main branch", ErrInvalidOperation)
+	}
+
+	return c.add(NewRemoveSnapshotRefUpdate(name), AssertRefSnapshotID(name, &ref.SnapshotID))
+}
+
+// RemoveBranch removes the branch, its snapshots are kept until they
+// expire.
+func (ms *ManageSnapshots) RemoveBranch(name string) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		return changes.removeRef(meta, name, BranchRef)
+	})
+}
+
+// RemoveTag removes the tag, its snapshot is kept until it expires.
+func (ms *ManageSnapshots) RemoveTag(name string) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		return changes.removeRef(meta, name, TagRef)
+	})
+}
+
+// CherryPick applies the changes of an append snapshot, for instance one
+// staged with write-audit-publish or committed to another branch, to the
+// current snapshot of the table. If the parent of the snapshot is the
+// current snapshot, the current snapshot is set to it, otherwise a new
+// snapshot adding the same data files is created.
+func (ms *ManageSnapshots) CherryPick(ctx context.Context, snapshotID int64) *ManageSnapshots {
+	return ms.add(func(meta Metadata, changes *refChanges) error {
+		snap, err := lookupSnapshot(meta, snapshotID)
+		if err != nil {
+			return err
+		}
+
+		if snap.Summary == nil || snap.Summary.Operation != OpAppend {
+			return fmt.Errorf("%w: can only cherry-pick append snapshots, snapshot %d is not an append",
+				ErrInvalidOperation, snapshotID)
+		}
+
+		// a snapshot staged with write-audit-publish is published once
+		wapID := snap.Summary.Properties[WapIDKey]
+		current := meta.CurrentSnapshot()
+		for s := range ancestorsOf(meta, current) {
+			var props iceberg.Properties
+			if s.Summary != nil {
+				props = s.Summary.Properties
+			}
+
+			if s.SnapshotID == snapshotID || props[SourceSnapshotIDKey] == strconv.FormatInt(snapshotID, 10) ||
+				(wapID != "" && props[PublishedWapIDKey] == wapID) {
+				return fmt.Errorf("%w: snapshot %d was already applied to the current snapshot",
+					ErrInvalidOperation, snapshotID)
+			}
+		}
+
+		if current != nil && snap.ParentSnapshotID != nil && *snap.ParentSnapshotID == current.SnapshotID {
+			return changes.setBranch(meta, MainBranch, snapshotID)
+		}
+
+		fs, err := ms.txn.tbl.fsF(ctx)
+		if err != nil {
+			return err
+		}
+
+		manifests, err := snap.Manifests(fs)
+		if err != nil {
+			return err
+		}
+
+		// only the manifests written by the snapshot can hold the entries
+		// of the files it added.
+		var added []iceberg.DataFile
+		for _, mf := range manifests {
+			if mf.SnapshotID() != snapshotID || mf.ManifestContent() != iceberg.ManifestContentData {
+				continue
+			}
+
+			entries, err := fetchManifestEntries(fs, mf)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range entries {
+				if e.Status() == iceberg.EntryStatusADDED && e.SnapshotID() == snapshotID {
+					added = append(added, e.DataFile())
+				}
+			}
+		}
+
+		props := iceberg.Properties{SourceSnapshotIDKey: strconv.FormatInt(snapshotID, 10)}
+		if wapID != "" {
+			props[PublishedWapIDKey] = wapID
+		}
+
+		// the snapshot producer works on the metadata of the transaction,
+		// which must first have the changes of the operations before
+		changes.snapshot = func() error {
+			updater := ms.txn.updateSnapshot(fs, props).fastAppend().toBranch(MainBranch)
+			for _, df := range added {
+				updater.appendDataFile(df)
+			}
+
+			return ms.txn.applySnapshot(updater)
+		}
+
+		return nil
+	})
+}
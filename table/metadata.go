@@ -36,7 +36,7 @@ import (
 
 const (
 	partitionFieldStartID       = 1000
-	supportedTableFormatVersion = 2
+	supportedTableFormatVersion = 3
 )
 
 func generateSnapshotID() int64 {
@@ -129,11 +129,19 @@ type Metadata interface {
 	Properties() iceberg.Properties
 	// PreviousFiles returns the list of metadata log entries for the table.
 	PreviousFiles() iter.Seq[MetadataLogEntry]
+	// Statistics returns the table statistics files of the snapshots.
+	Statistics() iter.Seq[StatisticsFile]
+	// PartitionStatistics returns the partition statistics files of the
+	// snapshots.
+	PartitionStatistics() iter.Seq[PartitionStatisticsFile]
 	Equals(Metadata) bool
 
 	NameMapping() iceberg.NameMapping
 
 	LastSequenceNumber() int64
+	// NextRowID returns the row id to assign to the first row added by the
+	// next snapshot. It is always 0 for tables before format version 3.
+	NextRowID() int64
 }
 
 type MetadataBuilder struct {
@@ -159,9 +167,13 @@ type MetadataBuilder struct {
 	sortOrderList      []SortOrder
 	defaultSortOrderID int
 	refs               map[string]SnapshotRef
+	statistics         []StatisticsFile
+	partitionStats     []PartitionStatisticsFile
 
 	// >v1 specific
 	lastSequenceNumber *int64
+	// >v2 specific
+	nextRowID int64
 }
 
 func NewMetadataBuilder() (*MetadataBuilder, error) {
@@ -187,19 +199,22 @@ func MetadataBuilderFromBase(metadata Metadata) (*MetadataBuilder, error) {
 	b.loc = metadata.Location()
 	b.lastUpdatedMS = metadata.LastUpdatedMillis()
 	b.lastColumnId = metadata.LastColumnID()
-	b.schemaList = metadata.Schemas()
+	// the base metadata may be shared, e.g. through the metadata cache,
+	// so it must not be modified by the builder
+	b.schemaList = slices.Clone(metadata.Schemas())
 	b.currentSchemaID = metadata.CurrentSchema().ID
-	b.specs = metadata.PartitionSpecs()
+	b.specs = slices.Clone(metadata.PartitionSpecs())
 	b.defaultSpecID = metadata.DefaultPartitionSpec()
 	b.lastPartitionID = metadata.LastPartitionSpecID()
-	b.props = metadata.Properties()
-	b.snapshotList = metadata.Snapshots()
-	b.sortOrderList = metadata.SortOrders()
+	b.props = maps.Clone(metadata.Properties())
+	b.snapshotList = slices.Clone(metadata.Snapshots())
+	b.sortOrderList = slices.Clone(metadata.SortOrders())
 	b.defaultSortOrderID = metadata.DefaultSortOrder()
 	if metadata.Version() > 1 {
 		seq := metadata.LastSequenceNumber()
 		b.lastSequenceNumber = &seq
 	}
+	b.nextRowID = metadata.NextRowID()
 
 	if metadata.CurrentSnapshot() != nil {
 		b.currentSnapshotID = &metadata.CurrentSnapshot().SnapshotID
@@ -208,6 +223,8 @@ func MetadataBuilderFromBase(metadata Metadata) (*MetadataBuilder, error) {
 	b.refs = maps.Collect(metadata.Refs())
 	b.snapshotLog = slices.Collect(metadata.SnapshotLogs())
 	b.metadataLog = slices.Collect(metadata.PreviousFiles())
+	b.statistics = slices.Collect(metadata.Statistics())
+	b.partitionStats = slices.Collect(metadata.PartitionStatistics())
 
 	return b, nil
 }
@@ -247,6 +264,18 @@ func (b *MetadataBuilder) newSnapshotID() int64 {
 	return snapshotID
 }
 
+// branchSnapshot returns the snapshot the branch refers to, or the current
+// snapshot if there is no such branch.
+func (b *MetadataBuilder) branchSnapshot(branch string) *Snapshot {
+	if ref, ok := b.refs[branch]; ok {
+		s, _ := b.SnapshotByID(ref.SnapshotID)
+
+		return s
+	}
+
+	return b.currentSnapshot()
+}
+
 func (b *MetadataBuilder) currentSnapshot() *Snapshot {
 	if b.currentSnapshotID == nil {
 		return nil
@@ -319,7 +348,7 @@ func (b *MetadataBuilder) AddSnapshot(snapshot *Snapshot) (*MetadataBuilder, err
 		return nil, errors.New("can't add snapshot with no added partition specs")
 	} else if s, _ := b.SnapshotByID(snapshot.SnapshotID); s != nil {
 		return nil, fmt.Errorf("can't add snapshot with id %d, already exists", snapshot.SnapshotID)
-	} else if b.formatVersion == 2 &&
+	} else if b.formatVersion >= 2 &&
 		snapshot.SequenceNumber > 0 &&
 		snapshot.ParentSnapshotID != nil &&
 		snapshot.SequenceNumber <= *b.lastSequenceNumber {
@@ -327,6 +356,20 @@ func (b *MetadataBuilder) AddSnapshot(snapshot *Snapshot) (*MetadataBuilder, err
 			snapshot.SequenceNumber, b.lastSequenceNumber)
 	}
 
+	if b.formatVersion >= 3 {
+		switch {
+		case snapshot.FirstRowID == nil:
+			return nil, errors.New("can't add snapshot with no first-row-id to a v3 table")
+		case snapshot.AddedRows == nil:
+			return nil, errors.New("can't add snapshot with no added-rows to a v3 table")
+		case *snapshot.FirstRowID < b.nextRowID:
+			return nil, fmt.Errorf("can't add snapshot with first-row-id %d, must be >= next-row-id %d",
+				*snapshot.FirstRowID, b.nextRowID)
+		}
+
+		b.nextRowID = *snapshot.FirstRowID + *snapshot.AddedRows
+	}
+
 	b.updates = append(b.updates, NewAddSnapshotUpdate(snapshot))
 	b.lastUpdatedMS = snapshot.TimestampMs
 	b.lastSequenceNumber = &snapshot.SequenceNumber
@@ -366,6 +409,68 @@ func (b *MetadataBuilder) RemoveProperties(keys []string) (*MetadataBuilder, err
 	return b, nil
 }
 
+func (b *MetadataBuilder) RemoveSnapshots(snapshotIDs []int64) (*MetadataBuilder, error) {
+	if b.currentSnapshotID != nil && slices.Contains(snapshotIDs, *b.currentSnapshotID) {
+		return nil, fmt.Errorf("%w: cannot remove current snapshot %d", iceberg.ErrInvalidArgument, *b.currentSnapshotID)
+	}
+
+	removed := func(id int64) bool { return slices.Contains(snapshotIDs, id) }
+	snapshots := make([]Snapshot, 0, len(b.snapshotList))
+	for _, s := range b.snapshotList {
+		if !removed(s.SnapshotID) {
+			snapshots = append(snapshots, s)
+		}
+	}
+
+	if len(snapshots) == len(b.snapshotList) {
+		return b, nil
+	}
+
+	snapshotLog := make([]SnapshotLogEntry, 0, len(b.snapshotLog))
+	for _, entry := range b.snapshotLog {
+		if !removed(entry.SnapshotID) {
+			snapshotLog = append(snapshotLog, entry)
+		}
+	}
+
+	refs := make(map[string]SnapshotRef, len(b.refs))
+	for name, ref := range b.refs {
+		if !removed(ref.SnapshotID) {
+			refs[name] = ref
+		}
+	}
+
+	b.snapshotList = snapshots
+	b.snapshotLog = snapshotLog
+	b.refs = refs
+	b.statistics = slices.DeleteFunc(b.statistics, func(s StatisticsFile) bool {
+		return removed(s.SnapshotID)
+	})
+	b.partitionStats = slices.DeleteFunc(b.partitionStats, func(s PartitionStatisticsFile) bool {
+		return removed(s.SnapshotID)
+	})
+	b.updates = append(b.updates, NewRemoveSnapshotsUpdate(snapshotIDs))
+
+	return b, nil
+}
+
+func (b *MetadataBuilder) RemoveSnapshotRef(name string) (*MetadataBuilder, error) {
+	if _, ok := b.refs[name]; !ok {
+		return b, nil
+	}
+
+	if name == MainBranch {
+		b.currentSnapshotID = nil
+	}
+
+	refs := maps.Clone(b.refs)
+	delete(refs, name)
+	b.refs = refs
+	b.updates = append(b.updates, NewRemoveSnapshotRefUpdate(name))
+
+	return b, nil
+}
+
 func (b *MetadataBuilder) SetCurrentSchemaID(currentSchemaID int) (*MetadataBuilder, error) {
 	if currentSchemaID == -1 {
 		currentSchemaID = maxBy(b.schemaList, func(s *iceberg.Schema) int {
@@ -570,8 +675,8 @@ func (b *MetadataBuilder) SetSnapshotRef(
 		b.lastUpdatedMS = snapshot.TimestampMs
 	}
 
+	b.updates = append(b.updates, NewSetSnapshotRefUpdate(name, snapshotID, refType, maxRefAgeMs, maxSnapshotAgeMs, minSnapshotsToKeep))
 	if name == MainBranch {
-		b.updates = append(b.updates, NewSetSnapshotRefUpdate(name, snapshotID, refType, maxRefAgeMs, maxSnapshotAgeMs, minSnapshotsToKeep))
 		b.currentSnapshotID = &snapshotID
 		if !isAddedSnapshot {
 			b.lastUpdatedMS = time.Now().Local().UnixMilli()
@@ -634,6 +739,8 @@ func (b *MetadataBuilder) buildCommonMetadata() *commonMetadata {
 		SortOrderList:      b.sortOrderList,
 		DefaultSortOrderID: b.defaultSortOrderID,
 		SnapshotRefs:       b.refs,
+		StatisticsList:     b.statistics,
+		PartitionStatsList: b.partitionStats,
 	}
 }
 
@@ -745,6 +852,19 @@ func (b *MetadataBuilder) Build() (Metadata, error) {
 			commonMetadata: *common,
 		}, nil
 
+	case 3:
+		var lastSequenceNumber int64
+
+		if b.lastSequenceNumber != nil {
+			lastSequenceNumber = *b.lastSequenceNumber
+		}
+
+		return &metadataV3{
+			LastSeqNum:     lastSequenceNumber,
+			NextRow:        b.nextRowID,
+			commonMetadata: *common,
+		}, nil
+
 	default:
 		panic("unreachable: invalid format version")
 	}
@@ -798,6 +918,8 @@ func ParseMetadataBytes(b []byte) (Metadata, error) {
 		ret = &metadataV1{}
 	case 2:
 		ret = &metadataV2{}
+	case 3:
+		ret = &metadataV3{}
 	default:
 		return nil, ErrInvalidMetadataFormatVersion
 	}
@@ -813,24 +935,26 @@ func sliceEqualHelper[T interface{ Equals(T) bool }](s1, s2 []T) bool {
 
 // https://iceberg.apache.org/spec/#iceberg-table-spec
 type commonMetadata struct {
-	FormatVersion      int                     `json:"format-version"`
-	UUID               uuid.UUID               `json:"table-uuid"`
-	Loc                string                  `json:"location"`
-	LastUpdatedMS      int64                   `json:"last-updated-ms"`
-	LastColumnId       int                     `json:"last-column-id"`
-	SchemaList         []*iceberg.Schema       `json:"schemas"`
-	CurrentSchemaID    int                     `json:"current-schema-id"`
-	Specs              []iceberg.PartitionSpec `json:"partition-specs"`
-	DefaultSpecID      int                     `json:"default-spec-id"`
-	LastPartitionID    *int                    `json:"last-partition-id,omitempty"`
-	Props              iceberg.Properties      `json:"properties,omitempty"`
-	SnapshotList       []Snapshot              `json:"snapshots,omitempty"`
-	CurrentSnapshotID  *int64                  `json:"current-snapshot-id,omitempty"`
-	SnapshotLog        []SnapshotLogEntry      `json:"snapshot-log,omitempty"`
-	MetadataLog        []MetadataLogEntry      `json:"metadata-log,omitempty"`
-	SortOrderList      []SortOrder             `json:"sort-orders"`
-	DefaultSortOrderID int                     `json:"default-sort-order-id"`
-	SnapshotRefs       map[string]SnapshotRef  `json:"refs,omitempty"`
+	FormatVersion      int                       `json:"format-version"`
+	UUID               uuid.UUID                 `json:"table-uuid"`
+	Loc                string                    `json:"location"`
+	LastUpdatedMS      int64                     `json:"last-updated-ms"`
+	LastColumnId       int                       `json:"last-column-id"`
+	SchemaList         []*iceberg.Schema         `json:"schemas"`
+	CurrentSchemaID    int                       `json:"current-schema-id"`
+	Specs              []iceberg.PartitionSpec   `json:"partition-specs"`
+	DefaultSpecID      int                       `json:"default-spec-id"`
+	LastPartitionID    *int                      `json:"last-partition-id,omitempty"`
+	Props              iceberg.Properties        `json:"properties,omitempty"`
+	SnapshotList       []Snapshot                `json:"snapshots,omitempty"`
+	CurrentSnapshotID  *int64                    `json:"current-snapshot-id,omitempty"`
+	SnapshotLog        []SnapshotLogEntry        `json:"snapshot-log,omitempty"`
+	MetadataLog        []MetadataLogEntry        `json:"metadata-log,omitempty"`
+	SortOrderList      []SortOrder               `json:"sort-orders"`
+	DefaultSortOrderID int                       `json:"default-sort-order-id"`
+	SnapshotRefs       map[string]SnapshotRef    `json:"refs,omitempty"`
+	StatisticsList     []StatisticsFile          `json:"statistics,omitempty"`
+	PartitionStatsList []PartitionStatisticsFile `json:"partition-statistics,omitempty"`
 }
 
 func (c *commonMetadata) Ref() SnapshotRef                     { return c.SnapshotRefs[MainBranch] }
@@ -843,6 +967,14 @@ func (c *commonMetadata) PreviousFiles() iter.Seq[MetadataLogEntry] {
 	return slices.Values(c.MetadataLog)
 }
 
+func (c *commonMetadata) Statistics() iter.Seq[StatisticsFile] {
+	return slices.Values(c.StatisticsList)
+}
+
+func (c *commonMetadata) PartitionStatistics() iter.Seq[PartitionStatisticsFile] {
+	return slices.Values(c.PartitionStatsList)
+}
+
 func (c *commonMetadata) Equals(other *commonMetadata) bool {
 	if other == nil {
 		return false
@@ -976,6 +1108,10 @@ func (c *commonMetadata) preValidate() {
 		c.CurrentSnapshotID = nil
 	}
 
+	if c.SnapshotRefs == nil {
+		c.SnapshotRefs = make(map[string]SnapshotRef)
+	}
+
 	if c.CurrentSnapshotID != nil {
 		if _, ok := c.SnapshotRefs[MainBranch]; !ok {
 			c.SnapshotRefs[MainBranch] = SnapshotRef{
@@ -989,10 +1125,6 @@ func (c *commonMetadata) preValidate() {
 		c.MetadataLog = []MetadataLogEntry{}
 	}
 
-	if c.SnapshotRefs == nil {
-		c.SnapshotRefs = make(map[string]SnapshotRef)
-	}
-
 	if c.SnapshotLog == nil {
 		c.SnapshotLog = []SnapshotLogEntry{}
 	}
@@ -1010,6 +1142,28 @@ func (c *commonMetadata) checkSchemas() error {
 		ErrInvalidMetadata, c.CurrentSchemaID)
 }
 
+// checkTypes verifies that the schemas only use types supported by the
+// table's format version.
+func (c *commonMetadata) checkTypes() error {
+	if c.FormatVersion >= 3 {
+		return nil
+	}
+
+	for _, s := range c.SchemaList {
+		for _, id := range s.FieldIDs() {
+			f, _ := s.FindFieldByID(id)
+			switch f.Type.(type) {
+			case iceberg.TimestampNsType, iceberg.TimestampTzNsType,
+				iceberg.UnknownType, iceberg.VariantType:
+				return fmt.Errorf("%w: field %q of schema %d has type %s which requires format version 3, table is version %d",
+					ErrInvalidMetadata, f.Name, s.ID, f.Type, c.FormatVersion)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (c *commonMetadata) checkPartitionSpecs() error {
 	for _, spec := range c.Specs {
 		if spec.ID() == c.DefaultSpecID {
@@ -1053,6 +1207,10 @@ func (c *commonMetadata) validate() error {
 		return err
 	}
 
+	if err := c.checkTypes(); err != nil {
+		return err
+	}
+
 	if err := c.checkPartitionSpecs(); err != nil {
 		return err
 	}
@@ -1096,6 +1254,7 @@ type metadataV1 struct {
 }
 
 func (m *metadataV1) LastSequenceNumber() int64 { return 0 }
+func (m *metadataV1) NextRowID() int64          { return 0 }
 
 func (m *metadataV1) Equals(other Metadata) bool {
 	rhs, ok := other.(*metadataV1)
@@ -1174,6 +1333,7 @@ type metadataV2 struct {
 }
 
 func (m *metadataV2) LastSequenceNumber() int64 { return m.LastSeqNum }
+func (m *metadataV2) NextRowID() int64          { return 0 }
 
 func (m *metadataV2) Equals(other Metadata) bool {
 	rhs, ok := other.(*metadataV2)
@@ -1205,6 +1365,52 @@ func (m *metadataV2) UnmarshalJSON(b []byte) error {
 	return m.validate()
 }
 
+type metadataV3 struct {
+	LastSeqNum int64 `json:"last-sequence-number"`
+	NextRow    int64 `json:"next-row-id"`
+
+	commonMetadata
+}
+
+func (m *metadataV3) LastSequenceNumber() int64 { return m.LastSeqNum }
+func (m *metadataV3) NextRowID() int64          { return m.NextRow }
+
+func (m *metadataV3) Equals(other Metadata) bool {
+	rhs, ok := other.(*metadataV3)
+	if !ok {
+		return false
+	}
+
+	if m == rhs {
+		return true
+	}
+
+	return m.LastSeqNum == rhs.LastSeqNum && m.NextRow == rhs.NextRow &&
+		m.commonMetadata.Equals(&rhs.commonMetadata)
+}
+
+func (m *metadataV3) UnmarshalJSON(b []byte) error {
+	type Alias metadataV3
+	aux := (*Alias)(m)
+
+	// Set LastColumnId to -1 to indicate that it is not set as LastColumnId = 0 is a valid value for when no schema is present
+	aux.LastColumnId = -1
+	// likewise next-row-id is required, but 0 is valid for a new table
+	aux.NextRow = -1
+
+	if err := json.Unmarshal(b, aux); err != nil {
+		return err
+	}
+
+	if m.NextRow < 0 {
+		return fmt.Errorf("%w: missing next-row-id", ErrInvalidMetadata)
+	}
+
+	m.preValidate()
+
+	return m.validate()
+}
+
 const DefaultFormatVersion = 2
 
 // NewMetadata creates a new table metadata object using the provided schema, information, generating a fresh UUID for
@@ -1273,6 +1479,8 @@ func NewMetadataWithUUID(sc *iceberg.Schema, partitions *iceberg.PartitionSpec,
 		}, nil
 	case 2:
 		return &metadataV2{commonMetadata: common}, nil
+	case 3:
+		return &metadataV3{commonMetadata: common}, nil
 	default:
 		return nil, fmt.Errorf("invalid format version: %d", formatVersion)
 	}
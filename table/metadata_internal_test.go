@@ -20,6 +20,7 @@ package table
 import (
 	"encoding/json"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/apache/iceberg-go"
@@ -90,6 +91,47 @@ const ExampleTableMetadataV2 = `{
     "refs": {"test": {"snapshot-id": 3051729675574597004, "type": "tag", "max-ref-age-ms": 10000000}}
 }`
 
+const ExampleTableMetadataV3 = `{
+    "format-version": 3,
+    "table-uuid": "9c12d441-03fe-4693-9a96-a0705ddf69c1",
+    "location": "s3://bucket/test/location",
+    "last-sequence-number": 34,
+    "next-row-id": 1200,
+    "last-updated-ms": 1602638573590,
+    "last-column-id": 4,
+    "current-schema-id": 0,
+    "schemas": [
+        {
+            "type": "struct",
+            "schema-id": 0,
+            "fields": [
+                {"id": 1, "name": "x", "required": true, "type": "long"},
+                {"id": 2, "name": "ts", "required": false, "type": "timestamptz_ns"},
+                {"id": 3, "name": "v", "required": false, "type": "variant"},
+                {"id": 4, "name": "u", "required": false, "type": "unknown"}
+            ]
+        }
+    ],
+    "default-spec-id": 0,
+    "partition-specs": [{"spec-id": 0, "fields": []}],
+    "last-partition-id": 999,
+    "default-sort-order-id": 0,
+    "sort-orders": [{"order-id": 0, "fields": []}],
+    "current-snapshot-id": 3055729675574597004,
+    "snapshots": [
+        {
+            "snapshot-id": 3055729675574597004,
+            "timestamp-ms": 1555100955770,
+            "sequence-number": 34,
+            "summary": {"operation": "append"},
+            "manifest-list": "s3://a/b/2.avro",
+            "schema-id": 0,
+            "first-row-id": 1000,
+            "added-rows": 200
+        }
+    ]
+}`
+
 const ExampleTableMetadataV1 = `{
 	"format-version": 1,
 	"table-uuid": "d20125c8-7284-442c-9aea-15fee620737c",
@@ -190,6 +232,92 @@ func TestMetadataV2Parsing(t *testing.T) {
 	assert.EqualValues(t, "134217728", meta.Properties()["read.split.target.size"])
 }
 
+func TestMetadataV3Parsing(t *testing.T) {
+	meta, err := ParseMetadataBytes([]byte(ExampleTableMetadataV3))
+	require.NoError(t, err)
+
+	assert.IsType(t, (*metadataV3)(nil), meta)
+	assert.Equal(t, 3, meta.Version())
+	assert.EqualValues(t, 34, meta.LastSequenceNumber())
+	assert.EqualValues(t, 1200, meta.NextRowID())
+
+	fields := meta.CurrentSchema().Fields()
+	assert.Equal(t, iceberg.PrimitiveTypes.TimestampTzNs, fields[1].Type)
+	assert.Equal(t, iceberg.PrimitiveTypes.Variant, fields[2].Type)
+	assert.Equal(t, iceberg.PrimitiveTypes.Unknown, fields[3].Type)
+
+	snap := meta.CurrentSnapshot()
+	require.NotNil(t, snap)
+	assert.EqualValues(t, 1000, *snap.FirstRowID)
+	assert.EqualValues(t, 200, *snap.AddedRows)
+
+	data, err := json.Marshal(meta)
+	require.NoError(t, err)
+
+	roundTrip, err := ParseMetadataBytes(data)
+	require.NoError(t, err)
+	assert.True(t, meta.Equals(roundTrip))
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.EqualValues(t, 1200, raw["next-row-id"])
+
+	_, err = ParseMetadataString(strings.Replace(ExampleTableMetadataV3, `"next-row-id": 1200,`, "", 1))
+	assert.ErrorIs(t, err, ErrInvalidMetadata)
+}
+
+func TestV3TypesRequireV3(t *testing.T) {
+	v2 := strings.Replace(ExampleTableMetadataV3, `"format-version": 3`, `"format-version": 2`, 1)
+	_, err := ParseMetadataString(v2)
+	assert.ErrorIs(t, err, ErrInvalidMetadata)
+	assert.ErrorContains(t, err, "requires format version 3")
+}
+
+func TestUpgradeFormatVersionV3(t *testing.T) {
+	meta, err := ParseMetadataString(ExampleTableMetadataV2)
+	require.NoError(t, err)
+
+	builder, err := MetadataBuilderFromBase(meta)
+	require.NoError(t, err)
+	require.NoError(t, NewUpgradeFormatVersionUpdate(3).Apply(builder))
+
+	upgraded, err := builder.Build()
+	require.NoError(t, err)
+	assert.IsType(t, (*metadataV3)(nil), upgraded)
+	assert.EqualValues(t, 34, upgraded.LastSequenceNumber())
+	assert.Zero(t, upgraded.NextRowID())
+
+	data, err := json.Marshal(upgraded)
+	require.NoError(t, err)
+	reparsed, err := ParseMetadataBytes(data)
+	require.NoError(t, err)
+	assert.True(t, upgraded.Equals(reparsed))
+
+	// snapshots added to a v3 table must carry row lineage
+	parent := meta.CurrentSnapshot().SnapshotID
+	snap := Snapshot{
+		SnapshotID:       1,
+		ParentSnapshotID: &parent,
+		SequenceNumber:   35,
+		TimestampMs:      1602638573591,
+		ManifestList:     "s3://a/b/3.avro",
+		Summary:          &Summary{Operation: OpAppend},
+	}
+	_, err = builder.AddSnapshot(&snap)
+	assert.ErrorContains(t, err, "first-row-id")
+
+	firstRowID, addedRows := int64(0), int64(10)
+	snap.FirstRowID, snap.AddedRows = &firstRowID, &addedRows
+	_, err = builder.AddSnapshot(&snap)
+	require.NoError(t, err)
+
+	upgraded, err = builder.Build()
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, upgraded.NextRowID())
+
+	assert.ErrorContains(t, NewUpgradeFormatVersionUpdate(4).Apply(builder), "unsupported format version 4")
+}
+
 func TestParsingCorrectTypes(t *testing.T) {
 	var meta metadataV2
 	require.NoError(t, json.Unmarshal([]byte(ExampleTableMetadataV2), &meta))
@@ -267,6 +395,42 @@ func TestSerializeMetadataV2(t *testing.T) {
 		string(data))
 }
 
+func TestMetadataStatistics(t *testing.T) {
+	var meta metadataV2
+	require.NoError(t, json.Unmarshal([]byte(ExampleTableMetadataV2), &meta))
+
+	meta.StatisticsList = []StatisticsFile{
+		{
+			SnapshotID: 3051729675574597004, StatisticsPath: "s3://a/b/stats-1.puffin",
+			FileSizeInBytes: 100, FileFooterSizeInBytes: 10,
+			BlobMetadata: []BlobMetadata{{Type: "apache-datasketches-theta-v1", SnapshotID: 3051729675574597004, SequenceNumber: 0, Fields: []int32{1}}},
+		},
+		{SnapshotID: 3055729675574597004, StatisticsPath: "s3://a/b/stats-2.puffin", FileSizeInBytes: 100, FileFooterSizeInBytes: 10},
+	}
+	meta.PartitionStatsList = []PartitionStatisticsFile{
+		{SnapshotID: 3051729675574597004, StatisticsPath: "s3://a/b/partition-stats-1.parquet", FileSizeInBytes: 50},
+	}
+
+	data, err := json.Marshal(&meta)
+	require.NoError(t, err)
+
+	parsed, err := ParseMetadataBytes(data)
+	require.NoError(t, err)
+	assert.Equal(t, meta.StatisticsList, slices.Collect(parsed.Statistics()))
+	assert.Equal(t, meta.PartitionStatsList, slices.Collect(parsed.PartitionStatistics()))
+
+	// the statistics of removed snapshots are removed with them
+	bldr, err := MetadataBuilderFromBase(parsed)
+	require.NoError(t, err)
+	_, err = bldr.RemoveSnapshots([]int64{3051729675574597004})
+	require.NoError(t, err)
+	updated, err := bldr.Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, meta.StatisticsList[1:], slices.Collect(updated.Statistics()))
+	assert.Empty(t, slices.Collect(updated.PartitionStatistics()))
+}
+
 func TestInvalidFormatVersion(t *testing.T) {
 	metadataInvalidFormat := `{
         "format-version": -1,
@@ -17,7 +17,11 @@
 
 package table
 
-import "github.com/apache/iceberg-go/table/internal"
+import (
+	"math"
+
+	"github.com/apache/iceberg-go/table/internal"
+)
 
 const (
 	WriteDataPathKey                        = "write.data.path"
@@ -50,6 +54,8 @@ const (
 	ParquetBloomFilterMaxBytesKey            = internal.ParquetBloomFilterMaxBytesKey
 	ParquetBloomFilterMaxBytesDefault        = internal.ParquetBloomFilterMaxBytesDefault
 	ParquetBloomFilterColumnEnabledKeyPrefix = internal.ParquetBloomFilterColumnEnabledKeyPrefix
+	ParquetPageIndexEnabledKey               = internal.ParquetPageIndexEnabledKey
+	ParquetPageIndexEnabledDefault           = internal.ParquetPageIndexEnabledDefault
 
 	ManifestMergeEnabledKey     = "commit.manifest-merge.enabled"
 	ManifestMergeEnabledDefault = false
@@ -60,6 +66,18 @@ const (
 	ManifestMinMergeCountKey     = "commit.manifest.min-count-to-merge"
 	ManifestMinMergeCountDefault = 100
 
+	CommitNumRetriesKey     = "commit.retry.num-retries"
+	CommitNumRetriesDefault = 4
+
+	CommitMinRetryWaitMsKey     = "commit.retry.min-wait-ms"
+	CommitMinRetryWaitMsDefault = 100
+
+	CommitMaxRetryWaitMsKey     = "commit.retry.max-wait-ms"
+	CommitMaxRetryWaitMsDefault = 60 * 1000 // 1 minute
+
+	CommitTotalRetryTimeoutMsKey     = "commit.retry.total-timeout-ms"
+	CommitTotalRetryTimeoutMsDefault = 30 * 60 * 1000 // 30 minutes
+
 	WritePartitionSummaryLimitKey     = "write.summary.partition-limit"
 	WritePartitionSummaryLimitDefault = 0
 
@@ -71,4 +89,28 @@ const (
 
 	WriteTargetFileSizeBytesKey     = "write.target-file-size-bytes"
 	WriteTargetFileSizeBytesDefault = 512 * 1024 * 1024 // 512 MB
+
+	// WriteWapEnabledKey enables write-audit-publish: snapshots written
+	// with the WapIDKey snapshot property are staged without updating any
+	// branch, to be published later with ManageSnapshots.CherryPick.
+	WriteWapEnabledKey     = "write.wap.enabled"
+	WriteWapEnabledDefault = false
+
+	SplitSizeKey     = "read.split.target-size"
+	SplitSizeDefault = 128 * 1024 * 1024 // 128 MB
+
+	SplitLookbackKey     = "read.split.planning-lookback"
+	SplitLookbackDefault = 10
+
+	SplitOpenFileCostKey     = "read.split.open-file-cost"
+	SplitOpenFileCostDefault = 4 * 1024 * 1024 // 4 MB
+
+	MaxSnapshotAgeMsKey     = "history.expire.max-snapshot-age-ms"
+	MaxSnapshotAgeMsDefault = 5 * 24 * 60 * 60 * 1000 // 5 days
+
+	MinSnapshotsToKeepKey     = "history.expire.min-snapshots-to-keep"
+	MinSnapshotsToKeepDefault = 1
+
+	MaxRefAgeMsKey     = "history.expire.max-ref-age-ms"
+	MaxRefAgeMsDefault = math.MaxInt64
 )
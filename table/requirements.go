@@ -37,7 +37,18 @@ const (
 	reqAssertLastAssignedPartitionID = "assert-last-assigned-partition-id"
 )
 
-var ErrInvalidRequirement = errors.New("invalid requirement")
+var (
+	ErrInvalidRequirement = errors.New("invalid requirement")
+	// ErrCommitFailed is returned when a commit conflicts with a concurrent
+	// change to the table. The commit can be retried after refreshing the table.
+	ErrCommitFailed = errors.New("commit failed, refresh and try again")
+	// ErrCommitStateUnknown is returned when a catalog cannot tell whether a
+	// commit was applied, in which case the files it references are kept.
+	ErrCommitStateUnknown = errors.New("commit state unknown")
+	// ErrValidationFailed is returned when a commit cannot be retried because
+	// a concurrent change to the table conflicts with the transaction.
+	ErrValidationFailed = errors.New("validation failed")
+)
 
 // A Requirement is a validation rule that must be satisfied before attempting to
 // make and commit changes to a table. Requirements are used to ensure that the
@@ -180,14 +191,14 @@ func (a *assertRefSnapshotID) Validate(meta Metadata) error {
 
 	if r != nil {
 		if a.SnapshotID == nil {
-			return fmt.Errorf("requirement failed: %s %s was created concurrently", r.SnapshotRefType, a.Ref)
+			return fmt.Errorf("%w: requirement failed: %s %s was created concurrently", ErrCommitFailed, r.SnapshotRefType, a.Ref)
 		}
 
 		if r.SnapshotID != *a.SnapshotID {
-			return fmt.Errorf("requirement failed: %s %s has changed: expected id %d, found %d", r.SnapshotRefType, a.Ref, a.SnapshotID, r.SnapshotID)
+			return fmt.Errorf("%w: requirement failed: %s %s has changed: expected id %d, found %d", ErrCommitFailed, r.SnapshotRefType, a.Ref, a.SnapshotID, r.SnapshotID)
 		}
 	} else if a.SnapshotID != nil {
-		return fmt.Errorf("requirement failed: branch or tag %s is missing, expected %d", a.Ref, a.SnapshotID)
+		return fmt.Errorf("%w: requirement failed: branch or tag %s is missing, expected %d", ErrCommitFailed, a.Ref, a.SnapshotID)
 	}
 
 	return nil
@@ -213,7 +224,7 @@ func (a *assertLastAssignedFieldId) Validate(meta Metadata) error {
 	}
 
 	if meta.LastColumnID() != a.LastAssignedFieldID {
-		return fmt.Errorf("requirement failed: last assigned field id has changed: expected %d, found %d", a.LastAssignedFieldID, meta.LastColumnID())
+		return fmt.Errorf("%w: requirement failed: last assigned field id has changed: expected %d, found %d", ErrCommitFailed, a.LastAssignedFieldID, meta.LastColumnID())
 	}
 
 	return nil
@@ -239,7 +250,7 @@ func (a *assertCurrentSchemaId) Validate(meta Metadata) error {
 	}
 
 	if meta.CurrentSchema().ID != a.CurrentSchemaID {
-		return fmt.Errorf("requirement failed: current schema id has changed: expected %d, found %d", a.CurrentSchemaID, meta.CurrentSchema().ID)
+		return fmt.Errorf("%w: requirement failed: current schema id has changed: expected %d, found %d", ErrCommitFailed, a.CurrentSchemaID, meta.CurrentSchema().ID)
 	}
 
 	return nil
@@ -265,7 +276,7 @@ func (a *assertLastAssignedPartitionId) Validate(meta Metadata) error {
 	}
 
 	if *meta.LastPartitionSpecID() != a.LastAssignedPartitionID {
-		return fmt.Errorf("requirement failed: last assigned partition id has changed: expected %d, found %d", a.LastAssignedPartitionID, *meta.LastPartitionSpecID())
+		return fmt.Errorf("%w: requirement failed: last assigned partition id has changed: expected %d, found %d", ErrCommitFailed, a.LastAssignedPartitionID, *meta.LastPartitionSpecID())
 	}
 
 	return nil
@@ -291,7 +302,7 @@ func (a *assertDefaultSpecId) Validate(meta Metadata) error {
 	}
 
 	if meta.DefaultPartitionSpec() != a.DefaultSpecID {
-		return fmt.Errorf("requirement failed: default spec id has changed: expected %d, found %d", a.DefaultSpecID, meta.DefaultPartitionSpec())
+		return fmt.Errorf("%w: requirement failed: default spec id has changed: expected %d, found %d", ErrCommitFailed, a.DefaultSpecID, meta.DefaultPartitionSpec())
 	}
 
 	return nil
@@ -317,7 +328,7 @@ func (a *assertDefaultSortOrderId) Validate(meta Metadata) error {
 	}
 
 	if meta.DefaultSortOrder() != a.DefaultSortOrderID {
-		return fmt.Errorf("requirement failed: default sort order id has changed: expected %d, found %d", a.DefaultSortOrderID, meta.DefaultSortOrder())
+		return fmt.Errorf("%w: requirement failed: default sort order id has changed: expected %d, found %d", ErrCommitFailed, a.DefaultSortOrderID, meta.DefaultSortOrder())
 	}
 
 	return nil
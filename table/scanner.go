@@ -20,9 +20,9 @@ package table
 import (
 	"cmp"
 	"context"
-	"errors"
 	"fmt"
 	"iter"
+	"reflect"
 	"slices"
 	"sync"
 	"time"
@@ -30,6 +30,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/internal"
 	"github.com/apache/iceberg-go/internal/telemetry/metrics"
 	"github.com/apache/iceberg-go/io"
 	"go.opentelemetry.io/otel/attribute"
@@ -90,10 +91,12 @@ func (p partitionRecord) Size() int            { return len(p) }
 func (p partitionRecord) Get(pos int) any      { return p[pos] }
 func (p partitionRecord) Set(pos int, val any) { p[pos] = val }
 
-// manifestEntries holds the data and positional delete entries read from manifests.
+// manifestEntries holds the data, positional delete and equality delete
+// entries read from manifests.
 type manifestEntries struct {
 	dataEntries             []iceberg.ManifestEntry
 	positionalDeleteEntries []iceberg.ManifestEntry
+	equalityDeleteEntries   []iceberg.ManifestEntry
 	mu                      sync.Mutex
 }
 
@@ -101,6 +104,7 @@ func newManifestEntries() *manifestEntries {
 	return &manifestEntries{
 		dataEntries:             make([]iceberg.ManifestEntry, 0),
 		positionalDeleteEntries: make([]iceberg.ManifestEntry, 0),
+		equalityDeleteEntries:   make([]iceberg.ManifestEntry, 0),
 	}
 }
 
@@ -116,6 +120,12 @@ func (m *manifestEntries) addPositionalDeleteEntry(e iceberg.ManifestEntry) {
 	m.positionalDeleteEntries = append(m.positionalDeleteEntries, e)
 }
 
+func (m *manifestEntries) addEqualityDeleteEntry(e iceberg.ManifestEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.equalityDeleteEntries = append(m.equalityDeleteEntries, e)
+}
+
 func getPartitionRecord(dataFile iceberg.DataFile, partitionType *iceberg.StructType) partitionRecord {
 	partitionData := dataFile.Partition()
 
@@ -127,10 +137,22 @@ func getPartitionRecord(dataFile iceberg.DataFile, partitionType *iceberg.Struct
 	return out
 }
 
+// fetchManifestEntries returns all the entries of the manifest, including
+// the deleted ones, through the manifest cache.
+func fetchManifestEntries(io io.IO, manifest iceberg.ManifestFile) ([]iceberg.ManifestEntry, error) {
+	return cachedRead(loadCache(&manifestCache), "manifest", io, manifest.FilePath(),
+		func() ([]iceberg.ManifestEntry, int64, error) {
+			entries, err := manifest.FetchEntries(io, false)
+
+			return entries, manifest.Length(), err
+		})
+}
+
 func openManifest(io io.IO, manifest iceberg.ManifestFile,
 	partitionFilter, metricsEval func(iceberg.DataFile) (bool, error),
 ) ([]iceberg.ManifestEntry, error) {
-	entries, err := manifest.FetchEntries(io, true)
+	// the deleted entries are skipped below
+	entries, err := fetchManifestEntries(io, manifest)
 	if err != nil {
 		return nil, err
 	}
@@ -155,6 +177,10 @@ func openManifest(io io.IO, manifest iceberg.ManifestFile,
 	}()
 
 	for _, entry := range entries {
+		if entry.Status() == iceberg.EntryStatusDELETED {
+			continue
+		}
+
 		var keep bool
 		keep, filterErr = partitionFilter(entry.DataFile())
 		if filterErr != nil {
@@ -194,6 +220,10 @@ type Scan struct {
 
 	partitionFilters *keyDefaultMap[int, iceberg.BooleanExpression]
 	concurrency      int
+
+	// err is the error of an option which failed to resolve the snapshot
+	// to scan, returned when planning or reading the scan.
+	err error
 }
 
 func (scan *Scan) UseRowLimit(n int64) *Scan {
@@ -203,46 +233,57 @@ func (scan *Scan) UseRowLimit(n int64) *Scan {
 	return &out
 }
 
+// UseRef returns a copy of the scan reading the snapshot the given branch
+// or tag refers to, see WithRef.
 func (scan *Scan) UseRef(name string) (*Scan, error) {
 	if scan.snapshotID != nil {
 		return nil, fmt.Errorf("%w: cannot override ref, already set snapshot id %d",
 			iceberg.ErrInvalidArgument, *scan.snapshotID)
 	}
 
-	if snap := scan.metadata.SnapshotByName(name); snap != nil {
-		out := *scan
-		out.snapshotID = &snap.SnapshotID
-		out.partitionFilters = newKeyDefaultMapWrapErr(out.buildPartitionProjection)
-
-		return &out, nil
+	out := *scan
+	WithRef(name)(&out)
+	if out.err != nil {
+		return nil, out.err
 	}
+	out.partitionFilters = newKeyDefaultMapWrapErr(out.buildPartitionProjection)
 
-	return nil, fmt.Errorf("%w: cannot scan unknown ref=%s", iceberg.ErrInvalidArgument, name)
+	return &out, nil
 }
 
-func (scan *Scan) Snapshot() *Snapshot {
-	if scan.snapshotID != nil {
-		return scan.metadata.SnapshotByID(*scan.snapshotID)
+// Snapshot returns the snapshot read by the scan, which is nil if the
+// table has no current snapshot. It returns the error of the options which
+// failed to resolve the snapshot, such as WithAsOfTimestamp and WithRef.
+func (scan *Scan) Snapshot() (*Snapshot, error) {
+	if scan.err != nil {
+		return nil, scan.err
 	}
 
-	return scan.metadata.CurrentSnapshot()
+	if scan.snapshotID == nil {
+		return scan.metadata.CurrentSnapshot(), nil
+	}
+
+	snap := scan.metadata.SnapshotByID(*scan.snapshotID)
+	if snap == nil {
+		return nil, fmt.Errorf("%w: snapshot not found: %d", ErrInvalidOperation, *scan.snapshotID)
+	}
+
+	return snap, nil
 }
 
 func (scan *Scan) Projection() (*iceberg.Schema, error) {
-	curSchema := scan.metadata.CurrentSchema()
-	if scan.snapshotID != nil {
-		snap := scan.metadata.SnapshotByID(*scan.snapshotID)
-		if snap == nil {
-			return nil, fmt.Errorf("%w: snapshot not found: %d", ErrInvalidOperation, *scan.snapshotID)
-		}
+	snap, err := scan.Snapshot()
+	if err != nil {
+		return nil, err
+	}
 
-		if snap.SchemaID != nil {
-			for _, schema := range scan.metadata.Schemas() {
-				if schema.ID == *snap.SchemaID {
-					curSchema = schema
+	curSchema := scan.metadata.CurrentSchema()
+	if scan.snapshotID != nil && snap.SchemaID != nil {
+		for _, schema := range scan.metadata.Schemas() {
+			if schema.ID == *snap.SchemaID {
+				curSchema = schema
 
-					break
-				}
+				break
 			}
 		}
 	}
@@ -327,12 +368,52 @@ func matchDeletesToData(entry iceberg.ManifestEntry, positionalDeletes []iceberg
 	return out, nil
 }
 
+// matchEqualityDeletesToData returns the equality delete files which apply
+// to the data file of the given entry. Equality deletes must be sorted by
+// sequence number. Per the spec, an equality delete file applies to a data
+// file when its data sequence number is strictly greater than the data file's
+// and it is either in the same partition or was written with an unpartitioned
+// spec, in which case it applies globally.
+func matchEqualityDeletesToData(entry iceberg.ManifestEntry, equalityDeletes []iceberg.ManifestEntry, specs []iceberg.PartitionSpec) []iceberg.DataFile {
+	idx, found := slices.BinarySearchFunc(equalityDeletes, entry, func(me1, me2 iceberg.ManifestEntry) int {
+		return cmp.Compare(me1.SequenceNum(), me2.SequenceNum())
+	})
+	// skip any deletes with the same sequence number as the data file
+	for found && idx < len(equalityDeletes) && equalityDeletes[idx].SequenceNum() == entry.SequenceNum() {
+		idx++
+	}
+
+	dataFile := entry.DataFile()
+	out := make([]iceberg.DataFile, 0)
+	for _, relevant := range equalityDeletes[idx:] {
+		df := relevant.DataFile()
+		if !isGlobalDelete(df, specs) &&
+			(df.SpecID() != dataFile.SpecID() || !reflect.DeepEqual(df.Partition(), dataFile.Partition())) {
+			continue
+		}
+
+		out = append(out, df)
+	}
+
+	return out
+}
+
+func isGlobalDelete(df iceberg.DataFile, specs []iceberg.PartitionSpec) bool {
+	for _, spec := range specs {
+		if spec.ID() == int(df.SpecID()) {
+			return spec.IsUnpartitioned()
+		}
+	}
+
+	return false
+}
+
 // fetchPartitionSpecFilteredManifests retrieves the table's current snapshot,
 // fetches its manifest files, and applies partition-spec filters to remove irrelevant manifests.
 func (scan *Scan) fetchPartitionSpecFilteredManifests(ctx context.Context) ([]iceberg.ManifestFile, error) {
-	snap := scan.Snapshot()
-	if snap == nil {
-		return nil, nil
+	snap, err := scan.Snapshot()
+	if err != nil || snap == nil {
+		return nil, err
 	}
 
 	afs, err := scan.ioF(ctx)
@@ -358,7 +439,8 @@ func (scan *Scan) fetchPartitionSpecFilteredManifests(ctx context.Context) ([]ic
 }
 
 // collectManifestEntries concurrently opens manifests, applies partition and metrics
-// filters, and accumulates both data entries and positional-delete entries.
+// filters, and accumulates data entries along with positional and equality
+// delete entries.
 func (scan *Scan) collectManifestEntries(
 	ctx context.Context,
 	manifestList []iceberg.ManifestFile,
@@ -406,7 +488,7 @@ func (scan *Scan) collectManifestEntries(
 				case iceberg.EntryContentPosDeletes:
 					entries.addPositionalDeleteEntry(e)
 				case iceberg.EntryContentEqDeletes:
-					return errors.New("iceberg-go does not yet support equality deletes")
+					entries.addEqualityDeleteEntry(e)
 				default:
 					return fmt.Errorf("%w: unknown DataFileContent type (%s): %s",
 						ErrInvalidMetadata, df.ContentType(), e)
@@ -427,29 +509,39 @@ func (scan *Scan) collectManifestEntries(
 // PlanFiles orchestrates the fetching and filtering of manifests, and then
 // building a list of FileScanTasks that match the current Scan criteria.
 func (scan *Scan) PlanFiles(ctx context.Context) ([]FileScanTask, error) {
+	if scan.err != nil {
+		return nil, scan.err
+	}
+
 	// Step 1: Retrieve filtered manifests based on snapshot and partition specs.
 	manifestList, err := scan.fetchPartitionSpecFilteredManifests(ctx)
 	if err != nil || len(manifestList) == 0 {
 		return nil, err
 	}
 
-	// Step 2: Read manifest entries concurrently, accumulating data and positional deletes.
+	// Step 2: Read manifest entries concurrently, accumulating data and delete entries.
 	entries, err := scan.collectManifestEntries(ctx, manifestList)
 	if err != nil {
 		return nil, err
 	}
 
-	// Step 3: Sort positional deletes and match them to data files.
-	slices.SortFunc(entries.positionalDeleteEntries, func(a, b iceberg.ManifestEntry) int {
+	// Step 3: Sort delete entries and match them to data files.
+	bySequenceNum := func(a, b iceberg.ManifestEntry) int {
 		return cmp.Compare(a.SequenceNum(), b.SequenceNum())
-	})
+	}
+	slices.SortFunc(entries.positionalDeleteEntries, bySequenceNum)
+	slices.SortFunc(entries.equalityDeleteEntries, bySequenceNum)
 
+	specs := scan.metadata.PartitionSpecs()
 	results := make([]FileScanTask, 0, len(entries.dataEntries))
 	for _, e := range entries.dataEntries {
 		deleteFiles, err := matchDeletesToData(e, entries.positionalDeleteEntries)
 		if err != nil {
 			return nil, err
 		}
+		deleteFiles = append(deleteFiles,
+			matchEqualityDeletesToData(e, entries.equalityDeleteEntries, specs)...)
+
 		results = append(results, FileScanTask{
 			File:        e.DataFile(),
 			DeleteFiles: deleteFiles,
@@ -467,6 +559,124 @@ type FileScanTask struct {
 	Start, Length int64
 }
 
+// CombinedScanTask is a group of file scan tasks, possibly covering only
+// part of a file each, which are intended to be read together.
+type CombinedScanTask struct {
+	Tasks []FileScanTask
+}
+
+// splitOffsetsValid reports whether the split offsets of the data file can
+// be used to split it, they must be ascending and within the file.
+func splitOffsetsValid(df iceberg.DataFile) bool {
+	offsets := df.SplitOffsets()
+	if len(offsets) == 0 || offsets[0] < 0 || !slices.IsSorted(offsets) {
+		return false
+	}
+
+	return offsets[len(offsets)-1] < df.FileSizeBytes()
+}
+
+// splitTask splits the task into tasks reading about targetSize bytes of the
+// file each. If the file has valid split offsets, the splits are aligned with
+// them by grouping adjacent offsets, otherwise the file is split into fixed
+// size ranges. Files in formats which cannot be split are not split.
+func splitTask(task FileScanTask, targetSize int64) []FileScanTask {
+	df := task.File
+	if df.FileFormat() != iceberg.ParquetFile || targetSize <= 0 {
+		return []FileScanTask{task}
+	}
+
+	newTask := func(start, length int64) FileScanTask {
+		return FileScanTask{File: df, DeleteFiles: task.DeleteFiles, Start: start, Length: length}
+	}
+
+	fileSize := df.FileSizeBytes()
+	if !splitOffsetsValid(df) {
+		tasks := make([]FileScanTask, 0, fileSize/targetSize+1)
+		for start := int64(0); start < fileSize; start += targetSize {
+			tasks = append(tasks, newTask(start, min(targetSize, fileSize-start)))
+		}
+
+		return tasks
+	}
+
+	offsets := df.SplitOffsets()
+	splitSize := func(i int) int64 {
+		if i == len(offsets)-1 {
+			return fileSize - offsets[i]
+		}
+
+		return offsets[i+1] - offsets[i]
+	}
+
+	tasks := make([]FileScanTask, 0, len(offsets))
+	for i := 0; i < len(offsets); {
+		start, length := offsets[i], splitSize(i)
+		for i++; i < len(offsets) && length+splitSize(i) <= targetSize; i++ {
+			length += splitSize(i)
+		}
+
+		tasks = append(tasks, newTask(start, length))
+	}
+
+	return tasks
+}
+
+// PlanTasks plans the files to scan like PlanFiles, then splits each of
+// them into tasks of about the read.split.target-size property and bin packs
+// the resulting tasks into combined tasks of about the same size. The sizes
+// account for the delete files of each task, and each task weighs at least
+// the read.split.open-file-cost property. The properties can be overridden
+// with the scan options.
+func (scan *Scan) PlanTasks(ctx context.Context) ([]CombinedScanTask, error) {
+	tasks, err := scan.PlanFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return scan.combineTasks(tasks), nil
+}
+
+// combineTasks splits and bin packs the planned tasks for PlanTasks.
+func (scan *Scan) combineTasks(tasks []FileScanTask) []CombinedScanTask {
+	props := scan.metadata.Properties()
+	getProp := func(key string, defVal int) int64 {
+		return int64(scan.options.GetInt(key, props.GetInt(key, defVal)))
+	}
+
+	var (
+		targetSize   = getProp(SplitSizeKey, SplitSizeDefault)
+		lookback     = getProp(SplitLookbackKey, SplitLookbackDefault)
+		openFileCost = getProp(SplitOpenFileCostKey, SplitOpenFileCostDefault)
+	)
+
+	splits := func(yield func(FileScanTask) bool) {
+		for _, t := range tasks {
+			for _, split := range splitTask(t, targetSize) {
+				if !yield(split) {
+					return
+				}
+			}
+		}
+	}
+
+	weight := func(t FileScanTask) int64 {
+		size := t.Length
+		for _, d := range t.DeleteFiles {
+			size += d.FileSizeBytes()
+		}
+
+		return max(size, openFileCost)
+	}
+
+	result := make([]CombinedScanTask, 0)
+	for bin := range internal.PackingIterator(splits, targetSize, int(lookback), weight, false) {
+		result = append(result, CombinedScanTask{Tasks: bin})
+	}
+
+	return result
+}
+
 // ToArrowRecords returns the arrow schema of the expected records and an interator
 // that can be used with a range expression to read the records as they are available.
 // If an error is encountered, during the planning and setup then this will return the
@@ -481,25 +691,48 @@ func (scan *Scan) ToArrowRecords(ctx context.Context) (*arrow.Schema, iter.Seq2[
 		return nil, nil, err
 	}
 
-	var boundFilter iceberg.BooleanExpression
+	return scan.ReadTasks(ctx, tasks)
+}
+
+// ReadTasks is like ToArrowRecords, but reads the given tasks rather than
+// planning them. This allows reading the tasks produced by PlanFiles or
+// PlanTasks separately, for instance distributing them across workers. Only
+// the row groups starting within the byte range of each task are read.
+func (scan *Scan) ReadTasks(ctx context.Context, tasks []FileScanTask) (*arrow.Schema, iter.Seq2[arrow.Record, error], error) {
+	as, err := scan.newArrowScan(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return as.GetRecords(ctx, tasks)
+}
+
+// newArrowScan returns the arrowScan reading the projection of the scan and
+// applying its row filter and limit.
+func (scan *Scan) newArrowScan(ctx context.Context) (*arrowScan, error) {
+	var (
+		boundFilter iceberg.BooleanExpression
+		err         error
+	)
+
 	if scan.rowFilter != nil {
 		boundFilter, err = iceberg.BindExpr(scan.metadata.CurrentSchema(), scan.rowFilter, scan.caseSensitive)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 	}
 
 	schema, err := scan.Projection()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	fs, err := scan.ioF(ctx)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return (&arrowScan{
+	return &arrowScan{
 		metadata:        scan.metadata,
 		fs:              fs,
 		projectedSchema: schema,
@@ -508,7 +741,7 @@ func (scan *Scan) ToArrowRecords(ctx context.Context) (*arrow.Schema, iter.Seq2[
 		rowLimit:        scan.limit,
 		options:         scan.options,
 		concurrency:     scan.concurrency,
-	}).GetRecords(ctx, tasks)
+	}, nil
 }
 
 // ToArrowTable calls ToArrowRecords and then gathers all of the records together
@@ -519,6 +752,10 @@ func (scan *Scan) ToArrowTable(ctx context.Context) (arrow.Table, error) {
 		return nil, err
 	}
 
+	return collectRecords(schema, itr)
+}
+
+func collectRecords(schema *arrow.Schema, itr iter.Seq2[arrow.Record, error]) (arrow.Table, error) {
 	records := make([]arrow.Record, 0)
 	for rec, err := range itr {
 		if err != nil {
@@ -0,0 +1,551 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table/internal"
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEntry(t *testing.T, spec iceberg.PartitionSpec, content iceberg.ManifestEntryContent, path string, partition map[int]any, seqNum int64) iceberg.ManifestEntry {
+	bldr, err := iceberg.NewDataFileBuilder(spec, content, path, iceberg.ParquetFile, partition, 10, 100)
+	require.NoError(t, err)
+	if content == iceberg.EntryContentEqDeletes {
+		bldr.EqualityFieldIDs([]int{1})
+	}
+
+	snapID := int64(1)
+
+	return iceberg.NewManifestEntry(iceberg.EntryStatusADDED, &snapID, &seqNum, &seqNum, bldr.Build())
+}
+
+func TestMatchEqualityDeletesToData(t *testing.T) {
+	spec := iceberg.NewPartitionSpecID(1, iceberg.PartitionField{
+		SourceID: 1, FieldID: 1000, Transform: iceberg.IdentityTransform{}, Name: "x",
+	})
+	specs := []iceberg.PartitionSpec{*iceberg.UnpartitionedSpec, spec}
+
+	dataEntry := newTestEntry(t, spec, iceberg.EntryContentData, "data.parquet", map[int]any{1000: int32(1)}, 2)
+	deletes := []iceberg.ManifestEntry{
+		newTestEntry(t, spec, iceberg.EntryContentEqDeletes, "older.parquet", map[int]any{1000: int32(1)}, 1),
+		newTestEntry(t, spec, iceberg.EntryContentEqDeletes, "same-seq.parquet", map[int]any{1000: int32(1)}, 2),
+		newTestEntry(t, spec, iceberg.EntryContentEqDeletes, "other-partition.parquet", map[int]any{1000: int32(2)}, 3),
+		newTestEntry(t, spec, iceberg.EntryContentEqDeletes, "same-partition.parquet", map[int]any{1000: int32(1)}, 3),
+		newTestEntry(t, *iceberg.UnpartitionedSpec, iceberg.EntryContentEqDeletes, "global.parquet", nil, 4),
+	}
+
+	matched := matchEqualityDeletesToData(dataEntry, deletes, specs)
+	paths := make([]string, len(matched))
+	for i, df := range matched {
+		paths[i] = df.FilePath()
+	}
+
+	assert.Equal(t, []string{"same-partition.parquet", "global.parquet"}, paths)
+}
+
+func TestProcessEqualityDeletes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "data", Type: iceberg.PrimitiveTypes.String},
+		iceberg.NestedField{ID: 3, Name: "nested", Type: &iceberg.StructType{
+			FieldList: []iceberg.NestedField{
+				{ID: 4, Name: "key", Type: iceberg.PrimitiveTypes.String},
+			},
+		}},
+	)
+
+	arrSchema, err := SchemaToArrowSchema(sc, nil, true, false)
+	require.NoError(t, err)
+
+	rec, _, err := array.RecordFromJSON(mem, arrSchema, strings.NewReader(`[
+		{"id": 1, "data": "a", "nested": {"key": "x"}},
+		{"id": 2, "data": "b", "nested": {"key": "y"}},
+		{"id": 3, "data": null, "nested": {"key": "z"}},
+		{"id": 4, "data": "d", "nested": {"key": null}}
+	]`))
+	require.NoError(t, err)
+
+	byID := &equalityDeletes{fieldIDs: []int{1}, keys: set[string]{}}
+	idCol := rec.Column(0)
+	for _, row := range []int{1} {
+		byID.keys[equalityKey([]arrow.Array{idCol}, row)] = struct{}{}
+	}
+
+	byDataAndKey := &equalityDeletes{fieldIDs: []int{2, 4}, keys: set[string]{}}
+	cols, err := columnsForFieldIDs(sc, rec, byDataAndKey.fieldIDs)
+	require.NoError(t, err)
+	for _, row := range []int{2, 3} {
+		byDataAndKey.keys[equalityKey(cols, row)] = struct{}{}
+	}
+	// null values only match nulls
+	byDataAndKey.keys[equalityKey([]arrow.Array{nil, nil}, 0)] = struct{}{}
+
+	ctx := context.Background()
+	out, err := processEqualityDeletes(ctx, sc, []*equalityDeletes{byID, byDataAndKey})(rec)
+	require.NoError(t, err)
+	defer out.Release()
+
+	require.EqualValues(t, 1, out.NumRows())
+	assert.Equal(t, int64(1), out.Column(0).(*array.Int64).Value(0))
+}
+
+func TestSplitTask(t *testing.T) {
+	newTask := func(format iceberg.FileFormat, offsets []int64) FileScanTask {
+		bldr, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentData,
+			"data", format, nil, 10, 100)
+		require.NoError(t, err)
+		if offsets != nil {
+			bldr.SplitOffsets(offsets)
+		}
+
+		return FileScanTask{File: bldr.Build(), Length: 100}
+	}
+
+	ranges := func(tasks []FileScanTask) [][2]int64 {
+		out := make([][2]int64, len(tasks))
+		for i, tk := range tasks {
+			out[i] = [2]int64{tk.Start, tk.Length}
+		}
+
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		format   iceberg.FileFormat
+		offsets  []int64
+		target   int64
+		expected [][2]int64
+	}{
+		{"offsets", iceberg.ParquetFile, []int64{4, 30, 60, 90}, 50,
+			[][2]int64{{4, 26}, {30, 30}, {60, 40}}},
+		{"offsets larger target", iceberg.ParquetFile, []int64{4, 30, 60, 90}, 200,
+			[][2]int64{{4, 96}}},
+		{"no offsets", iceberg.ParquetFile, nil, 40,
+			[][2]int64{{0, 40}, {40, 40}, {80, 20}}},
+		{"unsorted offsets", iceberg.ParquetFile, []int64{60, 4}, 40,
+			[][2]int64{{0, 40}, {40, 40}, {80, 20}}},
+		{"offsets past file end", iceberg.ParquetFile, []int64{4, 120}, 40,
+			[][2]int64{{0, 40}, {40, 40}, {80, 20}}},
+		{"not splittable", iceberg.AvroFile, []int64{4, 30}, 10,
+			[][2]int64{{0, 100}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ranges(splitTask(newTask(tt.format, tt.offsets), tt.target)))
+		})
+	}
+}
+
+func TestScanAvroDataFile(t *testing.T) {
+	location := t.TempDir()
+	fname := filepath.Join(location, "data.avro")
+
+	f, err := os.Create(fname)
+	require.NoError(t, err)
+
+	enc, err := ocf.NewEncoder(`{"type": "record", "name": "r", "fields": [
+		{"name": "id", "type": "long", "field-id": 1},
+		{"name": "data", "type": ["null", "string"], "field-id": 2}
+	]}`, f, ocf.WithSchemaMarshaler(ocf.FullSchemaMarshaler))
+	require.NoError(t, err)
+	for i, data := range []any{map[string]any{"string": "a"}, nil, map[string]any{"string": "c"}} {
+		require.NoError(t, enc.Encode(map[string]any{"id": int64(i), "data": data}))
+	}
+	require.NoError(t, enc.Close())
+	require.NoError(t, f.Close())
+
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "data", Type: iceberg.PrimitiveTypes.String})
+	meta, err := NewMetadata(sc, iceberg.UnpartitionedSpec, UnsortedSortOrder, location, nil)
+	require.NoError(t, err)
+
+	fs := iceio.LocalFS{}
+	tbl := New(Identifier{"db", "avro"}, meta, filepath.Join(location, "metadata.json"),
+		func(context.Context) (iceio.IO, error) { return fs, nil }, nil)
+
+	info, err := os.Stat(fname)
+	require.NoError(t, err)
+	bldr, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentData,
+		fname, iceberg.AvroFile, nil, 3, info.Size())
+	require.NoError(t, err)
+
+	tx := tbl.NewTransaction()
+	updater := tx.updateSnapshot(fs, nil).fastAppend()
+	updater.appendDataFile(bldr.Build())
+	updates, reqs, err := updater.commit()
+	require.NoError(t, err)
+	require.NoError(t, tx.apply(updates, reqs))
+
+	scan, err := tx.Scan(WithRowFilter(iceberg.NotEqualTo(iceberg.Reference("id"), int64(0))),
+		WithSelectedFields("data"))
+	require.NoError(t, err)
+
+	result, err := scan.ToArrowTable(context.Background())
+	require.NoError(t, err)
+	defer result.Release()
+
+	require.EqualValues(t, 2, result.NumRows())
+	assert.Equal(t, "data", result.Schema().Field(0).Name)
+	assert.Equal(t, `[(null) "c"]`, result.Column(0).Data().Chunk(0).String())
+}
+
+func TestScanTimestampNs(t *testing.T) {
+	location := t.TempDir()
+	fname := filepath.Join(location, "data.avro")
+
+	f, err := os.Create(fname)
+	require.NoError(t, err)
+
+	enc, err := ocf.NewEncoder(`{"type": "record", "name": "r", "fields": [
+		{"name": "id", "type": "long", "field-id": 1},
+		{"name": "ts", "type": {"type": "long", "logicalType": "timestamp-nanos", "adjust-to-utc": true}, "field-id": 2}
+	]}`, f, ocf.WithSchemaMarshaler(ocf.FullSchemaMarshaler))
+	require.NoError(t, err)
+	for i := range 2 {
+		require.NoError(t, enc.Encode(map[string]any{"id": int64(i), "ts": int64(1_000_000_001 + i)}))
+	}
+	require.NoError(t, enc.Close())
+	require.NoError(t, f.Close())
+
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "ts", Type: iceberg.PrimitiveTypes.TimestampTzNs, Required: true})
+	meta, err := NewMetadata(sc, iceberg.UnpartitionedSpec, UnsortedSortOrder, location,
+		iceberg.Properties{"format-version": "3"})
+	require.NoError(t, err)
+
+	fs := iceio.LocalFS{}
+	tbl := New(Identifier{"db", "ts_ns"}, meta, filepath.Join(location, "metadata.json"),
+		func(context.Context) (iceio.IO, error) { return fs, nil }, nil)
+
+	info, err := os.Stat(fname)
+	require.NoError(t, err)
+	bldr, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentData,
+		fname, iceberg.AvroFile, nil, 2, info.Size())
+	require.NoError(t, err)
+
+	tx := tbl.NewTransaction()
+	updater := tx.updateSnapshot(fs, nil).fastAppend()
+	updater.appendDataFile(bldr.Build())
+	updates, reqs, err := updater.commit()
+	require.NoError(t, err)
+	require.NoError(t, tx.apply(updates, reqs))
+
+	scan, err := tx.Scan()
+	require.NoError(t, err)
+
+	result, err := scan.ToArrowTable(context.Background())
+	require.NoError(t, err)
+	defer result.Release()
+
+	require.EqualValues(t, 2, result.NumRows())
+	assert.True(t, arrow.TypeEqual(arrow.FixedWidthTypes.Timestamp_ns, result.Schema().Field(1).Type))
+	assert.Equal(t, "[1000000001 1000000002]", fmt.Sprint(
+		result.Column(1).Data().Chunk(0).(*array.Timestamp).TimestampValues()))
+}
+
+func TestScanParquetRowSelection(t *testing.T) {
+	location := t.TempDir()
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "data", Type: iceberg.PrimitiveTypes.String, Required: true})
+	meta, err := NewMetadata(sc, iceberg.UnpartitionedSpec, UnsortedSortOrder, location, iceberg.Properties{
+		ParquetPageSizeBytesKey:                          "256",
+		ParquetPageRowLimitKey:                           "100",
+		ParquetBloomFilterColumnEnabledKeyPrefix + ".id": "true",
+		ParquetPageIndexEnabledKey:                       "true",
+	})
+	require.NoError(t, err)
+
+	fs := iceio.LocalFS{}
+	tbl := New(Identifier{"db", "pruning"}, meta, filepath.Join(location, "metadata.json"),
+		func(context.Context) (iceio.IO, error) { return fs, nil }, nil)
+
+	arrSc, err := SchemaToArrowSchema(sc, nil, false, false)
+	require.NoError(t, err)
+
+	// even ids from 0 to 1998
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, arrSc)
+	defer bldr.Release()
+	for i := range 1000 {
+		bldr.Field(0).(*array.Int64Builder).Append(int64(i * 2))
+		bldr.Field(1).(*array.StringBuilder).Append(fmt.Sprintf("row%d", i))
+	}
+	rec := bldr.NewRecord()
+	defer rec.Release()
+
+	arrTbl := array.NewTableFromRecords(arrSc, []arrow.Record{rec})
+	defer arrTbl.Release()
+
+	ctx := context.Background()
+	tx := tbl.NewTransaction()
+	require.NoError(t, tx.AppendTable(ctx, arrTbl, 1000, nil))
+
+	tests := []struct {
+		name     string
+		filter   iceberg.BooleanExpression
+		expected []int64
+		// bounds of the number of rows read from the file
+		minRead, maxRead int64
+	}{
+		{"missing id", iceberg.EqualTo(iceberg.Reference("id"), int64(501)), nil, 0, 0},
+		{"missing ids", iceberg.IsIn(iceberg.Reference("id"), int64(3), int64(1001)), nil, 0, 0},
+		{"point lookup", iceberg.EqualTo(iceberg.Reference("id"), int64(500)), []int64{500}, 1, 200},
+		{"range", iceberg.LessThan(iceberg.Reference("id"), int64(10)),
+			[]int64{0, 2, 4, 6, 8}, 5, 200},
+		{"ranges", iceberg.NewOr(iceberg.LessThan(iceberg.Reference("id"), int64(4)),
+			iceberg.GreaterThanEqual(iceberg.Reference("id"), int64(1996))),
+			[]int64{0, 2, 1996, 1998}, 4, 400},
+		{"all rows", iceberg.GreaterThanEqual(iceberg.Reference("id"), int64(0)), nil, 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scan, err := tx.Scan(WithRowFilter(tt.filter), WithSelectedFields("id"))
+			require.NoError(t, err)
+
+			tasks, err := scan.PlanFiles(ctx)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+
+			bound, err := iceberg.BindExpr(sc, tt.filter, true)
+			require.NoError(t, err)
+			selectRows, err := newParquetRowSelector(sc, bound)
+			require.NoError(t, err)
+
+			src, err := internal.GetFile(ctx, fs, tasks[0].File, false)
+			require.NoError(t, err)
+			rdr, err := src.GetReader(ctx)
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			recs, err := rdr.GetRecords(ctx, []int{0}, selectRows)
+			require.NoError(t, err)
+			defer recs.Release()
+
+			var read []int64
+			for recs.Next() {
+				read = append(read, recs.Record().Column(0).(*array.Int64).Int64Values()...)
+			}
+			if err := recs.Err(); !errors.Is(err, io.EOF) {
+				require.NoError(t, err)
+			}
+			assert.GreaterOrEqual(t, int64(len(read)), tt.minRead)
+			assert.LessOrEqual(t, int64(len(read)), tt.maxRead)
+			assert.True(t, slices.IsSorted(read))
+			for _, id := range tt.expected {
+				assert.Contains(t, read, id)
+			}
+
+			result, err := scan.ToArrowTable(ctx)
+			require.NoError(t, err)
+			defer result.Release()
+
+			if tt.expected == nil {
+				assert.EqualValues(t, tt.minRead, result.NumRows())
+
+				return
+			}
+
+			var ids []int64
+			for _, chunk := range result.Column(0).Data().Chunks() {
+				ids = append(ids, chunk.(*array.Int64).Int64Values()...)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+// writeAvroFile writes the rows to an avro data file with the schema,
+// returning its size.
+func writeAvroFile(t *testing.T, fname, schema string, rows []map[string]any) int64 {
+	f, err := os.Create(fname)
+	require.NoError(t, err)
+
+	enc, err := ocf.NewEncoder(schema, f, ocf.WithSchemaMarshaler(ocf.FullSchemaMarshaler))
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, enc.Encode(row))
+	}
+	require.NoError(t, enc.Close())
+	require.NoError(t, f.Close())
+
+	info, err := os.Stat(fname)
+	require.NoError(t, err)
+
+	return info.Size()
+}
+
+func TestScanEqualityDeletes(t *testing.T) {
+	location := t.TempDir()
+
+	// the data file was written before id was promoted to long and val to
+	// double
+	dataFile := filepath.Join(location, "data.avro")
+	dataSize := writeAvroFile(t, dataFile, `{"type": "record", "name": "r", "fields": [
+		{"name": "id", "type": "int", "field-id": 1},
+		{"name": "val", "type": "float", "field-id": 2},
+		{"name": "data", "type": "string", "field-id": 3}
+	]}`, []map[string]any{
+		{"id": int32(1), "val": float32(0.1), "data": "a"},
+		{"id": int32(2), "val": float32(0.2), "data": "b"},
+		{"id": int32(3), "val": float32(0.3), "data": "c"},
+		{"id": int32(4), "val": float32(0.4), "data": "d"},
+	})
+
+	byID := filepath.Join(location, "id-deletes.avro")
+	byIDSize := writeAvroFile(t, byID, `{"type": "record", "name": "r", "fields": [
+		{"name": "id", "type": "long", "field-id": 1}
+	]}`, []map[string]any{{"id": int64(2)}, {"id": int64(5)}})
+
+	byVal := filepath.Join(location, "val-deletes.avro")
+	byValSize := writeAvroFile(t, byVal, `{"type": "record", "name": "r", "fields": [
+		{"name": "val", "type": "double", "field-id": 2}
+	]}`, []map[string]any{{"val": float64(float32(0.3))}, {"val": 0.4}})
+
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "val", Type: iceberg.PrimitiveTypes.Float64, Required: true},
+		iceberg.NestedField{ID: 3, Name: "data", Type: iceberg.PrimitiveTypes.String, Required: true})
+	meta, err := NewMetadata(sc, iceberg.UnpartitionedSpec, UnsortedSortOrder, location,
+		iceberg.Properties{"format-version": "2"})
+	require.NoError(t, err)
+
+	fs := iceio.LocalFS{}
+	tbl := New(Identifier{"db", "eq_deletes"}, meta, filepath.Join(location, "metadata.json"),
+		func(context.Context) (iceio.IO, error) { return fs, nil }, nil)
+
+	tx := tbl.NewTransaction()
+	appendFiles := func(files ...iceberg.DataFile) {
+		updater := tx.updateSnapshot(fs, nil).fastAppend()
+		for _, df := range files {
+			updater.appendDataFile(df)
+		}
+		updates, reqs, err := updater.commit()
+		require.NoError(t, err)
+		require.NoError(t, tx.apply(updates, reqs))
+	}
+
+	data, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentData,
+		dataFile, iceberg.AvroFile, nil, 4, dataSize)
+	require.NoError(t, err)
+	appendFiles(data.Build())
+
+	idDeletes, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentEqDeletes,
+		byID, iceberg.AvroFile, nil, 2, byIDSize)
+	require.NoError(t, err)
+	valDeletes, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentEqDeletes,
+		byVal, iceberg.AvroFile, nil, 2, byValSize)
+	require.NoError(t, err)
+	appendFiles(idDeletes.EqualityFieldIDs([]int{1}).Build(), valDeletes.EqualityFieldIDs([]int{2}).Build())
+
+	scan, err := tx.Scan()
+	require.NoError(t, err)
+
+	result, err := scan.ToArrowTable(context.Background())
+	require.NoError(t, err)
+	defer result.Release()
+
+	// the promoted float 0.3 matches the delete of its double value, unlike
+	// 0.4 which isn't exactly representable as a float
+	require.EqualValues(t, 2, result.NumRows())
+	assert.Equal(t, `["a" "d"]`, result.Column(2).Data().Chunk(0).String())
+}
+
+func TestScanAvroPositionDeletes(t *testing.T) {
+	location := t.TempDir()
+
+	dataFile := filepath.Join(location, "data.avro")
+	dataSize := writeAvroFile(t, dataFile, `{"type": "record", "name": "r", "fields": [
+		{"name": "id", "type": "long", "field-id": 1}
+	]}`, []map[string]any{{"id": int64(1)}, {"id": int64(2)}, {"id": int64(3)}, {"id": int64(4)}})
+
+	// avro position deletes are read as a plain string file_path column,
+	// unlike the dictionary encoded column read from parquet
+	deleteFile := filepath.Join(location, "pos-deletes.avro")
+	deleteSize := writeAvroFile(t, deleteFile, `{"type": "record", "name": "r", "fields": [
+		{"name": "file_path", "type": "string", "field-id": 2147483546},
+		{"name": "pos", "type": "long", "field-id": 2147483545}
+	]}`, []map[string]any{
+		{"file_path": dataFile, "pos": int64(1)},
+		{"file_path": dataFile, "pos": int64(3)},
+		{"file_path": filepath.Join(location, "other.avro"), "pos": int64(0)},
+	})
+
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	meta, err := NewMetadata(sc, iceberg.UnpartitionedSpec, UnsortedSortOrder, location,
+		iceberg.Properties{"format-version": "2"})
+	require.NoError(t, err)
+
+	fs := iceio.LocalFS{}
+	tbl := New(Identifier{"db", "pos_deletes"}, meta, filepath.Join(location, "metadata.json"),
+		func(context.Context) (iceio.IO, error) { return fs, nil }, nil)
+
+	tx := tbl.NewTransaction()
+	appendFile := func(df iceberg.DataFile) {
+		updater := tx.updateSnapshot(fs, nil).fastAppend()
+		updater.appendDataFile(df)
+		updates, reqs, err := updater.commit()
+		require.NoError(t, err)
+		require.NoError(t, tx.apply(updates, reqs))
+	}
+
+	data, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentData,
+		dataFile, iceberg.AvroFile, nil, 4, dataSize)
+	require.NoError(t, err)
+	appendFile(data.Build())
+
+	deletes, err := iceberg.NewDataFileBuilder(*iceberg.UnpartitionedSpec, iceberg.EntryContentPosDeletes,
+		deleteFile, iceberg.AvroFile, nil, 3, deleteSize)
+	require.NoError(t, err)
+	appendFile(deletes.Build())
+
+	scan, err := tx.Scan()
+	require.NoError(t, err)
+
+	result, err := scan.ToArrowTable(context.Background())
+	require.NoError(t, err)
+	defer result.Release()
+
+	require.EqualValues(t, 2, result.NumRows())
+	assert.Equal(t, `[1 3]`, result.Column(0).Data().Chunk(0).String())
+}
@@ -22,6 +22,7 @@ import (
 	"io"
 	"maps"
 	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -120,11 +121,15 @@ func (of *overwriteFiles) existingManifests() ([]iceberg.ManifestFile, error) {
 	// determine if there are any existing manifest files
 	existingFiles := make([]iceberg.ManifestFile, 0)
 
-	snap := of.base.txn.meta.currentSnapshot()
-	if snap == nil {
+	if of.base.parentSnapshotID <= 0 {
 		return existingFiles, nil
 	}
 
+	snap, err := of.base.txn.meta.SnapshotByID(of.base.parentSnapshotID)
+	if err != nil {
+		return existingFiles, err
+	}
+
 	manifestList, err := snap.Manifests(of.base.io)
 	if err != nil {
 		return existingFiles, err
@@ -423,35 +428,64 @@ type snapshotProducer struct {
 	manifestCount    atomic.Int32
 	deletedFiles     map[string]iceberg.DataFile
 	snapshotProps    iceberg.Properties
+
+	// branch is the branch the snapshot is committed to, unless stageOnly
+	// is set in which case the snapshot is only added to the table.
+	branch    string
+	stageOnly bool
+
+	// attempt is the number of times the commit was retried, and written
+	// the manifest files written by the current attempt
+	attempt   int
+	writtenMx sync.Mutex
+	written   []string
+
+	// conflictFilter selects the rows the changes of the producer depend on.
+	// When set, files that may contain such rows and were added by commits
+	// concurrent with the transaction fail validation when it is retried.
+	conflictFilter iceberg.BooleanExpression
+	// referencedFiles are the data files that must still be live for the
+	// delete files added by the producer to apply.
+	referencedFiles []string
 }
 
 func createSnapshotProducer(op Operation, txn *Transaction, fs iceio.WriteFileIO, commitUUID *uuid.UUID, snapshotProps iceberg.Properties) *snapshotProducer {
-	var (
-		commit         uuid.UUID
-		parentSnapshot int64 = -1
-	)
-
+	var commit uuid.UUID
 	if commitUUID == nil {
 		commit = uuid.New()
 	} else {
 		commit = *commitUUID
 	}
 
-	if snap := txn.meta.currentSnapshot(); snap != nil {
-		parentSnapshot = snap.SnapshotID
+	// with write-audit-publish, the snapshots of writes identified by a
+	// wap.id are staged to be published later
+	stageOnly := txn.meta.props.GetBool(WriteWapEnabledKey, WriteWapEnabledDefault) &&
+		snapshotProps[WapIDKey] != ""
+
+	sp := &snapshotProducer{
+		commitUuid:    commit,
+		io:            fs,
+		txn:           txn,
+		op:            op,
+		snapshotID:    txn.meta.newSnapshotID(),
+		stageOnly:     stageOnly,
+		addedFiles:    []iceberg.DataFile{},
+		deletedFiles:  make(map[string]iceberg.DataFile),
+		snapshotProps: snapshotProps,
 	}
 
-	return &snapshotProducer{
-		commitUuid:       commit,
-		io:               fs,
-		txn:              txn,
-		op:               op,
-		snapshotID:       txn.meta.newSnapshotID(),
-		parentSnapshotID: parentSnapshot,
-		addedFiles:       []iceberg.DataFile{},
-		deletedFiles:     make(map[string]iceberg.DataFile),
-		snapshotProps:    snapshotProps,
+	return sp.toBranch(txn.branch)
+}
+
+// toBranch makes the producer commit the snapshot to the branch, on top of
+// its head.
+func (sp *snapshotProducer) toBranch(branch string) *snapshotProducer {
+	sp.branch, sp.parentSnapshotID = branch, -1
+	if snap := sp.txn.meta.branchSnapshot(branch); snap != nil {
+		sp.parentSnapshotID = snap.SnapshotID
 	}
+
+	return sp
 }
 
 func (sp *snapshotProducer) spec(id int) iceberg.PartitionSpec {
@@ -474,6 +508,18 @@ func (sp *snapshotProducer) deleteDataFile(df iceberg.DataFile) *snapshotProduce
 	return sp
 }
 
+func (sp *snapshotProducer) referenceDataFile(df iceberg.DataFile) *snapshotProducer {
+	sp.referencedFiles = append(sp.referencedFiles, df.FilePath())
+
+	return sp
+}
+
+func (sp *snapshotProducer) conflictDetectionFilter(filter iceberg.BooleanExpression) *snapshotProducer {
+	sp.conflictFilter = filter
+
+	return sp
+}
+
 func (sp *snapshotProducer) newManifestWriter(spec iceberg.PartitionSpec) (*iceberg.ManifestWriter, string, *internal.CountingWriter, error) {
 	out, path, err := sp.newManifestOutput()
 	if err != nil {
@@ -503,10 +549,205 @@ func (sp *snapshotProducer) newManifestOutput() (io.WriteCloser, string, error)
 	if err != nil {
 		return nil, "", fmt.Errorf("could not create manifest file: %w", err)
 	}
+	sp.recordWritten(filepath)
 
 	return f, filepath, nil
 }
 
+func (sp *snapshotProducer) recordWritten(path string) {
+	sp.writtenMx.Lock()
+	defer sp.writtenMx.Unlock()
+
+	sp.written = append(sp.written, path)
+}
+
+// deleteWritten deletes the manifest lists and manifests written by the
+// current attempt, once it is abandoned.
+func (sp *snapshotProducer) deleteWritten() {
+	sp.writtenMx.Lock()
+	defer sp.writtenMx.Unlock()
+
+	for _, path := range sp.written {
+		_ = sp.io.Remove(path)
+	}
+	sp.written = nil
+}
+
+// rebase prepares the producer to be committed again on top of the refreshed
+// transaction metadata after its previous attempt conflicted with the given
+// concurrent snapshots. The manifests written by the previous attempt are
+// removed, while the data files it added are reused as is.
+func (sp *snapshotProducer) rebase(attempt int, concurrent []*Snapshot) error {
+	sp.deleteWritten()
+	sp.attempt = attempt
+
+	sp.toBranch(sp.branch)
+
+	if _, err := sp.txn.meta.SnapshotByID(sp.snapshotID); err == nil {
+		sp.snapshotID = sp.txn.meta.newSnapshotID()
+	}
+
+	if err := sp.validateConflicts(concurrent); err != nil {
+		return err
+	}
+
+	return sp.validateLiveFiles()
+}
+
+// validateLiveFiles checks that every data file removed or referenced by
+// the producer is still live in its parent snapshot.
+func (sp *snapshotProducer) validateLiveFiles() error {
+	if len(sp.deletedFiles) == 0 && len(sp.referencedFiles) == 0 {
+		return nil
+	}
+
+	missing := make(map[string]struct{})
+	for path := range sp.deletedFiles {
+		missing[path] = struct{}{}
+	}
+	for _, path := range sp.referencedFiles {
+		missing[path] = struct{}{}
+	}
+
+	if parent, err := sp.txn.meta.SnapshotByID(sp.parentSnapshotID); err == nil {
+		manifests, err := parent.Manifests(sp.io)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range manifests {
+			entries, err := sp.fetchManifestEntry(m, true)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range entries {
+				delete(missing, e.DataFile().FilePath())
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: cannot commit %s, data files were removed concurrently: %v",
+			ErrValidationFailed, sp.op, slices.Sorted(maps.Keys(missing)))
+	}
+
+	return nil
+}
+
+// validateConflicts checks that none of the concurrent snapshots added files
+// that may contain rows matching the conflict filter of the producer. Delete
+// files always conflict, data files only with serializable isolation.
+func (sp *snapshotProducer) validateConflicts(concurrent []*Snapshot) error {
+	if sp.conflictFilter == nil || len(concurrent) == 0 {
+		return nil
+	}
+
+	mightMatch, err := sp.conflictEvaluator()
+	if err != nil {
+		return err
+	}
+
+	checkData := sp.txn.isolation == IsolationSerializable
+	for _, snap := range concurrent {
+		manifests, err := snap.Manifests(sp.io)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range manifests {
+			if m.SnapshotID() != snap.SnapshotID ||
+				(m.ManifestContent() == iceberg.ManifestContentData && !checkData) {
+				continue
+			}
+
+			entries, err := sp.fetchManifestEntry(m, true)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range entries {
+				if e.Status() != iceberg.EntryStatusADDED || e.SnapshotID() != snap.SnapshotID {
+					continue
+				}
+
+				df := e.DataFile()
+				matches, err := mightMatch(df)
+				if err != nil {
+					return err
+				}
+
+				if matches {
+					return fmt.Errorf("%w: found conflicting %s file %s added by snapshot %d for filter %s",
+						ErrValidationFailed, df.ContentType(), df.FilePath(), snap.SnapshotID, sp.conflictFilter)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// conflictEvaluator returns a function reporting whether a file may contain
+// rows matching the conflict filter, based on its partition and, for data
+// files, its column metrics.
+func (sp *snapshotProducer) conflictEvaluator() (func(iceberg.DataFile) (bool, error), error) {
+	schema := sp.txn.meta.CurrentSchema()
+	metricsEval, err := newInclusiveMetricsEvaluator(schema, sp.conflictFilter, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	partitionEvals := make(map[int]func(iceberg.DataFile) (bool, error))
+	partitionEval := func(specID int) (func(iceberg.DataFile) (bool, error), error) {
+		if eval, ok := partitionEvals[specID]; ok {
+			return eval, nil
+		}
+
+		spec, err := sp.txn.meta.GetSpecByID(specID)
+		if err != nil {
+			return nil, err
+		}
+
+		partFilter, err := newInclusiveProjection(schema, *spec, true)(sp.conflictFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		partType := spec.PartitionType(schema)
+		fn, err := iceberg.ExpressionEvaluator(iceberg.NewSchema(0, partType.FieldList...), partFilter, true)
+		if err != nil {
+			return nil, err
+		}
+
+		partitionEvals[specID] = func(df iceberg.DataFile) (bool, error) {
+			return fn(getPartitionRecord(df, partType))
+		}
+
+		return partitionEvals[specID], nil
+	}
+
+	return func(df iceberg.DataFile) (bool, error) {
+		eval, err := partitionEval(int(df.SpecID()))
+		if err != nil {
+			return false, err
+		}
+
+		matches, err := eval(df)
+		if err != nil || !matches {
+			return false, err
+		}
+
+		// delete file metrics do not generally cover the filtered data
+		// columns, so only their partition is checked
+		if df.ContentType() != iceberg.EntryContentData {
+			return true, nil
+		}
+
+		return metricsEval(df)
+	}, nil
+}
+
 func (sp *snapshotProducer) fetchManifestEntry(m iceberg.ManifestFile, discardDeleted bool) ([]iceberg.ManifestEntry, error) {
 	return m.FetchEntries(sp.io, discardDeleted)
 }
@@ -514,9 +755,22 @@ func (sp *snapshotProducer) fetchManifestEntry(m iceberg.ManifestFile, discardDe
 func (sp *snapshotProducer) manifests() ([]iceberg.ManifestFile, error) {
 	var g errgroup.Group
 
-	results := [...][]iceberg.ManifestFile{nil, nil, nil}
+	results := [...][]iceberg.ManifestFile{nil, nil, nil, nil}
+
+	addedDataFiles := make([]iceberg.DataFile, 0, len(sp.addedFiles))
+	addedDeleteFiles := make(map[int][]iceberg.DataFile)
+	for _, df := range sp.addedFiles {
+		if df.ContentType() == iceberg.EntryContentData {
+			addedDataFiles = append(addedDataFiles, df)
+
+			continue
+		}
+
+		specID := int(df.SpecID())
+		addedDeleteFiles[specID] = append(addedDeleteFiles[specID], df)
+	}
 
-	if len(sp.addedFiles) > 0 {
+	if len(addedDataFiles) > 0 {
 		g.Go(func() error {
 			out, path, err := sp.newManifestOutput()
 			if err != nil {
@@ -533,7 +787,7 @@ func (sp *snapshotProducer) manifests() ([]iceberg.ManifestFile, error) {
 				return err
 			}
 
-			for _, df := range sp.addedFiles {
+			for _, df := range addedDataFiles {
 				err := wr.Add(iceberg.NewManifestEntry(iceberg.EntryStatusADDED, &sp.snapshotID,
 					nil, nil, df))
 				if err != nil {
@@ -555,6 +809,46 @@ func (sp *snapshotProducer) manifests() ([]iceberg.ManifestFile, error) {
 		})
 	}
 
+	if len(addedDeleteFiles) > 0 {
+		g.Go(func() error {
+			for specID, files := range addedDeleteFiles {
+				out, path, err := sp.newManifestOutput()
+				if err != nil {
+					return err
+				}
+				defer out.Close()
+
+				counter := &internal.CountingWriter{W: out}
+				wr, err := iceberg.NewDeleteManifestWriter(sp.txn.meta.formatVersion, counter,
+					sp.spec(specID), sp.txn.meta.CurrentSchema(), sp.snapshotID)
+				if err != nil {
+					return err
+				}
+
+				for _, df := range files {
+					err := wr.Add(iceberg.NewManifestEntry(iceberg.EntryStatusADDED, &sp.snapshotID,
+						nil, nil, df))
+					if err != nil {
+						return err
+					}
+				}
+
+				// close the writer to force a flush and ensure counter.Count is accurate
+				if err := wr.Close(); err != nil {
+					return err
+				}
+
+				mf, err := wr.ToManifestFile(path, counter.Count)
+				if err != nil {
+					return err
+				}
+				results[3] = append(results[3], mf)
+			}
+
+			return nil
+		})
+	}
+
 	deleted, err := sp.deletedEntries()
 	if err != nil {
 		return nil, err
@@ -603,7 +897,7 @@ func (sp *snapshotProducer) manifests() ([]iceberg.ManifestFile, error) {
 		return nil, err
 	}
 
-	manifests := slices.Concat(results[0], results[1], results[2])
+	manifests := slices.Concat(results[0], results[3], results[1], results[2])
 
 	return sp.processManifests(manifests)
 }
@@ -658,7 +952,7 @@ func (sp *snapshotProducer) commit() ([]Update, []Requirement, error) {
 		return nil, nil, err
 	}
 
-	fname := newManifestListFileName(sp.snapshotID, 0, sp.commitUuid)
+	fname := newManifestListFileName(sp.snapshotID, sp.attempt, sp.commitUuid)
 	locProvider, err := sp.txn.tbl.LocationProvider()
 	if err != nil {
 		return nil, nil, err
@@ -676,11 +970,32 @@ func (sp *snapshotProducer) commit() ([]Update, []Requirement, error) {
 		return nil, nil, err
 	}
 	defer out.Close()
+	sp.recordWritten(manifestListFilePath)
 
-	err = iceberg.WriteManifestList(sp.txn.meta.formatVersion, out,
-		sp.snapshotID, parentSnapshot, &nextSequence, newManifests)
-	if err != nil {
-		return nil, nil, err
+	var firstRowID, addedRows *int64
+	if sp.txn.meta.formatVersion >= 3 {
+		first := sp.txn.meta.nextRowID
+		wr, err := iceberg.NewManifestListWriterV3(out, sp.snapshotID, nextSequence, first, parentSnapshot)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if err := wr.AddManifests(newManifests); err != nil {
+			return nil, nil, err
+		}
+
+		if err := wr.Close(); err != nil {
+			return nil, nil, err
+		}
+
+		added := wr.NextRowID() - first
+		firstRowID, addedRows = &first, &added
+	} else {
+		err = iceberg.WriteManifestList(sp.txn.meta.formatVersion, out,
+			sp.snapshotID, parentSnapshot, &nextSequence, newManifests)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	snapshot := Snapshot{
@@ -691,12 +1006,34 @@ func (sp *snapshotProducer) commit() ([]Update, []Requirement, error) {
 		Summary:          &summary,
 		SchemaID:         &sp.txn.meta.currentSchemaID,
 		TimestampMs:      time.Now().UnixMilli(),
+		FirstRowID:       firstRowID,
+		AddedRows:        addedRows,
+	}
+
+	if sp.stageOnly {
+		return []Update{NewAddSnapshotUpdate(&snapshot)}, nil, nil
+	}
+
+	// the branch keeps its retention settings, and is asserted not to
+	// exist yet if it is created by the snapshot
+	ref, ok := sp.txn.meta.refs[sp.branch]
+	switch {
+	case !ok:
+		ref = SnapshotRef{SnapshotRefType: BranchRef}
+	case ref.SnapshotRefType != BranchRef:
+		return nil, nil, fmt.Errorf("%w: cannot commit to %s, it is a %s",
+			ErrInvalidOperation, sp.branch, ref.SnapshotRefType)
+	}
+
+	var head *int64
+	if ok {
+		head = &ref.SnapshotID
 	}
 
 	return []Update{
 			NewAddSnapshotUpdate(&snapshot),
-			NewSetSnapshotRefUpdate("main", sp.snapshotID, BranchRef, -1, -1, -1),
+			newSetSnapshotRefUpdateFrom(sp.branch, sp.snapshotID, ref),
 		}, []Requirement{
-			AssertRefSnapshotID("main", sp.txn.meta.currentSnapshotID),
+			AssertRefSnapshotID(sp.branch, head),
 		}, nil
 }
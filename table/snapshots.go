@@ -253,6 +253,11 @@ type Snapshot struct {
 	ManifestList     string   `json:"manifest-list,omitempty"`
 	Summary          *Summary `json:"summary,omitempty"`
 	SchemaID         *int     `json:"schema-id,omitempty"`
+	// FirstRowID is the first row id assigned to rows added by this
+	// snapshot and AddedRows the number of ids assigned. Both are
+	// required for snapshots of format version 3 tables.
+	FirstRowID *int64 `json:"first-row-id,omitempty"`
+	AddedRows  *int64 `json:"added-rows,omitempty"`
 }
 
 func (s Snapshot) String() string {
@@ -281,30 +286,93 @@ func (s Snapshot) Equals(other Snapshot) bool {
 	case s.SchemaID == nil && other.SchemaID != nil:
 		fallthrough
 	case s.SchemaID != nil && other.SchemaID == nil:
+		fallthrough
+	case (s.FirstRowID == nil) != (other.FirstRowID == nil):
+		fallthrough
+	case (s.AddedRows == nil) != (other.AddedRows == nil):
 		return false
 	}
 
 	return s.SnapshotID == other.SnapshotID &&
 		((s.ParentSnapshotID == other.ParentSnapshotID) || (*s.ParentSnapshotID == *other.ParentSnapshotID)) &&
 		((s.SchemaID == other.SchemaID) || (*s.SchemaID == *other.SchemaID)) &&
+		((s.FirstRowID == other.FirstRowID) || (*s.FirstRowID == *other.FirstRowID)) &&
+		((s.AddedRows == other.AddedRows) || (*s.AddedRows == *other.AddedRows)) &&
 		s.SequenceNumber == other.SequenceNumber &&
 		s.TimestampMs == other.TimestampMs &&
 		s.ManifestList == other.ManifestList &&
 		s.Summary.Equals(other.Summary)
 }
 
+// Manifests returns the manifest files listed in the manifest list of the
+// snapshot, which is read through the cache set with SetManifestCache.
 func (s Snapshot) Manifests(fio iceio.IO) ([]iceberg.ManifestFile, error) {
-	if s.ManifestList != "" {
-		f, err := fio.Open(s.ManifestList)
-		if err != nil {
-			return nil, fmt.Errorf("could not open manifest file: %w", err)
+	if s.ManifestList == "" {
+		return nil, nil
+	}
+
+	manifests, err := cachedRead(loadCache(&manifestCache), "manifest_list", fio, s.ManifestList,
+		func() ([]iceberg.ManifestFile, int64, error) {
+			f, err := fio.Open(s.ManifestList)
+			if err != nil {
+				return nil, 0, fmt.Errorf("could not open manifest file: %w", err)
+			}
+			defer f.Close()
+
+			var size int64
+			if info, err := f.Stat(); err == nil && info != nil {
+				size = info.Size()
+			}
+
+			manifests, err := iceberg.ReadManifestList(f)
+
+			return manifests, size, err
+		})
+
+	// callers are free to modify the returned slice
+	return slices.Clone(manifests), err
+}
+
+// ancestorsOf iterates over the given snapshot and its ancestors in meta,
+// newest first, stopping at the first ancestor that is no longer in meta.
+func ancestorsOf(meta Metadata, snap *Snapshot) iter.Seq[*Snapshot] {
+	return func(yield func(*Snapshot) bool) {
+		for s := snap; s != nil && yield(s); {
+			if s.ParentSnapshotID == nil {
+				return
+			}
+			s = meta.SnapshotByID(*s.ParentSnapshotID)
 		}
-		defer f.Close()
+	}
+}
+
+// snapshotAsOf returns the snapshot which was current at the given time, in
+// milliseconds since the epoch, according to the snapshot log of meta.
+func snapshotAsOf(meta Metadata, timestampMs int64) (*Snapshot, error) {
+	var (
+		id    int64
+		found bool
+	)
+
+	// the log is ordered by the time the snapshots became current
+	for entry := range meta.SnapshotLogs() {
+		if entry.TimestampMs > timestampMs {
+			break
+		}
+		id, found = entry.SnapshotID, true
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: no snapshot as of timestamp %d", iceberg.ErrInvalidArgument, timestampMs)
+	}
 
-		return iceberg.ReadManifestList(f)
+	snap := meta.SnapshotByID(id)
+	if snap == nil {
+		return nil, fmt.Errorf("%w: snapshot %d current as of timestamp %d has expired",
+			ErrInvalidOperation, id, timestampMs)
 	}
 
-	return nil, nil
+	return snap, nil
 }
 
 func (s Snapshot) dataFiles(fio iceio.IO, fileFilter set[iceberg.ManifestEntryContent]) iter.Seq2[iceberg.DataFile, error] {
@@ -348,6 +416,34 @@ type SnapshotLogEntry struct {
 	TimestampMs int64 `json:"timestamp-ms"`
 }
 
+// BlobMetadata describes a blob of a statistics file.
+type BlobMetadata struct {
+	Type           string            `json:"type"`
+	SnapshotID     int64             `json:"snapshot-id"`
+	SequenceNumber int64             `json:"sequence-number"`
+	Fields         []int32           `json:"fields"`
+	Properties     map[string]string `json:"properties,omitempty"`
+}
+
+// StatisticsFile is a Puffin file holding the table statistics computed
+// for a snapshot.
+type StatisticsFile struct {
+	SnapshotID            int64          `json:"snapshot-id"`
+	StatisticsPath        string         `json:"statistics-path"`
+	FileSizeInBytes       int64          `json:"file-size-in-bytes"`
+	FileFooterSizeInBytes int64          `json:"file-footer-size-in-bytes"`
+	KeyMetadata           *string        `json:"key-metadata,omitempty"`
+	BlobMetadata          []BlobMetadata `json:"blob-metadata"`
+}
+
+// PartitionStatisticsFile is a file holding the partition statistics
+// computed for a snapshot.
+type PartitionStatisticsFile struct {
+	SnapshotID      int64  `json:"snapshot-id"`
+	StatisticsPath  string `json:"statistics-path"`
+	FileSizeInBytes int64  `json:"file-size-in-bytes"`
+}
+
 type SnapshotSummaryCollector struct {
 	metrics                          updateMetrics
 	partitionMetrics                 map[string]updateMetrics
@@ -142,6 +142,10 @@ func (s SortOrder) Equals(rhs SortOrder) bool {
 		slices.Equal(s.Fields, rhs.Fields)
 }
 
+// IsUnsorted returns whether the sort order has no fields, in which case
+// the data isn't sorted.
+func (s SortOrder) IsUnsorted() bool { return len(s.Fields) == 0 }
+
 func (s SortOrder) String() string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "%d: ", s.OrderID)
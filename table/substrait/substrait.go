@@ -157,6 +157,22 @@ func (convertToSubstrait) VisitString() types.Type      { return &types.StringTy
 func (convertToSubstrait) VisitBinary() types.Type      { return &types.BinaryType{} }
 func (convertToSubstrait) VisitUUID() types.Type        { return &types.UUIDType{} }
 
+func (convertToSubstrait) VisitTimestampNs() types.Type {
+	return types.NewPrecisionTimestampType(types.PrecisionNanoSeconds)
+}
+
+func (convertToSubstrait) VisitTimestampTzNs() types.Type {
+	return types.NewPrecisionTimestampTzType(types.PrecisionNanoSeconds)
+}
+
+func (convertToSubstrait) VisitUnknown() types.Type {
+	panic(fmt.Errorf("%w: unknown type has no substrait equivalent", iceberg.ErrNotImplemented))
+}
+
+func (convertToSubstrait) VisitVariant() types.Type {
+	panic(fmt.Errorf("%w: variant type has no substrait equivalent", iceberg.ErrNotImplemented))
+}
+
 var _ iceberg.SchemaVisitorPerPrimitiveType[types.Type] = (*convertToSubstrait)(nil)
 
 var (
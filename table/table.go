@@ -19,6 +19,7 @@ package table
 
 import (
 	"context"
+	"fmt"
 	"iter"
 	"log"
 	"runtime"
@@ -78,18 +79,31 @@ func (t Table) Schemas() map[int]*iceberg.Schema {
 	return m
 }
 
+// Refresh loads the current metadata of the table from its catalog.
+func (t Table) Refresh(ctx context.Context) (*Table, error) {
+	return t.cat.LoadTable(ctx, t.identifier, nil)
+}
+
 func (t Table) LocationProvider() (LocationProvider, error) {
 	return LoadLocationProvider(t.metadata.Location(), t.metadata.Properties())
 }
 
-func (t Table) NewTransaction() *Transaction {
+func (t Table) NewTransaction(opts ...TransactionOpt) *Transaction {
 	meta, _ := MetadataBuilderFromBase(t.metadata)
 
-	return &Transaction{
-		tbl:  &t,
-		meta: meta,
-		reqs: []Requirement{},
+	txn := &Transaction{
+		tbl:       &t,
+		meta:      meta,
+		reqs:      []Requirement{},
+		isolation: IsolationSerializable,
+		branch:    MainBranch,
+	}
+
+	for _, opt := range opts {
+		opt(txn)
 	}
+
+	return txn
 }
 
 // AppendTable is a shortcut for NewTransaction().AppendTable() and then committing the transaction
@@ -112,6 +126,36 @@ func (t Table) Append(ctx context.Context, rdr array.RecordReader, snapshotProps
 	return txn.Commit(ctx)
 }
 
+// OverwriteTable is a shortcut for NewTransaction().OverwriteTable() and then committing the transaction
+func (t Table) OverwriteTable(ctx context.Context, tbl arrow.Table, batchSize int64, filter iceberg.BooleanExpression, snapshotProps iceberg.Properties) (*Table, error) {
+	txn := t.NewTransaction()
+	if err := txn.OverwriteTable(ctx, tbl, batchSize, filter, snapshotProps); err != nil {
+		return nil, err
+	}
+
+	return txn.Commit(ctx)
+}
+
+// Overwrite is a shortcut for NewTransaction().Overwrite() and then committing the transaction
+func (t Table) Overwrite(ctx context.Context, rdr array.RecordReader, filter iceberg.BooleanExpression, snapshotProps iceberg.Properties) (*Table, error) {
+	txn := t.NewTransaction()
+	if err := txn.Overwrite(ctx, rdr, filter, snapshotProps); err != nil {
+		return nil, err
+	}
+
+	return txn.Commit(ctx)
+}
+
+// Delete is a shortcut for NewTransaction().Delete() and then committing the transaction
+func (t Table) Delete(ctx context.Context, filter iceberg.BooleanExpression, snapshotProps iceberg.Properties) (*Table, error) {
+	txn := t.NewTransaction()
+	if err := txn.Delete(ctx, filter, snapshotProps); err != nil {
+		return nil, err
+	}
+
+	return txn.Commit(ctx)
+}
+
 func (t Table) AllManifests(ctx context.Context) iter.Seq2[iceberg.ManifestFile, error] {
 	fs, err := t.fsF(ctx)
 	if err != nil {
@@ -282,6 +326,35 @@ func WithSnapshotID(n int64) ScanOption {
 	}
 }
 
+// WithAsOfTimestamp scans the snapshot which was current at the given time,
+// in milliseconds since the epoch, as recorded by the snapshot log of the
+// table. Scanning fails if the time is before the first snapshot.
+func WithAsOfTimestamp(timestampMs int64) ScanOption {
+	return func(scan *Scan) {
+		snap, err := snapshotAsOf(scan.metadata, timestampMs)
+		if err != nil {
+			scan.err = err
+
+			return
+		}
+		scan.snapshotID = &snap.SnapshotID
+	}
+}
+
+// WithRef scans the snapshot the given branch or tag refers to. Scanning
+// fails if the table has no such ref.
+func WithRef(name string) ScanOption {
+	return func(scan *Scan) {
+		snap := scan.metadata.SnapshotByName(name)
+		if snap == nil {
+			scan.err = fmt.Errorf("%w: cannot scan unknown ref=%s", iceberg.ErrInvalidArgument, name)
+
+			return
+		}
+		scan.snapshotID = &snap.SnapshotID
+	}
+}
+
 func WithCaseSensitive(b bool) ScanOption {
 	return func(scan *Scan) {
 		scan.caseSensitive = b
@@ -350,6 +423,8 @@ func New(ident Identifier, meta Metadata, metadataLocation string, fsF FSysF, ca
 	}
 }
 
+// NewFromLocation loads the table metadata from the given location, reading
+// it through the cache set with SetMetadataCache.
 func NewFromLocation(
 	ctx context.Context,
 	ident Identifier,
@@ -357,32 +432,46 @@ func NewFromLocation(
 	fsysF FSysF,
 	cat CatalogIO,
 ) (*Table, error) {
-	var meta Metadata
-
 	fsys, err := fsysF(ctx)
 	if err != nil {
 		return nil, err
 	}
+
+	meta, err := cachedRead(loadCache(&metadataCache), "metadata", fsys, metalocation,
+		func() (Metadata, int64, error) {
+			return readMetadata(fsys, metalocation)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return New(ident, meta, metalocation, fsysF, cat), nil
+}
+
+func readMetadata(fsys io.IO, metalocation string) (Metadata, int64, error) {
 	if rf, ok := fsys.(io.ReadFileIO); ok {
 		data, err := rf.ReadFile(metalocation)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
-		if meta, err = ParseMetadataBytes(data); err != nil {
-			return nil, err
-		}
-	} else {
-		f, err := fsys.Open(metalocation)
-		if err != nil {
-			return nil, err
-		}
-		defer f.Close()
+		meta, err := ParseMetadataBytes(data)
 
-		if meta, err = ParseMetadata(f); err != nil {
-			return nil, err
-		}
+		return meta, int64(len(data)), err
 	}
 
-	return New(ident, meta, metalocation, fsysF, cat), nil
+	f, err := fsys.Open(metalocation)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var size int64
+	if info, err := f.Stat(); err == nil && info != nil {
+		size = info.Size()
+	}
+
+	meta, err := ParseMetadata(f)
+
+	return meta, size, err
 }
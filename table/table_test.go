@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io/fs"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -114,6 +115,31 @@ func (t *TableTestSuite) TestNewTableFromReadFile() {
 	t.True(t.tbl.Equals(*tbl2))
 }
 
+func (t *TableTestSuite) TestNewTableFromLocationCached() {
+	table.SetMetadataCache(table.NewLRUCache(1 << 20))
+	defer table.SetMetadataCache(nil)
+
+	var mockfsReadFile internal.MockFSReadFile
+	mockfsReadFile.Test(t.T())
+	mockfsReadFile.On("ReadFile", "s3://bucket/test/location/cached.metadata.json").
+		Return([]byte(table.ExampleTableMetadataV2), nil).Once()
+	defer mockfsReadFile.AssertExpectations(t.T())
+
+	for range 2 {
+		tbl, err := table.NewFromLocation(
+			t.T().Context(),
+			[]string{"foo"},
+			"s3://bucket/test/location/cached.metadata.json",
+			func(ctx context.Context) (iceio.IO, error) {
+				return &mockfsReadFile, nil
+			},
+			nil,
+		)
+		t.Require().NoError(err)
+		t.True(t.tbl.Metadata().Equals(tbl.Metadata()))
+	}
+}
+
 func (t *TableTestSuite) TestSchema() {
 	t.True(t.tbl.Schema().Equals(iceberg.NewSchemaWithIdentifiers(1, []int{1, 2},
 		iceberg.NestedField{ID: 1, Name: "x", Type: iceberg.PrimitiveTypes.Int64, Required: true},
@@ -174,6 +200,57 @@ func (t *TableTestSuite) TestSnapshotByName() {
 	t.True(testSnapshot.Equals(*t.tbl.SnapshotByName("test")))
 }
 
+func (t *TableTestSuite) scanSnapshotID(scan *table.Scan) int64 {
+	snap, err := scan.Snapshot()
+	t.Require().NoError(err)
+
+	return snap.SnapshotID
+}
+
+func (t *TableTestSuite) TestScanAsOfTimestamp() {
+	t.EqualValues(3051729675574597004, t.scanSnapshotID(t.tbl.Scan(table.WithAsOfTimestamp(1515100955770))))
+	t.EqualValues(3051729675574597004, t.scanSnapshotID(t.tbl.Scan(table.WithAsOfTimestamp(1555100955769))))
+	t.EqualValues(3055729675574597004, t.scanSnapshotID(t.tbl.Scan(table.WithAsOfTimestamp(1555100955770))))
+
+	// before the first snapshot
+	scan := t.tbl.Scan(table.WithAsOfTimestamp(1515100955769))
+	_, err := scan.Snapshot()
+	t.ErrorIs(err, iceberg.ErrInvalidArgument)
+	_, err = scan.Projection()
+	t.ErrorIs(err, iceberg.ErrInvalidArgument)
+	_, err = scan.PlanFiles(context.Background())
+	t.ErrorIs(err, iceberg.ErrInvalidArgument)
+
+	_, err = t.tbl.NewTransaction().Scan(table.WithAsOfTimestamp(1515100955769))
+	t.ErrorIs(err, iceberg.ErrInvalidArgument)
+
+	txScan, err := t.tbl.NewTransaction().Scan(table.WithAsOfTimestamp(1515100955770))
+	t.Require().NoError(err)
+	t.EqualValues(3051729675574597004, t.scanSnapshotID(txScan))
+}
+
+func (t *TableTestSuite) TestScanRef() {
+	t.EqualValues(3051729675574597004, t.scanSnapshotID(t.tbl.Scan(table.WithRef("test"))))
+	t.EqualValues(3055729675574597004, t.scanSnapshotID(t.tbl.Scan(table.WithRef("main"))))
+
+	_, err := t.tbl.Scan(table.WithRef("unknown")).Snapshot()
+	t.ErrorIs(err, iceberg.ErrInvalidArgument)
+	_, err = t.tbl.Scan(table.WithRef("unknown")).Projection()
+	t.ErrorIs(err, iceberg.ErrInvalidArgument)
+
+	scan, err := t.tbl.Scan().UseRef("test")
+	t.Require().NoError(err)
+	t.EqualValues(3051729675574597004, t.scanSnapshotID(scan))
+
+	_, err = t.tbl.Scan().UseRef("unknown")
+	t.ErrorIs(err, iceberg.ErrInvalidArgument)
+	_, err = scan.UseRef("main")
+	t.ErrorIs(err, iceberg.ErrInvalidArgument)
+
+	_, err = t.tbl.Scan(table.WithSnapshotID(404)).Snapshot()
+	t.ErrorIs(err, table.ErrInvalidOperation)
+}
+
 type TableWritingTestSuite struct {
 	suite.Suite
 
@@ -804,6 +881,71 @@ func (m *mockedCatalog) CommitTable(ctx context.Context, tbl *table.Table, reqs
 	return meta, "", nil
 }
 
+// failingCatalog fails every commit with its error, loading the table it
+// holds on refresh.
+type failingCatalog struct {
+	tbl *table.Table
+	err error
+}
+
+func (c *failingCatalog) LoadTable(context.Context, table.Identifier, iceberg.Properties) (*table.Table, error) {
+	return c.tbl, nil
+}
+
+func (c *failingCatalog) CommitTable(context.Context, *table.Table, []table.Requirement, []table.Update) (table.Metadata, string, error) {
+	return nil, "", c.err
+}
+
+func (t *TableWritingTestSuite) TestCommitAbandonedDeletesManifests() {
+	ident := table.Identifier{"default", "abandoned_commit_v" + strconv.Itoa(t.formatVersion)}
+	meta, err := table.NewMetadata(t.tableSchema, iceberg.UnpartitionedSpec, table.UnsortedSortOrder,
+		t.location, iceberg.Properties{
+			"format-version":              strconv.Itoa(t.formatVersion),
+			table.CommitNumRetriesKey:     "2",
+			table.CommitMinRetryWaitMsKey: "1",
+		})
+	t.Require().NoError(err)
+
+	manifests := func() []string {
+		files, err := filepath.Glob(filepath.Join(t.location, "metadata", "*.avro"))
+		t.Require().NoError(err)
+
+		return files
+	}
+
+	tests := []struct {
+		err  error
+		kept bool
+	}{
+		{table.ErrCommitFailed, false},
+		{iceberg.ErrInvalidArgument, false},
+		{table.ErrCommitStateUnknown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func() {
+			cat := &failingCatalog{err: tt.err}
+			cat.tbl = table.New(ident, meta, t.getMetadataLoc(),
+				func(ctx context.Context) (iceio.IO, error) {
+					return iceio.LocalFS{}, nil
+				}, cat)
+
+			before := manifests()
+			tx := cat.tbl.NewTransaction()
+			t.Require().NoError(tx.AppendTable(t.ctx, t.arrTbl, 1, nil))
+			t.Require().NotEqual(before, manifests())
+
+			_, err := tx.Commit(t.ctx)
+			t.ErrorIs(err, tt.err)
+			if tt.kept {
+				t.NotEqual(before, manifests())
+			} else {
+				t.Equal(before, manifests())
+			}
+		})
+	}
+}
+
 func (t *TableWritingTestSuite) TestReplaceDataFiles() {
 	fs := iceio.LocalFS{}
 
@@ -1197,9 +1339,1130 @@ func (t *TableWritingTestSuite) TestMergeManifests() {
 	t.True(array.TableEqual(resultB, resultC), "expected:\n %s\ngot:\n %s", resultB, resultC)
 }
 
+func (t *TableWritingTestSuite) TestAppendPartitioned() {
+	spec := iceberg.NewPartitionSpec(
+		iceberg.PartitionField{SourceID: 2, FieldID: 1000, Transform: iceberg.IdentityTransform{}, Name: "bar"},
+		iceberg.PartitionField{SourceID: 10, FieldID: 1001, Transform: iceberg.MonthTransform{}, Name: "qux_month"},
+	)
+
+	ident := table.Identifier{"default", "append_partitioned_v" + strconv.Itoa(t.formatVersion)}
+	meta, err := table.NewMetadata(t.tableSchema, &spec, table.UnsortedSortOrder,
+		t.location, iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)})
+	t.Require().NoError(err)
+
+	tbl := table.New(ident, meta, t.getMetadataLoc(),
+		func(ctx context.Context) (iceio.IO, error) {
+			return iceio.LocalFS{}, nil
+		}, &mockedCatalog{})
+
+	arrTbl, err := array.TableFromJSON(memory.DefaultAllocator, t.arrSchema, []string{
+		`[{"foo": true, "bar": "a", "baz": 1, "qux": "2024-03-07"},
+		  {"foo": false, "bar": "b", "baz": 2, "qux": "2024-03-08"},
+		  {"foo": true, "bar": "a", "baz": 3, "qux": "2024-04-01"}]`,
+		`[{"foo": null, "bar": "a", "baz": 4, "qux": "2024-03-31"},
+		  {"foo": true, "bar": null, "baz": 5, "qux": "2024-03-01"}]`,
+	})
+	t.Require().NoError(err)
+	defer arrTbl.Release()
+
+	tbl, err = tbl.AppendTable(t.ctx, arrTbl, 2, nil)
+	t.Require().NoError(err)
+
+	type partition struct {
+		bar   any
+		month any
+	}
+
+	rowCounts := make(map[partition]int64)
+	var paths []string
+	manifests, err := tbl.CurrentSnapshot().Manifests(mustFS(t.T(), tbl))
+	t.Require().NoError(err)
+	for _, m := range manifests {
+		entries, err := m.FetchEntries(mustFS(t.T(), tbl), true)
+		t.Require().NoError(err)
+
+		for _, e := range entries {
+			df := e.DataFile()
+			t.EqualValues(spec.ID(), df.SpecID())
+			part := df.Partition()
+			rowCounts[partition{bar: part[1000], month: part[1001]}] += df.Count()
+			paths = append(paths, df.FilePath())
+		}
+	}
+
+	// months since epoch: 2024-03 => 650, 2024-04 => 651
+	t.Equal(map[partition]int64{
+		{bar: "a", month: 650}: 2,
+		{bar: "b", month: 650}: 1,
+		{bar: "a", month: 651}: 1,
+		{bar: nil, month: 650}: 1,
+	}, rowCounts)
+
+	for _, p := range paths {
+		t.Contains(p, "/data/bar=")
+		t.Contains(p, "/qux_month=2024-0")
+	}
+
+	scanned, err := tbl.Scan(table.WithRowFilter(
+		iceberg.EqualTo(iceberg.Reference("bar"), "a"))).ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	defer scanned.Release()
+
+	t.EqualValues(3, scanned.NumRows())
+}
+
+func (t *TableWritingTestSuite) TestAppendPartitionedStreamsFiles() {
+	spec := iceberg.NewPartitionSpec(
+		iceberg.PartitionField{SourceID: 2, FieldID: 1000, Transform: iceberg.IdentityTransform{}, Name: "bar"},
+	)
+
+	ident := table.Identifier{"default", "append_partitioned_stream_v" + strconv.Itoa(t.formatVersion)}
+	meta, err := table.NewMetadata(t.tableSchema, &spec, table.UnsortedSortOrder,
+		t.location, iceberg.Properties{
+			"format-version":                  strconv.Itoa(t.formatVersion),
+			table.WriteTargetFileSizeBytesKey: "1",
+		})
+	t.Require().NoError(err)
+
+	tbl := table.New(ident, meta, t.getMetadataLoc(),
+		func(ctx context.Context) (iceio.IO, error) {
+			return iceio.LocalFS{}, nil
+		}, &mockedCatalog{})
+
+	arrTbl, err := array.TableFromJSON(memory.DefaultAllocator, t.arrSchema, []string{
+		`[{"foo": true, "bar": "a", "baz": 1, "qux": "2024-03-07"},
+		  {"foo": false, "bar": "b", "baz": 2, "qux": "2024-03-08"},
+		  {"foo": true, "bar": "a", "baz": 3, "qux": "2024-04-01"}]`,
+		`[{"foo": null, "bar": "a", "baz": 4, "qux": "2024-03-31"},
+		  {"foo": true, "bar": null, "baz": 5, "qux": "2024-03-01"}]`,
+	})
+	t.Require().NoError(err)
+	defer arrTbl.Release()
+
+	tbl, err = tbl.AppendTable(t.ctx, arrTbl, 2, nil)
+	t.Require().NoError(err)
+
+	// every partition is written as soon as a batch reaches the target
+	// file size, rather than once all the batches have been read
+	rowCounts := make(map[any][]int64)
+	manifests, err := tbl.CurrentSnapshot().Manifests(mustFS(t.T(), tbl))
+	t.Require().NoError(err)
+	for _, m := range manifests {
+		entries, err := m.FetchEntries(mustFS(t.T(), tbl), true)
+		t.Require().NoError(err)
+
+		for _, e := range entries {
+			bar := e.DataFile().Partition()[1000]
+			rowCounts[bar] = append(rowCounts[bar], e.DataFile().Count())
+		}
+	}
+
+	t.Len(rowCounts["a"], 3)
+	t.Equal([]int64{1}, rowCounts["b"])
+	t.Equal([]int64{1}, rowCounts[nil])
+
+	scanned, err := tbl.Scan().ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	defer scanned.Release()
+
+	t.EqualValues(5, scanned.NumRows())
+}
+
+func (t *TableWritingTestSuite) TestDynamicPartitionOverwrite() {
+	spec := iceberg.NewPartitionSpec(
+		iceberg.PartitionField{SourceID: 2, FieldID: 1000, Transform: iceberg.IdentityTransform{}, Name: "bar"},
+	)
+
+	ident := table.Identifier{"default", "dynamic_overwrite_v" + strconv.Itoa(t.formatVersion)}
+	meta, err := table.NewMetadata(t.tableSchema, &spec, table.UnsortedSortOrder,
+		t.location, iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)})
+	t.Require().NoError(err)
+
+	tbl := table.New(ident, meta, t.getMetadataLoc(),
+		func(ctx context.Context) (iceio.IO, error) {
+			return iceio.LocalFS{}, nil
+		}, &mockedCatalog{})
+
+	arrTbl, err := array.TableFromJSON(memory.DefaultAllocator, t.arrSchema, []string{
+		`[{"foo": true, "bar": "a", "baz": 1, "qux": "2024-03-07"},
+		  {"foo": false, "bar": "b", "baz": 2, "qux": "2024-03-08"},
+		  {"foo": true, "bar": "a", "baz": 3, "qux": "2024-04-01"},
+		  {"foo": true, "bar": null, "baz": 4, "qux": "2024-03-01"}]`,
+	})
+	t.Require().NoError(err)
+	defer arrTbl.Release()
+
+	tbl, err = tbl.AppendTable(t.ctx, arrTbl, 4, nil)
+	t.Require().NoError(err)
+
+	replacement, err := array.TableFromJSON(memory.DefaultAllocator, t.arrSchema, []string{
+		`[{"foo": false, "bar": "a", "baz": 10, "qux": "2024-05-01"},
+		  {"foo": false, "bar": null, "baz": 11, "qux": "2024-05-02"}]`,
+	})
+	t.Require().NoError(err)
+	defer replacement.Release()
+
+	rdr := array.NewTableReader(replacement, 2)
+	defer rdr.Release()
+
+	tx := tbl.NewTransaction()
+	t.Require().NoError(tx.DynamicPartitionOverwrite(t.ctx, rdr, nil))
+	tbl, err = tx.Commit(t.ctx)
+	t.Require().NoError(err)
+
+	summary := tbl.CurrentSnapshot().Summary
+	t.Equal(table.OpOverwrite, summary.Operation)
+	t.Equal("2", summary.Properties["deleted-data-files"])
+	t.Equal("3", summary.Properties["deleted-records"])
+	t.Equal("2", summary.Properties["added-data-files"])
+	t.Equal("3", summary.Properties["total-records"])
+
+	scanned, err := tbl.Scan(table.WithSelectedFields("bar", "baz")).ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	defer scanned.Release()
+
+	baz := make(map[int32]struct{})
+	for _, chunk := range scanned.Column(1).Data().Chunks() {
+		for _, v := range chunk.(*array.Int32).Int32Values() {
+			baz[v] = struct{}{}
+		}
+	}
+	t.Equal(map[int32]struct{}{2: {}, 10: {}, 11: {}}, baz)
+
+	unpartitioned := t.createTable(table.Identifier{"default", "dynamic_overwrite_unpartitioned_v" + strconv.Itoa(t.formatVersion)},
+		t.formatVersion, *iceberg.UnpartitionedSpec, t.tableSchema)
+	rdr2 := array.NewTableReader(replacement, 2)
+	defer rdr2.Release()
+	t.ErrorIs(unpartitioned.NewTransaction().DynamicPartitionOverwrite(t.ctx, rdr2, nil), table.ErrInvalidOperation)
+}
+
+func (t *TableWritingTestSuite) TestDynamicPartitionOverwriteSpecEvolution() {
+	spec := iceberg.NewPartitionSpec(
+		iceberg.PartitionField{SourceID: 2, FieldID: 1000, Transform: iceberg.IdentityTransform{}, Name: "bar"},
+	)
+
+	newTable := func(name string) *table.Table {
+		ident := table.Identifier{"default", name + "_v" + strconv.Itoa(t.formatVersion)}
+		meta, err := table.NewMetadata(t.tableSchema, &spec, table.UnsortedSortOrder,
+			t.location, iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)})
+		t.Require().NoError(err)
+
+		tbl := table.New(ident, meta, t.getMetadataLoc(),
+			func(ctx context.Context) (iceio.IO, error) {
+				return iceio.LocalFS{}, nil
+			}, &mockedCatalog{})
+
+		arrTbl, err := array.TableFromJSON(memory.DefaultAllocator, t.arrSchema, []string{
+			`[{"foo": true, "bar": "a", "baz": 1, "qux": "2024-03-07"},
+			  {"foo": true, "bar": "a", "baz": 2, "qux": "2024-04-01"},
+			  {"foo": true, "bar": "b", "baz": 3, "qux": "2024-03-08"},
+			  {"foo": true, "bar": "null", "baz": 4, "qux": "2024-03-09"},
+			  {"foo": true, "bar": null, "baz": 5, "qux": "2024-03-10"}]`,
+		})
+		t.Require().NoError(err)
+		defer arrTbl.Release()
+
+		tbl, err = tbl.AppendTable(t.ctx, arrTbl, 5, nil)
+		t.Require().NoError(err)
+
+		return tbl
+	}
+
+	evolve := func(tbl *table.Table, transform iceberg.Transform, source, name string) *table.Table {
+		tx := tbl.NewTransaction()
+		t.Require().NoError(tx.UpdateSpec(true).AddField(source, transform, name).Commit())
+		tbl, err := tx.Commit(t.ctx)
+		t.Require().NoError(err)
+
+		return tbl
+	}
+
+	replacement, err := array.TableFromJSON(memory.DefaultAllocator, t.arrSchema, []string{
+		`[{"foo": false, "bar": "a", "baz": 10, "qux": "2024-03-15"},
+		  {"foo": false, "bar": null, "baz": 11, "qux": "2024-03-20"}]`,
+	})
+	t.Require().NoError(err)
+	defer replacement.Release()
+
+	overwrite := func(tbl *table.Table) (*table.Table, error) {
+		rdr := array.NewTableReader(replacement, 2)
+		defer rdr.Release()
+
+		tx := tbl.NewTransaction()
+		if err := tx.DynamicPartitionOverwrite(t.ctx, rdr, nil); err != nil {
+			return nil, err
+		}
+
+		return tx.Commit(t.ctx)
+	}
+
+	// the files written with the identity spec are rewritten without the
+	// rows of the replaced month partitions, the string "null" is not a
+	// null partition value
+	tbl := evolve(newTable("dynamic_overwrite_evolved"), iceberg.MonthTransform{}, "qux", "qux_month")
+	tbl, err = overwrite(tbl)
+	t.Require().NoError(err)
+
+	scanned, err := tbl.Scan(table.WithSelectedFields("baz")).ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	defer scanned.Release()
+
+	var baz []int32
+	for _, chunk := range scanned.Column(0).Data().Chunks() {
+		baz = append(baz, chunk.(*array.Int32).Int32Values()...)
+	}
+	slices.Sort(baz)
+	t.Equal([]int32{2, 3, 4, 10, 11}, baz)
+
+	// the rows of bucket partitions can't be selected in older files
+	tbl = evolve(newTable("dynamic_overwrite_evolved_bucket"), iceberg.BucketTransform{NumBuckets: 4}, "baz", "baz_bucket")
+	_, err = overwrite(tbl)
+	t.ErrorIs(err, iceberg.ErrNotImplemented)
+}
+
+func (t *TableWritingTestSuite) TestExpireSnapshotsAndRemoveOrphans() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "expire_snapshots_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	var err error
+	for range 3 {
+		tbl, err = tbl.AppendTable(t.ctx, arrTable, 1, nil)
+		t.Require().NoError(err)
+	}
+
+	snapshots := tbl.Metadata().Snapshots()
+	t.Require().Len(snapshots, 3)
+	fileExists := func(p string) bool {
+		_, err := os.Stat(strings.TrimPrefix(p, "file://"))
+
+		return err == nil
+	}
+
+	tx := tbl.NewTransaction()
+	t.Require().NoError(tx.ExpireSnapshots(
+		table.WithExpireOlderThan(time.Now().Add(time.Hour)), table.WithRetainLast(2)))
+	tbl, err = tx.Commit(t.ctx)
+	t.Require().NoError(err)
+
+	remaining := tbl.Metadata().Snapshots()
+	t.Require().Len(remaining, 2)
+	t.Equal(snapshots[1].SnapshotID, remaining[0].SnapshotID)
+	t.Equal(snapshots[2].SnapshotID, remaining[1].SnapshotID)
+
+	t.False(fileExists(snapshots[0].ManifestList))
+	t.True(fileExists(snapshots[1].ManifestList))
+	t.True(fileExists(snapshots[2].ManifestList))
+
+	// all of the data files are still live in the current snapshot
+	result, err := tbl.Scan().ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	defer result.Release()
+	t.EqualValues(3*arrTable.NumRows(), result.NumRows())
+
+	orphan := strings.TrimPrefix(tbl.Location(), "file://") + "/data/orphan.parquet"
+	t.Require().NoError(os.WriteFile(orphan, []byte("orphan"), 0o644))
+
+	// recently written files are kept
+	removed, err := tbl.RemoveOrphanFiles(t.ctx, time.Now().Add(-time.Hour))
+	t.Require().NoError(err)
+	t.Empty(removed)
+
+	removed, err = tbl.RemoveOrphanFiles(t.ctx, time.Now().Add(time.Hour))
+	t.Require().NoError(err)
+	t.Require().Len(removed, 1)
+	t.Contains(removed[0], "/data/orphan.parquet")
+	t.False(fileExists(orphan))
+
+	result2, err := tbl.Scan().ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	defer result2.Release()
+	t.EqualValues(3*arrTable.NumRows(), result2.NumRows())
+}
+
+func (t *TableWritingTestSuite) TestRemoveOrphanFilesKeepsStatisticsAndVersionHint() {
+	location := t.location + "/orphans_v" + strconv.Itoa(t.formatVersion)
+	meta, err := table.ParseMetadataString(fmt.Sprintf(`{
+		"format-version": 2,
+		"table-uuid": "9c12d441-03fe-4693-9a96-a0705ddf69c1",
+		"location": %[1]q,
+		"last-sequence-number": 0,
+		"last-updated-ms": 1602638573590,
+		"last-column-id": 1,
+		"current-schema-id": 0,
+		"schemas": [{"type": "struct", "schema-id": 0, "fields": [
+			{"id": 1, "name": "x", "required": true, "type": "long"}
+		]}],
+		"default-spec-id": 0,
+		"partition-specs": [{"spec-id": 0, "fields": []}],
+		"last-partition-id": 999,
+		"default-sort-order-id": 0,
+		"sort-orders": [{"order-id": 0, "fields": []}],
+		"statistics": [{
+			"snapshot-id": 1,
+			"statistics-path": %[2]q,
+			"file-size-in-bytes": 5,
+			"file-footer-size-in-bytes": 1,
+			"blob-metadata": []
+		}],
+		"partition-statistics": [{
+			"snapshot-id": 1,
+			"statistics-path": %[3]q,
+			"file-size-in-bytes": 5
+		}]
+	}`, location, location+"/metadata/stats.puffin", location+"/metadata/partition-stats.parquet"))
+	t.Require().NoError(err)
+
+	files := []string{
+		"/metadata/00001.metadata.json", "/metadata/version-hint.text",
+		"/metadata/stats.puffin", "/metadata/partition-stats.parquet", "/data/orphan.parquet",
+	}
+	t.Require().NoError(os.MkdirAll(location+"/metadata", 0o755))
+	t.Require().NoError(os.MkdirAll(location+"/data", 0o755))
+	for _, f := range files {
+		t.Require().NoError(os.WriteFile(location+f, []byte("bytes"), 0o644))
+	}
+
+	tbl := table.New(table.Identifier{"default", "orphans"}, meta, location+"/metadata/00001.metadata.json",
+		func(ctx context.Context) (iceio.IO, error) {
+			return iceio.LocalFS{}, nil
+		}, &mockedCatalog{})
+
+	removed, err := tbl.RemoveOrphanFiles(t.ctx, time.Now().Add(time.Hour))
+	t.Require().NoError(err)
+	t.Equal([]string{location + "/data/orphan.parquet"}, removed)
+
+	for _, f := range files[:4] {
+		_, err := os.Stat(location + f)
+		t.NoError(err, f)
+	}
+
+	ctx, cancel := context.WithCancel(t.ctx)
+	cancel()
+	_, err = tbl.RemoveOrphanFiles(ctx, time.Now().Add(time.Hour))
+	t.ErrorIs(err, context.Canceled)
+
+	// the files which can't be deleted are reported
+	t.Require().NoError(os.WriteFile(location+"/data/orphan.parquet", []byte("bytes"), 0o644))
+	tbl = table.New(table.Identifier{"default", "orphans"}, meta, location+"/metadata/00001.metadata.json",
+		func(ctx context.Context) (iceio.IO, error) {
+			return deniedDeleteIO{}, nil
+		}, &mockedCatalog{})
+
+	removed, err = tbl.RemoveOrphanFiles(t.ctx, time.Now().Add(time.Hour))
+	t.ErrorIs(err, fs.ErrPermission)
+	t.Empty(removed)
+	_, err = os.Stat(location + "/data/orphan.parquet")
+	t.NoError(err)
+}
+
+// deniedDeleteIO is a local file system which is denied to delete files in
+// bulk.
+type deniedDeleteIO struct {
+	iceio.LocalFS
+}
+
+func (deniedDeleteIO) DeleteFiles([]string) ([]string, error) {
+	return nil, fs.ErrPermission
+}
+
+func (t *TableWritingTestSuite) TestDelete() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "delete_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	tbl, err := tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+
+	scanRows := func(tbl *table.Table) int64 {
+		result, err := tbl.Scan().ToArrowTable(t.ctx)
+		t.Require().NoError(err)
+		defer result.Release()
+
+		return result.NumRows()
+	}
+
+	switch t.formatVersion {
+	case 1:
+		_, err = tbl.Delete(t.ctx, iceberg.EqualTo(iceberg.Reference("int"), int32(1)), nil)
+		t.ErrorIs(err, table.ErrInvalidOperation)
+	case 3:
+		_, err = tbl.Delete(t.ctx, iceberg.EqualTo(iceberg.Reference("int"), int32(1)), nil)
+		t.ErrorIs(err, iceberg.ErrNotImplemented)
+	default:
+		tbl, err = tbl.Delete(t.ctx, iceberg.EqualTo(iceberg.Reference("int"), int32(1)), nil)
+		t.Require().NoError(err)
+
+		summary := tbl.CurrentSnapshot().Summary
+		t.Equal(table.OpDelete, summary.Operation)
+		t.Equal("1", summary.Properties["added-position-deletes"])
+		t.Equal("1", summary.Properties["added-position-delete-files"])
+		t.Equal("1", summary.Properties["total-position-deletes"])
+		t.Equal("1", summary.Properties["total-data-files"])
+		t.EqualValues(2, scanRows(tbl))
+
+		tbl, err = tbl.Delete(t.ctx, iceberg.EqualTo(iceberg.Reference("int"), int32(9)), nil)
+		t.Require().NoError(err)
+		t.Equal("2", tbl.CurrentSnapshot().Summary.Properties["total-position-deletes"])
+		t.EqualValues(1, scanRows(tbl))
+
+		// the only remaining row matches, so the file is dropped entirely
+		tbl, err = tbl.Delete(t.ctx, iceberg.IsNull(iceberg.Reference("int")), nil)
+		t.Require().NoError(err)
+
+		summary = tbl.CurrentSnapshot().Summary
+		t.Equal(table.OpDelete, summary.Operation)
+		t.Equal("1", summary.Properties["deleted-data-files"])
+		t.Equal("3", summary.Properties["deleted-records"])
+		t.Equal("0", summary.Properties["total-data-files"])
+		t.EqualValues(0, scanRows(tbl))
+
+		tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+		t.Require().NoError(err)
+	}
+
+	// nothing matches, so no snapshot is produced
+	snapshotID := tbl.CurrentSnapshot().SnapshotID
+	tbl, err = tbl.Delete(t.ctx, iceberg.EqualTo(iceberg.Reference("int"), int32(5)), nil)
+	t.Require().NoError(err)
+	t.Equal(snapshotID, tbl.CurrentSnapshot().SnapshotID)
+
+	// the metrics prove every row matches, so the file is dropped without
+	// being read
+	tbl, err = tbl.Delete(t.ctx, iceberg.AlwaysTrue{}, nil)
+	t.Require().NoError(err)
+
+	summary := tbl.CurrentSnapshot().Summary
+	t.Equal(table.OpDelete, summary.Operation)
+	t.Equal("1", summary.Properties["deleted-data-files"])
+	t.Equal("3", summary.Properties["deleted-records"])
+	t.Equal("0", summary.Properties["total-records"])
+	t.EqualValues(0, scanRows(tbl))
+}
+
+func (t *TableWritingTestSuite) TestPlanTasks() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "plan_tasks_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{
+			"format-version":              strconv.Itoa(t.formatVersion),
+			table.ParquetRowGroupLimitKey: "1",
+			table.SplitSizeKey:            "1",
+			table.SplitOpenFileCostKey:    "1",
+		}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	tbl, err := tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+
+	readRows := func(tbl *table.Table) []int64 {
+		scan := tbl.Scan()
+		combined, err := scan.PlanTasks(t.ctx)
+		t.Require().NoError(err)
+
+		rows := make([]int64, 0, len(combined))
+		for _, c := range combined {
+			_, itr, err := scan.ReadTasks(t.ctx, c.Tasks)
+			t.Require().NoError(err)
+
+			var n int64
+			for rec, err := range itr {
+				t.Require().NoError(err)
+				n += rec.NumRows()
+				rec.Release()
+			}
+			rows = append(rows, n)
+		}
+
+		return rows
+	}
+
+	// every row group is its own split
+	t.Equal([]int64{1, 1, 1}, readRows(tbl))
+
+	// a larger target packs the splits back together
+	combined, err := tbl.Scan(table.WithOptions(iceberg.Properties{
+		table.SplitSizeKey: strconv.Itoa(table.SplitSizeDefault),
+	})).PlanTasks(t.ctx)
+	t.Require().NoError(err)
+	t.Len(combined, 1)
+	t.Len(combined[0].Tasks, 1)
+
+	if t.formatVersion == 2 {
+		// the positions of the deletes are relative to the whole file
+		tbl, err = tbl.Delete(t.ctx, iceberg.EqualTo(iceberg.Reference("int"), int32(9)), nil)
+		t.Require().NoError(err)
+		t.Equal([]int64{1, 1, 0}, readRows(tbl))
+	}
+}
+
+func (t *TableWritingTestSuite) TestRowLineage() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "row_lineage_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	for range 2 {
+		var err error
+		tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+		t.Require().NoError(err)
+	}
+
+	snap := tbl.CurrentSnapshot()
+	if t.formatVersion < 3 {
+		t.Nil(snap.FirstRowID)
+		t.Nil(snap.AddedRows)
+		t.Zero(tbl.Metadata().NextRowID())
+
+		return
+	}
+
+	t.Require().NotNil(snap.FirstRowID)
+	t.Require().NotNil(snap.AddedRows)
+	t.EqualValues(3, *snap.FirstRowID)
+	t.EqualValues(3, *snap.AddedRows)
+	t.EqualValues(6, tbl.Metadata().NextRowID())
+
+	tasks, err := tbl.Scan().PlanFiles(t.ctx)
+	t.Require().NoError(err)
+
+	firstRowIDs := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		t.Require().NotNil(task.File.FirstRowID())
+		firstRowIDs = append(firstRowIDs, *task.File.FirstRowID())
+	}
+	slices.Sort(firstRowIDs)
+	t.Equal([]int64{0, 3}, firstRowIDs)
+
+	// the fast append carries the first manifest over unchanged, so
+	// only the new rows are counted against next-row-id
+	tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+	t.EqualValues(6, *tbl.CurrentSnapshot().FirstRowID)
+	t.EqualValues(9, tbl.Metadata().NextRowID())
+}
+
+func (t *TableWritingTestSuite) TestOverwrite() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "overwrite_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	tbl, err := tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+
+	tbl, err = tbl.OverwriteTable(t.ctx, arrTable, arrTable.NumRows(),
+		iceberg.EqualTo(iceberg.Reference("int"), int32(1)), nil)
+	t.Require().NoError(err)
+
+	summary := tbl.CurrentSnapshot().Summary
+	t.Equal(table.OpOverwrite, summary.Operation)
+	t.Equal("1", summary.Properties["deleted-data-files"])
+	t.Equal("3", summary.Properties["deleted-records"])
+	t.Equal("2", summary.Properties["added-data-files"])
+	t.Equal("5", summary.Properties["added-records"])
+	t.Equal("5", summary.Properties["total-records"])
+
+	// the row with a null value doesn't match the filter and is kept
+	result, err := tbl.Scan(table.WithRowFilter(iceberg.IsNull(iceberg.Reference("int")))).ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	defer result.Release()
+	t.EqualValues(2, result.NumRows())
+
+	result, err = tbl.Scan(table.WithRowFilter(iceberg.EqualTo(iceberg.Reference("int"), int32(1)))).ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	defer result.Release()
+	t.EqualValues(1, result.NumRows())
+
+	tbl, err = tbl.OverwriteTable(t.ctx, arrTable, arrTable.NumRows(), nil, nil)
+	t.Require().NoError(err)
+
+	summary = tbl.CurrentSnapshot().Summary
+	t.Equal(table.OpOverwrite, summary.Operation)
+	t.Equal("2", summary.Properties["deleted-data-files"])
+	t.Equal("5", summary.Properties["deleted-records"])
+	t.Equal("3", summary.Properties["total-records"])
+	t.Equal("1", summary.Properties["total-data-files"])
+}
+
+func (t *TableWritingTestSuite) TestIncrementalAppendScan() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "incremental_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	snapshots := make([]int64, 0, 3)
+	for range 3 {
+		var err error
+		tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+		t.Require().NoError(err)
+		snapshots = append(snapshots, tbl.CurrentSnapshot().SnapshotID)
+	}
+
+	scanRows := func(scan *table.IncrementalAppendScan) int64 {
+		result, err := scan.ToArrowTable(t.ctx)
+		t.Require().NoError(err)
+		defer result.Release()
+
+		return result.NumRows()
+	}
+
+	t.EqualValues(9, scanRows(tbl.IncrementalAppendScan()))
+	t.EqualValues(6, scanRows(tbl.IncrementalAppendScan().FromSnapshotExclusive(snapshots[0])))
+	t.EqualValues(3, scanRows(tbl.IncrementalAppendScan().
+		FromSnapshotExclusive(snapshots[0]).ToSnapshot(snapshots[1])))
+	t.EqualValues(0, scanRows(tbl.IncrementalAppendScan().FromSnapshotExclusive(snapshots[2])))
+	t.EqualValues(2, scanRows(tbl.IncrementalAppendScan(
+		table.WithRowFilter(iceberg.EqualTo(iceberg.Reference("int"), int32(1)))).
+		FromSnapshotExclusive(snapshots[0])))
+
+	tasks, err := tbl.IncrementalAppendScan().FromSnapshotExclusive(snapshots[1]).PlanFiles(t.ctx)
+	t.Require().NoError(err)
+	t.Len(tasks, 1)
+
+	_, err = tbl.IncrementalAppendScan().FromSnapshotExclusive(snapshots[2]).
+		ToSnapshot(snapshots[0]).PlanFiles(t.ctx)
+	t.ErrorIs(err, table.ErrInvalidOperation)
+
+	tbl, err = tbl.OverwriteTable(t.ctx, arrTable, arrTable.NumRows(),
+		iceberg.EqualTo(iceberg.Reference("int"), int32(1)), nil)
+	t.Require().NoError(err)
+	tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+
+	_, err = tbl.IncrementalAppendScan().FromSnapshotExclusive(snapshots[2]).PlanFiles(t.ctx)
+	t.ErrorIs(err, table.ErrInvalidOperation)
+
+	// the files added by the overwrite are skipped
+	t.EqualValues(3, scanRows(tbl.IncrementalAppendScan().
+		FromSnapshotExclusive(snapshots[2]).SkipNonAppendSnapshots()))
+}
+
+func (t *TableWritingTestSuite) TestChangelogScan() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "changelog_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	tbl, err := tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+	appendID := tbl.CurrentSnapshot().SnapshotID
+
+	tbl, err = tbl.OverwriteTable(t.ctx, arrTable, arrTable.NumRows(),
+		iceberg.EqualTo(iceberg.Reference("int"), int32(1)), nil)
+	t.Require().NoError(err)
+	overwriteID := tbl.CurrentSnapshot().SnapshotID
+
+	type change struct {
+		changeType string
+		ordinal    int32
+		snapshotID int64
+	}
+
+	scanChanges := func(scan *table.ChangelogScan) map[change]int {
+		result, err := scan.ToArrowTable(t.ctx)
+		t.Require().NoError(err)
+		defer result.Release()
+
+		sc, n := result.Schema(), int(result.NumCols())
+		t.Equal(table.ChangeTypeColumnName, sc.Field(n-3).Name)
+		t.Equal(table.ChangeOrdinalColumnName, sc.Field(n-2).Name)
+		t.Equal(table.CommitSnapshotIDColumnName, sc.Field(n-1).Name)
+
+		changes := make(map[change]int)
+		rdr := array.NewTableReader(result, -1)
+		defer rdr.Release()
+		for rdr.Next() {
+			rec := rdr.Record()
+			types := rec.Column(n - 3).(*array.String)
+			ordinals := rec.Column(n - 2).(*array.Int32)
+			snapshotIDs := rec.Column(n - 1).(*array.Int64)
+			for i := range int(rec.NumRows()) {
+				changes[change{types.Value(i), ordinals.Value(i), snapshotIDs.Value(i)}]++
+			}
+		}
+
+		return changes
+	}
+
+	t.Equal(map[change]int{
+		{"INSERT", 0, appendID}:    3,
+		{"DELETE", 1, overwriteID}: 3,
+		{"INSERT", 1, overwriteID}: 5,
+	}, scanChanges(tbl.ChangelogScan()))
+
+	t.Equal(map[change]int{
+		{"DELETE", 0, overwriteID}: 3,
+		{"INSERT", 0, overwriteID}: 5,
+	}, scanChanges(tbl.ChangelogScan().FromSnapshotExclusive(appendID)))
+
+	t.Equal(map[change]int{
+		{"INSERT", 0, appendID}: 3,
+	}, scanChanges(tbl.ChangelogScan().ToSnapshot(appendID)))
+
+	if t.formatVersion != 2 {
+		return
+	}
+
+	// the rows deleted by position deletes are read from the data files
+	tbl, err = tbl.Delete(t.ctx, iceberg.EqualTo(iceberg.Reference("int"), int32(9)), nil)
+	t.Require().NoError(err)
+	deleteID := tbl.CurrentSnapshot().SnapshotID
+
+	t.Equal(map[change]int{
+		{"DELETE", 0, deleteID}: 2,
+	}, scanChanges(tbl.ChangelogScan().FromSnapshotExclusive(overwriteID)))
+
+	// rows already deleted aren't deleted again by the removal of the files
+	tbl, err = tbl.OverwriteTable(t.ctx, arrTable, arrTable.NumRows(), nil, nil)
+	t.Require().NoError(err)
+
+	t.Equal(map[change]int{
+		{"DELETE", 0, tbl.CurrentSnapshot().SnapshotID}: 3,
+		{"INSERT", 0, tbl.CurrentSnapshot().SnapshotID}: 3,
+	}, scanChanges(tbl.ChangelogScan().FromSnapshotExclusive(deleteID)))
+}
+
+func (t *TableWritingTestSuite) TestManageSnapshots() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "manage_snapshots_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	snapshots := make([]int64, 0, 3)
+	for range 3 {
+		var err error
+		tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+		t.Require().NoError(err)
+		snapshots = append(snapshots, tbl.CurrentSnapshot().SnapshotID)
+		// keep the timestamps of the snapshots distinct for RollbackToTime
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	manage := func(tbl *table.Table, f func(*table.ManageSnapshots)) (*table.Table, error) {
+		txn := tbl.NewTransaction()
+		ms := txn.ManageSnapshots()
+		f(ms)
+		if err := ms.Commit(); err != nil {
+			return nil, err
+		}
+
+		return txn.Commit(t.ctx)
+	}
+
+	scanRows := func(tbl *table.Table) int64 {
+		result, err := tbl.Scan().ToArrowTable(t.ctx)
+		t.Require().NoError(err)
+		defer result.Release()
+
+		return result.NumRows()
+	}
+
+	tbl, err := manage(tbl, func(ms *table.ManageSnapshots) {
+		ms.CreateTag("v1", snapshots[0], table.WithMaxRefAgeMs(3600000)).
+			CreateBranch("audit", snapshots[1], table.WithMinSnapshotsToKeep(2)).
+			RollbackTo(snapshots[1])
+	})
+	t.Require().NoError(err)
+	t.Equal(snapshots[1], tbl.CurrentSnapshot().SnapshotID)
+	t.Equal(snapshots[0], tbl.SnapshotByName("v1").SnapshotID)
+	t.Equal(snapshots[1], tbl.SnapshotByName("audit").SnapshotID)
+	t.EqualValues(6, scanRows(tbl))
+
+	refs := maps.Collect(tbl.Metadata().Refs())
+	t.Equal(table.TagRef, refs["v1"].SnapshotRefType)
+	t.EqualValues(3600000, *refs["v1"].MaxRefAgeMs)
+	t.Equal(table.BranchRef, refs["audit"].SnapshotRefType)
+	t.EqualValues(2, *refs["audit"].MinSnapshotsToKeep)
+
+	_, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.RollbackTo(snapshots[2]) })
+	t.ErrorIs(err, table.ErrInvalidOperation)
+	_, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.CreateTag("v1", snapshots[1]) })
+	t.ErrorIs(err, table.ErrInvalidOperation)
+	_, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.RemoveBranch(table.MainBranch) })
+	t.ErrorIs(err, table.ErrInvalidOperation)
+	_, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.RemoveTag("audit") })
+	t.ErrorIs(err, table.ErrInvalidOperation)
+
+	tbl, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.SetCurrentSnapshot(snapshots[2]) })
+	t.Require().NoError(err)
+	t.Equal(snapshots[2], tbl.CurrentSnapshot().SnapshotID)
+
+	tbl, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.FastForward("audit", table.MainBranch) })
+	t.Require().NoError(err)
+	t.Equal(snapshots[2], tbl.SnapshotByName("audit").SnapshotID)
+	// the retention of the branch is kept
+	t.EqualValues(2, *maps.Collect(tbl.Metadata().Refs())["audit"].MinSnapshotsToKeep)
+
+	_, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.FastForward("audit", "v1") })
+	t.ErrorIs(err, table.ErrInvalidOperation)
+
+	tbl, err = manage(tbl, func(ms *table.ManageSnapshots) {
+		ms.RollbackToTime(tbl.SnapshotByID(snapshots[1]).TimestampMs+1).
+			ReplaceBranch("audit", snapshots[0])
+	})
+	t.Require().NoError(err)
+	t.Equal(snapshots[1], tbl.CurrentSnapshot().SnapshotID)
+	t.Equal(snapshots[0], tbl.SnapshotByName("audit").SnapshotID)
+
+	// the parent of the third snapshot is current again
+	tbl, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.CherryPick(t.ctx, snapshots[2]) })
+	t.Require().NoError(err)
+	t.Equal(snapshots[2], tbl.CurrentSnapshot().SnapshotID)
+
+	tbl, err = manage(tbl, func(ms *table.ManageSnapshots) {
+		ms.RollbackTo(snapshots[0]).CherryPick(t.ctx, snapshots[2])
+	})
+	t.Require().NoError(err)
+	t.NotContains(snapshots, tbl.CurrentSnapshot().SnapshotID)
+	t.Equal(snapshots[0], *tbl.CurrentSnapshot().ParentSnapshotID)
+	t.Equal(strconv.FormatInt(snapshots[2], 10),
+		tbl.CurrentSnapshot().Summary.Properties[table.SourceSnapshotIDKey])
+	t.EqualValues(6, scanRows(tbl))
+
+	_, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.CherryPick(t.ctx, snapshots[2]) })
+	t.ErrorIs(err, table.ErrInvalidOperation)
+
+	tbl, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.RemoveTag("v1").RemoveBranch("audit") })
+	t.Require().NoError(err)
+	t.Nil(tbl.SnapshotByName("v1"))
+	t.Nil(tbl.SnapshotByName("audit"))
+
+	// each change requires the refs it changes to be unchanged
+	txn := tbl.NewTransaction()
+	t.Require().NoError(txn.ManageSnapshots().CreateTag("v2", snapshots[0]).Commit())
+	_, err = manage(tbl, func(ms *table.ManageSnapshots) { ms.CreateTag("v2", snapshots[1]) })
+	t.Require().NoError(err)
+	_, err = txn.Commit(t.ctx)
+	t.ErrorIs(err, table.ErrCommitFailed)
+
+	// the operations see the changes of the ones before them
+	tbl, err = manage(tbl, func(ms *table.ManageSnapshots) {
+		ms.CreateBranch("dev", snapshots[0]).FastForward("dev", table.MainBranch).
+			CreateTag("v3", snapshots[1]).RemoveTag("v3")
+	})
+	t.Require().NoError(err)
+	t.Equal(tbl.CurrentSnapshot().SnapshotID, tbl.SnapshotByName("dev").SnapshotID)
+	t.Nil(tbl.SnapshotByName("v3"))
+
+	// nothing is staged when an operation fails
+	txn = tbl.NewTransaction()
+	err = txn.ManageSnapshots().CreateTag("v4", snapshots[0]).RemoveBranch("missing").Commit()
+	t.ErrorIs(err, iceberg.ErrInvalidArgument)
+	tbl, err = txn.Commit(t.ctx)
+	t.Require().NoError(err)
+	t.Nil(tbl.SnapshotByName("v4"))
+}
+
+func (t *TableWritingTestSuite) TestWriteToBranch() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "branch_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	tbl, err := tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+	mainID := tbl.CurrentSnapshot().SnapshotID
+
+	scanRows := func(tbl *table.Table, opts ...table.ScanOption) int64 {
+		result, err := tbl.Scan(opts...).ToArrowTable(t.ctx)
+		t.Require().NoError(err)
+		defer result.Release()
+
+		return result.NumRows()
+	}
+
+	appendTo := func(tbl *table.Table, branch string) (*table.Table, error) {
+		txn := tbl.NewTransaction(table.WithBranch(branch))
+		if err := txn.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil); err != nil {
+			return nil, err
+		}
+
+		return txn.Commit(t.ctx)
+	}
+
+	// the branch is created from the current snapshot
+	tbl, err = appendTo(tbl, "audit")
+	t.Require().NoError(err)
+	audit := tbl.SnapshotByName("audit")
+	t.Equal(mainID, tbl.CurrentSnapshot().SnapshotID)
+	t.Equal(mainID, *audit.ParentSnapshotID)
+	t.EqualValues(3, scanRows(tbl))
+	t.EqualValues(6, scanRows(tbl, table.WithRef("audit")))
+
+	// concurrent writes to the branch are rebased on its head
+	stale := tbl
+	tbl, err = appendTo(tbl, "audit")
+	t.Require().NoError(err)
+	tbl, err = appendTo(stale, "audit")
+	t.Require().NoError(err)
+	t.Equal(mainID, tbl.CurrentSnapshot().SnapshotID)
+	t.EqualValues(12, scanRows(tbl, table.WithRef("audit")))
+
+	txn := tbl.NewTransaction(table.WithBranch("audit"))
+	t.Require().NoError(txn.OverwriteTable(t.ctx, arrTable, arrTable.NumRows(),
+		iceberg.EqualTo(iceberg.Reference("int"), int32(1)), nil))
+	tbl, err = txn.Commit(t.ctx)
+	t.Require().NoError(err)
+	t.Equal(table.OpOverwrite, tbl.SnapshotByName("audit").Summary.Operation)
+	t.EqualValues(11, scanRows(tbl, table.WithRef("audit")))
+	t.EqualValues(3, scanRows(tbl))
+
+	// publish the branch
+	txn = tbl.NewTransaction()
+	t.Require().NoError(txn.ManageSnapshots().FastForward(table.MainBranch, "audit").Commit())
+	tbl, err = txn.Commit(t.ctx)
+	t.Require().NoError(err)
+	t.EqualValues(11, scanRows(tbl))
+
+	txn = tbl.NewTransaction()
+	t.Require().NoError(txn.ManageSnapshots().CreateTag("v1", mainID).Commit())
+	tbl, err = txn.Commit(t.ctx)
+	t.Require().NoError(err)
+	_, err = appendTo(tbl, "v1")
+	t.ErrorIs(err, table.ErrInvalidOperation)
+}
+
+func (t *TableWritingTestSuite) TestWriteAuditPublish() {
+	tbl := t.createTableWithProps(table.Identifier{"default", "wap_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{
+			"format-version":         strconv.Itoa(t.formatVersion),
+			table.WriteWapEnabledKey: "true",
+		}, tableSchema())
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	tbl, err := tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+	mainID := tbl.CurrentSnapshot().SnapshotID
+
+	stagedSnapshot := func(tbl *table.Table, wapID string) table.Snapshot {
+		for _, snap := range tbl.Metadata().Snapshots() {
+			if snap.Summary.Properties[table.WapIDKey] == wapID {
+				return snap
+			}
+		}
+		t.FailNow("staged snapshot not found", wapID)
+
+		return table.Snapshot{}
+	}
+
+	cherryPick := func(tbl *table.Table, id int64) (*table.Table, error) {
+		txn := tbl.NewTransaction()
+		if err := txn.ManageSnapshots().CherryPick(t.ctx, id).Commit(); err != nil {
+			return nil, err
+		}
+
+		return txn.Commit(t.ctx)
+	}
+
+	// the staged snapshot doesn't change any ref
+	tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), iceberg.Properties{table.WapIDKey: "w1"})
+	t.Require().NoError(err)
+	t.Equal(mainID, tbl.CurrentSnapshot().SnapshotID)
+	staged := stagedSnapshot(tbl, "w1")
+	t.Equal(mainID, *staged.ParentSnapshotID)
+
+	result, err := tbl.Scan(table.WithSnapshotID(staged.SnapshotID)).ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	t.EqualValues(6, result.NumRows())
+	result.Release()
+
+	// publishing fast-forwards to the staged snapshot
+	tbl, err = cherryPick(tbl, staged.SnapshotID)
+	t.Require().NoError(err)
+	t.Equal(staged.SnapshotID, tbl.CurrentSnapshot().SnapshotID)
+
+	tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), iceberg.Properties{table.WapIDKey: "w2"})
+	t.Require().NoError(err)
+	staged = stagedSnapshot(tbl, "w2")
+	tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+
+	// publishing on top of a newer snapshot creates a new snapshot
+	tbl, err = cherryPick(tbl, staged.SnapshotID)
+	t.Require().NoError(err)
+	t.Equal("w2", tbl.CurrentSnapshot().Summary.Properties[table.PublishedWapIDKey])
+	result, err = tbl.Scan().ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	t.EqualValues(12, result.NumRows())
+	result.Release()
+
+	_, err = cherryPick(tbl, staged.SnapshotID)
+	t.ErrorIs(err, table.ErrInvalidOperation)
+}
+
+func (t *TableWritingTestSuite) TestWriteSorted() {
+	ident := table.Identifier{"default", "sorted_v" + strconv.Itoa(t.formatVersion)}
+	cat := t.getInMemCatalog()
+	cat.DropTable(t.ctx, ident)
+	cat.DropNamespace(t.ctx, catalog.NamespaceFromIdent(ident))
+	t.Require().NoError(cat.CreateNamespace(t.ctx, catalog.NamespaceFromIdent(ident), nil))
+
+	tbl, err := cat.CreateTable(t.ctx, ident, tableSchema(),
+		catalog.WithProperties(iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}),
+		catalog.WithSortOrder(table.SortOrder{
+			OrderID: table.InitialSortOrderID,
+			Fields: []table.SortField{
+				{SourceID: 4, Transform: iceberg.TruncateTransform{Width: 10}, Direction: table.SortASC, NullOrder: table.NullsLast},
+				{SourceID: 2, Transform: iceberg.IdentityTransform{}, Direction: table.SortDESC, NullOrder: table.NullsFirst},
+			},
+		}),
+		catalog.WithLocation("file://"+t.location))
+	t.Require().NoError(err)
+
+	arrTable := arrowTableWithNull()
+	defer arrTable.Release()
+
+	tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+
+	tasks, err := tbl.Scan().PlanFiles(t.ctx)
+	t.Require().NoError(err)
+	t.Require().Len(tasks, 1)
+	t.Require().NotNil(tasks[0].File.SortOrderID())
+	t.Equal(tbl.Metadata().DefaultSortOrder(), *tasks[0].File.SortOrderID())
+
+	result, err := tbl.Scan(table.WithSelectedFields("string", "int")).ToArrowTable(t.ctx)
+	t.Require().NoError(err)
+	defer result.Release()
+
+	// both ints truncate to 0 so the strings break the tie, the null int
+	// sorts last
+	ints := result.Column(1).Data().Chunk(0).(*array.Int32)
+	strs := result.Column(0).Data().Chunk(0).(*array.String)
+	t.Equal([]int32{9, 1}, ints.Int32Values()[:2])
+	t.True(ints.IsNull(2))
+	t.Equal("z", strs.Value(0))
+	t.Equal("a", strs.Value(1))
+
+	// unsorted tables don't record a sort order for the files
+	tbl = t.createTableWithProps(table.Identifier{"default", "unsorted_v" + strconv.Itoa(t.formatVersion)},
+		iceberg.Properties{"format-version": strconv.Itoa(t.formatVersion)}, tableSchema())
+	tbl, err = tbl.AppendTable(t.ctx, arrTable, arrTable.NumRows(), nil)
+	t.Require().NoError(err)
+
+	tasks, err = tbl.Scan().PlanFiles(t.ctx)
+	t.Require().NoError(err)
+	t.Require().Len(tasks, 1)
+	t.Nil(tasks[0].File.SortOrderID())
+}
+
 func TestTableWriting(t *testing.T) {
 	suite.Run(t, &TableWritingTestSuite{formatVersion: 1})
 	suite.Run(t, &TableWritingTestSuite{formatVersion: 2})
+	suite.Run(t, &TableWritingTestSuite{formatVersion: 3})
 }
 
 func TestNullableStructRequiredField(t *testing.T) {
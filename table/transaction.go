@@ -22,10 +22,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"maps"
+	"math/rand/v2"
 	"runtime"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
@@ -46,25 +52,85 @@ func (s snapshotUpdate) fastAppend() *snapshotProducer {
 
 func (s snapshotUpdate) mergeOverwrite(commitUUID *uuid.UUID) *snapshotProducer {
 	op := OpOverwrite
-	if s.txn.meta.currentSnapshot() == nil {
+	if s.txn.targetSnapshot() == nil {
 		op = OpAppend
 	}
 
 	return newOverwriteFilesProducer(op, s.txn, s.io, commitUUID, s.snapshotProps)
 }
 
+func (s snapshotUpdate) delete() *snapshotProducer {
+	return newOverwriteFilesProducer(OpDelete, s.txn, s.io, nil, s.snapshotProps)
+}
+
 func (s snapshotUpdate) mergeAppend() *snapshotProducer {
 	return newMergeAppendFilesProducer(OpAppend, s.txn, s.io, nil, s.snapshotProps)
 }
 
+// IsolationLevel determines which changes committed concurrently with a
+// transaction conflict with its overwrites and deletes when the commit of
+// the transaction is retried.
+type IsolationLevel string
+
+const (
+	// IsolationSerializable fails the commit if data or delete files that
+	// may contain rows matching an overwrite or delete filter were added
+	// concurrently.
+	IsolationSerializable IsolationLevel = "serializable"
+	// IsolationSnapshot only fails the commit if delete files that may
+	// contain rows matching an overwrite or delete filter were added
+	// concurrently.
+	IsolationSnapshot IsolationLevel = "snapshot"
+)
+
+type TransactionOpt func(*Transaction)
+
+// WithIsolationLevel sets the isolation level of the transaction,
+// IsolationSerializable by default.
+func WithIsolationLevel(level IsolationLevel) TransactionOpt {
+	return func(t *Transaction) {
+		t.isolation = level
+	}
+}
+
+// WithBranch makes the writes of the transaction commit their snapshots to
+// the given branch rather than main, building on the head of the branch and
+// requiring it to be unchanged when committing. A branch which doesn't
+// exist yet is created from the current snapshot of the table.
+func WithBranch(name string) TransactionOpt {
+	return func(t *Transaction) {
+		t.branch = name
+	}
+}
+
 type Transaction struct {
 	tbl  *Table
 	meta *MetadataBuilder
 
+	isolation IsolationLevel
+	// branch is the branch the snapshots written are committed to.
+	branch string
+
 	reqs []Requirement
 
 	mx        sync.Mutex
 	committed bool
+
+	// cleanExpiredFiles is set when snapshots were expired in this
+	// transaction and their files should be removed after committing.
+	cleanExpiredFiles bool
+
+	// changes records everything staged on the transaction, in order, so
+	// that it can be re-applied on a refreshed table if the commit conflicts.
+	changes []stagedChange
+}
+
+// stagedChange is a set of updates staged on a transaction together with
+// their requirements and, for snapshot updates, the producer that created them.
+type stagedChange struct {
+	updates  []Update
+	reqs     []Requirement
+	producer *snapshotProducer
 }
 
 func (t *Transaction) apply(updates []Update, reqs []Requirement) error {
@@ -75,6 +141,29 @@ func (t *Transaction) apply(updates []Update, reqs []Requirement) error {
 		return errors.New("transaction has already been committed")
 	}
 
+	return t.applyChange(stagedChange{updates: updates, reqs: reqs})
+}
+
+// applySnapshot commits the snapshot producer and stages the resulting
+// snapshot on the transaction.
+func (t *Transaction) applySnapshot(sp *snapshotProducer) error {
+	updates, reqs, err := sp.commit()
+	if err != nil {
+		return err
+	}
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if t.committed {
+		return errors.New("transaction has already been committed")
+	}
+
+	return t.applyChange(stagedChange{updates: updates, reqs: reqs, producer: sp})
+}
+
+func (t *Transaction) applyChange(change stagedChange) error {
+	updates, reqs := change.updates, change.reqs
 	current, err := t.meta.Build()
 	if err != nil {
 		return err
@@ -88,12 +177,13 @@ func (t *Transaction) apply(updates []Update, reqs []Requirement) error {
 
 	existing := map[string]struct{}{}
 	for _, r := range t.reqs {
-		existing[r.GetType()] = struct{}{}
+		existing[requirementKey(r)] = struct{}{}
 	}
 
 	for _, r := range reqs {
-		if _, ok := existing[r.GetType()]; !ok {
+		if _, ok := existing[requirementKey(r)]; !ok {
 			t.reqs = append(t.reqs, r)
+			existing[requirementKey(r)] = struct{}{}
 		}
 	}
 
@@ -112,10 +202,28 @@ func (t *Transaction) apply(updates []Update, reqs []Requirement) error {
 			t.meta.lastUpdatedMS = time.Now().UnixMilli()
 		}
 	}
+	t.changes = append(t.changes, change)
 
 	return nil
 }
 
+// requirementKey identifies the requirements of which only the first one
+// staged on the transaction is kept, as the later ones are checked against
+// the changes staged before them. The refs are asserted separately.
+func requirementKey(r Requirement) string {
+	if a, ok := r.(*assertRefSnapshotID); ok {
+		return a.GetType() + ":" + a.Ref
+	}
+
+	return r.GetType()
+}
+
+// targetSnapshot returns the head of the branch written by the transaction,
+// or the current snapshot if the branch doesn't exist yet.
+func (t *Transaction) targetSnapshot() *Snapshot {
+	return t.meta.branchSnapshot(t.branch)
+}
+
 func (t *Transaction) appendSnapshotProducer(afs io.IO, props iceberg.Properties) *snapshotProducer {
 	manifestMerge := t.meta.props.GetBool(ManifestMergeEnabledKey, ManifestMergeEnabledDefault)
 	updateSnapshot := t.updateSnapshot(afs, props)
@@ -146,6 +254,10 @@ func (t *Transaction) UpdateSpec(caseSensitive bool) *UpdateSpec {
 	return NewUpdateSpec(t, caseSensitive)
 }
 
+func (t *Transaction) UpdateSchema(caseSensitive bool) *UpdateSchema {
+	return NewUpdateSchema(t, caseSensitive)
+}
+
 func (t *Transaction) AppendTable(ctx context.Context, tbl arrow.Table, batchSize int64, snapshotProps iceberg.Properties) error {
 	rdr := array.NewTableReader(tbl, batchSize)
 	defer rdr.Release()
@@ -173,12 +285,398 @@ func (t *Transaction) Append(ctx context.Context, rdr array.RecordReader, snapsh
 		appendFiles.appendDataFile(df)
 	}
 
-	updates, reqs, err := appendFiles.commit()
+	return t.applySnapshot(appendFiles)
+}
+
+func (t *Transaction) OverwriteTable(ctx context.Context, tbl arrow.Table, batchSize int64, filter iceberg.BooleanExpression, snapshotProps iceberg.Properties) error {
+	rdr := array.NewTableReader(tbl, batchSize)
+	defer rdr.Release()
+
+	return t.Overwrite(ctx, rdr, filter, snapshotProps)
+}
+
+// Overwrite replaces the rows of the table matching the filter with the
+// records from rdr, committing a single overwrite snapshot. A nil filter
+// overwrites every row of the table.
+//
+// This is a copy-on-write operation: data files in which every row matches
+// the filter are removed, while data files in which only some rows match
+// are rewritten without the matching rows.
+//
+// When the commit is retried after a concurrent change, files added
+// concurrently that may contain matching rows fail the commit according
+// to the isolation level of the transaction.
+func (t *Transaction) Overwrite(ctx context.Context, rdr array.RecordReader, filter iceberg.BooleanExpression, snapshotProps iceberg.Properties) error {
+	if filter == nil {
+		filter = iceberg.AlwaysTrue{}
+	}
+
+	fs, err := t.tbl.fsF(ctx)
+	if err != nil {
+		return err
+	}
+
+	matches, err := t.matchRows(ctx, fs, filter)
+	if err != nil {
+		return err
+	}
+
+	commitUUID := uuid.New()
+	updater := t.updateSnapshot(fs, snapshotProps).mergeOverwrite(&commitUUID).
+		conflictDetectionFilter(filter)
+	for _, df := range matches.files {
+		updater.deleteDataFile(df)
+	}
+
+	if err := t.rewritePartialMatches(ctx, fs, updater, matches); err != nil {
+		return err
+	}
+
+	itr := recordsToDataFiles(ctx, t.tbl.Location(), t.meta, recordWritingArgs{
+		sc:        rdr.Schema(),
+		itr:       array.IterFromReader(rdr),
+		fs:        fs.(io.WriteFileIO),
+		writeUUID: &updater.commitUuid,
+	})
+	for df, err := range itr {
+		if err != nil {
+			return err
+		}
+		updater.appendDataFile(df)
+	}
+
+	if len(updater.deletedFiles) == 0 && len(updater.addedFiles) == 0 {
+		return nil
+	}
+
+	return t.applySnapshot(updater)
+}
+
+// DynamicPartitionOverwrite replaces the data of every partition present in
+// the records from rdr with those records, leaving all other partitions
+// untouched, committing a single overwrite snapshot.
+//
+// The partitions of the records are computed with the transforms of the
+// current partition spec. Data files written with the current spec are
+// replaced when they belong to one of those partitions, while data files
+// written with an older spec are rewritten without the rows belonging to
+// them, which requires the partitions to be selectable with a row filter:
+// that is not the case of bucket partitions for example.
+//
+// When the commit is retried after a concurrent change, files added
+// concurrently to the replaced partitions fail the commit according to the
+// isolation level of the transaction.
+func (t *Transaction) DynamicPartitionOverwrite(ctx context.Context, rdr array.RecordReader, snapshotProps iceberg.Properties) error {
+	spec := t.meta.CurrentSpec()
+	if spec.IsUnpartitioned() {
+		return fmt.Errorf("%w: cannot apply dynamic overwrite on an unpartitioned table", ErrInvalidOperation)
+	}
+
+	fs, err := t.tbl.fsF(ctx)
 	if err != nil {
 		return err
 	}
 
-	return t.apply(updates, reqs)
+	commitUUID := uuid.New()
+	updater := t.updateSnapshot(fs, snapshotProps).mergeOverwrite(&commitUUID)
+
+	schema := t.meta.CurrentSchema()
+	partitionType := spec.PartitionType(schema)
+
+	itr := recordsToDataFiles(ctx, t.tbl.Location(), t.meta, recordWritingArgs{
+		sc:        rdr.Schema(),
+		itr:       array.IterFromReader(rdr),
+		fs:        fs.(io.WriteFileIO),
+		writeUUID: &updater.commitUuid,
+	})
+
+	replaced := make(map[string][]iceberg.Literal)
+	for df, err := range itr {
+		if err != nil {
+			return err
+		}
+
+		values, err := partitionLiterals(df, partitionType)
+		if err != nil {
+			return err
+		}
+		replaced[partitionKey(values)] = values
+		updater.appendDataFile(df)
+	}
+
+	if len(replaced) == 0 {
+		return nil
+	}
+
+	filter, exact, err := partitionsRowFilter(spec, schema, slices.Collect(maps.Values(replaced)))
+	if err != nil {
+		return err
+	}
+	updater.conflictDetectionFilter(filter)
+
+	matches, err := t.matchRows(ctx, fs, filter)
+	if err != nil {
+		return err
+	}
+
+	if !exact {
+		// the filter selects more rows than those of the replaced
+		// partitions, files of the current spec are replaced according to
+		// their partition and files of older specs can't be rewritten.
+		files := slices.Clone(matches.files)
+		for _, m := range matches.partial {
+			files = append(files, m.task.File)
+		}
+
+		matches.files, matches.partial = nil, nil
+		for _, df := range files {
+			if int(df.SpecID()) != spec.ID() {
+				return fmt.Errorf("%w: cannot replace the rows of data file %s written with partition spec %d, the partitions of spec %d can't be selected with a row filter",
+					iceberg.ErrNotImplemented, df.FilePath(), df.SpecID(), spec.ID())
+			}
+
+			values, err := partitionLiterals(df, partitionType)
+			if err != nil {
+				return err
+			}
+			if _, ok := replaced[partitionKey(values)]; ok {
+				matches.files = append(matches.files, df)
+			}
+		}
+	}
+
+	for _, df := range matches.files {
+		updater.deleteDataFile(df)
+	}
+
+	if err := t.rewritePartialMatches(ctx, fs, updater, matches); err != nil {
+		return err
+	}
+
+	return t.applySnapshot(updater)
+}
+
+// rewritePartialMatches removes the partially matching files of the matches
+// with the updater, adding new data files holding their rows which don't
+// match.
+func (t *Transaction) rewritePartialMatches(ctx context.Context, fs io.IO, updater *snapshotProducer, matches rowMatches) error {
+	if len(matches.partial) == 0 {
+		return nil
+	}
+
+	sc, records, err := t.survivingRows(ctx, fs, matches)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range matches.partial {
+		updater.deleteDataFile(m.task.File)
+	}
+
+	rewritten := recordsToDataFiles(ctx, t.tbl.Location(), t.meta, recordWritingArgs{
+		sc:  sc,
+		itr: records,
+		fs:  fs.(io.WriteFileIO),
+	})
+	for df, err := range rewritten {
+		if err != nil {
+			return err
+		}
+		updater.appendDataFile(df)
+	}
+
+	return nil
+}
+
+// partitionKey identifies a partition by its values, where a nil literal is
+// a null value. Null values are distinct from any other value, such as the
+// string "null".
+func partitionKey(values []iceberg.Literal) string {
+	var key strings.Builder
+	for _, v := range values {
+		if v == nil {
+			key.WriteByte('N')
+
+			continue
+		}
+
+		s := v.String()
+		key.WriteByte('V')
+		key.WriteString(strconv.Itoa(len(s)))
+		key.WriteByte(':')
+		key.WriteString(s)
+	}
+
+	return key.String()
+}
+
+// partitionLiterals returns the partition values of the data file as
+// literals of the types of the partition fields, or nil for null values.
+// Values are converted as they are held in different Go types depending on
+// whether the data file was just written or read from a manifest.
+func partitionLiterals(df iceberg.DataFile, partitionType *iceberg.StructType) ([]iceberg.Literal, error) {
+	data := df.Partition()
+	out := make([]iceberg.Literal, len(partitionType.FieldList))
+	for i, f := range partitionType.FieldList {
+		var lit iceberg.Literal
+		switch v := data[f.ID].(type) {
+		case nil:
+			continue
+		case int:
+			lit = iceberg.Int64Literal(v)
+		case int32:
+			lit = iceberg.Int32Literal(v)
+		case int64:
+			lit = iceberg.Int64Literal(v)
+		case bool:
+			lit = iceberg.BoolLiteral(v)
+		case float32:
+			lit = iceberg.Float32Literal(v)
+		case float64:
+			lit = iceberg.Float64Literal(v)
+		case string:
+			lit = iceberg.StringLiteral(v)
+		case []byte:
+			lit = iceberg.BinaryLiteral(v)
+		case iceberg.Date:
+			// day partition values written with the date logical type,
+			// as Spark does, hold the days from epoch of the int result
+			if _, ok := f.Type.(iceberg.Int32Type); ok {
+				lit = iceberg.Int32Literal(v)
+			} else {
+				lit = iceberg.DateLiteral(v)
+			}
+		case iceberg.Time:
+			lit = iceberg.TimeLiteral(v)
+		case iceberg.Timestamp:
+			lit = iceberg.TimestampLiteral(v)
+		case uuid.UUID:
+			lit = iceberg.UUIDLiteral(v)
+		case iceberg.Decimal:
+			lit = iceberg.DecimalLiteral(v)
+		default:
+			return nil, fmt.Errorf("%w: unsupported value %v of partition field %s of data file %s",
+				iceberg.ErrInvalidArgument, v, f.Name, df.FilePath())
+		}
+
+		lit, err := lit.To(f.Type)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = lit
+	}
+
+	return out, nil
+}
+
+// partitionsRowFilter returns a row filter selecting the rows belonging to
+// any of the partitions of the spec with the given values. The filter is
+// exact when the transforms of the spec can be expressed on their source
+// columns, otherwise it selects a superset of those rows: for example the
+// rows of a bucket partition can't be selected by a row filter, so the
+// bucket fields are left out of it.
+func partitionsRowFilter(spec iceberg.PartitionSpec, schema *iceberg.Schema, partitions [][]iceberg.Literal) (filter iceberg.BooleanExpression, exact bool, err error) {
+	exact = true
+	filter = iceberg.AlwaysFalse{}
+	fields := slices.Collect(spec.Fields())
+	for _, values := range partitions {
+		partFilter := iceberg.BooleanExpression(iceberg.AlwaysTrue{})
+		for i, f := range fields {
+			fieldFilter, fieldExact, err := partitionFieldRowFilter(f, schema, values[i])
+			if err != nil {
+				return nil, false, err
+			}
+
+			exact = exact && fieldExact
+			partFilter = iceberg.NewAnd(partFilter, fieldFilter)
+		}
+		filter = iceberg.NewOr(filter, partFilter)
+	}
+
+	return filter, exact, nil
+}
+
+// partitionFieldRowFilter returns a row filter selecting the rows for which
+// the partition field has the value, a nil literal being a null value, and
+// whether that filter is exact.
+func partitionFieldRowFilter(f iceberg.PartitionField, schema *iceberg.Schema, value iceberg.Literal) (iceberg.BooleanExpression, bool, error) {
+	source, ok := schema.FindFieldByID(f.SourceID)
+	if !ok {
+		return nil, false, fmt.Errorf("%w: could not find source field %d for partition field %s",
+			iceberg.ErrInvalidSchema, f.SourceID, f.Name)
+	}
+
+	name, _ := schema.FindColumnName(f.SourceID)
+	ref := iceberg.Reference(name)
+
+	if _, ok := f.Transform.(iceberg.VoidTransform); ok {
+		return iceberg.AlwaysTrue{}, true, nil
+	}
+
+	if value == nil {
+		// every transform except void maps only null to null
+		return iceberg.IsNull(ref), true, nil
+	}
+
+	between := func(lower, upper iceberg.Literal) iceberg.BooleanExpression {
+		return iceberg.NewAnd(iceberg.LiteralPredicate(iceberg.OpGTEQ, ref, lower),
+			iceberg.LiteralPredicate(iceberg.OpLT, ref, upper))
+	}
+
+	switch t := f.Transform.(type) {
+	case iceberg.IdentityTransform:
+		return iceberg.LiteralPredicate(iceberg.OpEQ, ref, value), true, nil
+	case iceberg.YearTransform, iceberg.MonthTransform, iceberg.DayTransform, iceberg.HourTransform:
+		var n int
+		switch v := value.(type) {
+		case iceberg.Int32Literal:
+			n = int(v)
+		case iceberg.DateLiteral:
+			n = int(v)
+		default:
+			return nil, false, fmt.Errorf("%w: invalid value %s of partition field %s",
+				iceberg.ErrInvalidArgument, value, f.Name)
+		}
+		epoch := time.Unix(0, 0).UTC()
+		var start, end time.Time
+		switch t.(type) {
+		case iceberg.YearTransform:
+			start, end = epoch.AddDate(n, 0, 0), epoch.AddDate(n+1, 0, 0)
+		case iceberg.MonthTransform:
+			start, end = epoch.AddDate(0, n, 0), epoch.AddDate(0, n+1, 0)
+		case iceberg.DayTransform:
+			start, end = epoch.AddDate(0, 0, n), epoch.AddDate(0, 0, n+1)
+		case iceberg.HourTransform:
+			start, end = epoch.Add(time.Duration(n)*time.Hour), epoch.Add(time.Duration(n+1)*time.Hour)
+		}
+
+		switch source.Type.(type) {
+		case iceberg.DateType:
+			const secondsPerDay = 24 * 60 * 60
+
+			return between(iceberg.DateLiteral(start.Unix()/secondsPerDay),
+				iceberg.DateLiteral(end.Unix()/secondsPerDay)), true, nil
+		case iceberg.TimestampType, iceberg.TimestampTzType:
+			return between(iceberg.TimestampLiteral(start.UnixMicro()),
+				iceberg.TimestampLiteral(end.UnixMicro())), true, nil
+		}
+	case iceberg.TruncateTransform:
+		switch v := value.(type) {
+		case iceberg.Int32Literal:
+			return between(v, iceberg.Int64Literal(int64(v)+int64(t.Width))), true, nil
+		case iceberg.Int64Literal:
+			return between(v, v+iceberg.Int64Literal(t.Width)), true, nil
+		case iceberg.StringLiteral:
+			// strings are truncated to a number of code points
+			if utf8.RuneCountInString(string(v)) < t.Width {
+				return iceberg.LiteralPredicate(iceberg.OpEQ, ref, v), true, nil
+			}
+
+			return iceberg.StartsWith(ref, string(v)), true, nil
+		}
+	}
+
+	return iceberg.AlwaysTrue{}, false, nil
 }
 
 // ReplaceFiles is actually just an overwrite operation with multiple
@@ -217,7 +715,7 @@ func (t *Transaction) ReplaceDataFiles(ctx context.Context, filesToDelete, files
 		return errors.New("add file paths must be unique for ReplaceDataFiles")
 	}
 
-	s := t.meta.currentSnapshot()
+	s := t.targetSnapshot()
 	if s == nil {
 		return fmt.Errorf("%w: cannot replace files in a table without an existing snapshot", ErrInvalidOperation)
 	}
@@ -272,12 +770,7 @@ func (t *Transaction) ReplaceDataFiles(ctx context.Context, filesToDelete, files
 		updater.appendDataFile(df)
 	}
 
-	updates, reqs, err := updater.commit()
-	if err != nil {
-		return err
-	}
-
-	return t.apply(updates, reqs)
+	return t.applySnapshot(updater)
 }
 
 func (t *Transaction) AddFiles(ctx context.Context, files []string, snapshotProps iceberg.Properties, ignoreDuplicates bool) error {
@@ -291,7 +784,7 @@ func (t *Transaction) AddFiles(ctx context.Context, files []string, snapshotProp
 	}
 
 	if !ignoreDuplicates {
-		if s := t.meta.currentSnapshot(); s != nil {
+		if s := t.targetSnapshot(); s != nil {
 			referenced := make([]string, 0)
 			fs, err := t.tbl.fsF(ctx)
 			if err != nil {
@@ -339,12 +832,7 @@ func (t *Transaction) AddFiles(ctx context.Context, files []string, snapshotProp
 		updater.appendDataFile(df)
 	}
 
-	updates, reqs, err := updater.commit()
-	if err != nil {
-		return err
-	}
-
-	return t.apply(updates, reqs)
+	return t.applySnapshot(updater)
 }
 
 func (t *Transaction) Scan(opts ...ScanOption) (*Scan, error) {
@@ -367,6 +855,10 @@ func (t *Transaction) Scan(opts ...ScanOption) (*Scan, error) {
 		opt(s)
 	}
 
+	if s.err != nil {
+		return nil, s.err
+	}
+
 	s.partitionFilters = newKeyDefaultMapWrapErr(s.buildPartitionProjection)
 
 	return s, nil
@@ -399,13 +891,185 @@ func (t *Transaction) Commit(ctx context.Context) (*Table, error) {
 
 	t.committed = true
 
-	if len(t.meta.updates) > 0 {
-		t.reqs = append(t.reqs, AssertTableUUID(t.meta.uuid))
+	if len(t.meta.updates) == 0 {
+		return t.tbl, nil
+	}
 
-		return t.tbl.doCommit(ctx, t.meta.updates, t.reqs)
+	// the producers are kept across refreshes, so the manifests of their
+	// last attempt can be deleted when the commit is abandoned
+	var producers []*snapshotProducer
+	for _, change := range t.changes {
+		if change.producer != nil {
+			producers = append(producers, change.producer)
+		}
+	}
+	abandon := func() {
+		for _, sp := range producers {
+			sp.deleteWritten()
+		}
 	}
 
-	return t.tbl, nil
+	retry := newCommitRetry(t.meta.props)
+	for attempt := 1; ; attempt++ {
+		reqs := append(slices.Clone(t.reqs), AssertTableUUID(t.meta.uuid))
+
+		tbl, err := t.tbl.doCommit(ctx, t.meta.updates, reqs)
+		if err == nil {
+			if t.cleanExpiredFiles {
+				fs, err := tbl.fsF(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				if err := deleteExpiredFiles(fs, t.tbl.metadata, tbl.metadata); err != nil {
+					log.Printf("Warning: Failed to clean up expired snapshot files: %v", err)
+				}
+			}
+
+			return tbl, nil
+		}
+
+		if !errors.Is(err, ErrCommitFailed) || !retry.wait(ctx, attempt) {
+			// the snapshots may have been committed when the catalog
+			// can't tell whether the commit succeeded
+			if !errors.Is(err, ErrCommitStateUnknown) {
+				abandon()
+			}
+
+			return nil, err
+		}
+
+		if err := t.refresh(ctx, attempt); err != nil {
+			abandon()
+
+			return nil, err
+		}
+	}
+}
+
+// refresh reloads the table from its catalog and re-applies every change
+// staged on the transaction on top of it. Requirements are validated again
+// against the refreshed table, overwrites and deletes are validated against
+// the concurrent snapshots according to the isolation level, and snapshots
+// are re-written with new manifests while reusing the data files that were
+// already written.
+func (t *Transaction) refresh(ctx context.Context, attempt int) error {
+	tbl, err := t.tbl.Refresh(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := AssertTableUUID(t.meta.uuid).Validate(tbl.metadata); err != nil {
+		return err
+	}
+
+	// the snapshots committed to the branch written by the transaction
+	// since the transaction was last applied on top of it
+	var (
+		base       = branchHead(t.tbl.metadata, t.branch)
+		concurrent []*Snapshot
+		found      = base == nil
+	)
+	for snap := range ancestorsOf(tbl.metadata, branchHead(tbl.metadata, t.branch)) {
+		if base != nil && snap.SnapshotID == base.SnapshotID {
+			found = true
+
+			break
+		}
+		concurrent = append(concurrent, snap)
+	}
+
+	if !found {
+		return fmt.Errorf("%w: snapshot %d is no longer an ancestor of the current snapshot",
+			ErrValidationFailed, base.SnapshotID)
+	}
+
+	meta, err := MetadataBuilderFromBase(tbl.metadata)
+	if err != nil {
+		return err
+	}
+
+	changes := t.changes
+	t.tbl, t.meta, t.reqs, t.changes = tbl, meta, nil, nil
+	for _, change := range changes {
+		if change.producer != nil {
+			if err := change.producer.rebase(attempt, concurrent); err != nil {
+				return err
+			}
+
+			change.updates, change.reqs, err = change.producer.commit()
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := t.applyChange(change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// branchHead returns the snapshot the branch refers to, or the current
+// snapshot if there is no such branch.
+func branchHead(meta Metadata, branch string) *Snapshot {
+	if ref, ok := lookupRef(meta, branch); ok {
+		return meta.SnapshotByID(ref.SnapshotID)
+	}
+
+	return meta.CurrentSnapshot()
+}
+
+// commitRetry is the backoff between commit attempts configured by
+// the commit.retry.* table properties.
+type commitRetry struct {
+	numRetries       int
+	minWait, maxWait time.Duration
+	deadline         time.Time
+}
+
+func newCommitRetry(props iceberg.Properties) commitRetry {
+	ms := func(key string, def int) time.Duration {
+		return time.Duration(props.GetInt(key, def)) * time.Millisecond
+	}
+
+	return commitRetry{
+		numRetries: props.GetInt(CommitNumRetriesKey, CommitNumRetriesDefault),
+		minWait:    ms(CommitMinRetryWaitMsKey, CommitMinRetryWaitMsDefault),
+		maxWait:    ms(CommitMaxRetryWaitMsKey, CommitMaxRetryWaitMsDefault),
+		deadline:   time.Now().Add(ms(CommitTotalRetryTimeoutMsKey, CommitTotalRetryTimeoutMsDefault)),
+	}
+}
+
+// wait sleeps before the given retry, numbered from 1, returning false
+// without sleeping if the retries or the total timeout are exhausted.
+func (r commitRetry) wait(ctx context.Context, retry int) bool {
+	if retry > r.numRetries {
+		return false
+	}
+
+	delay := r.minWait
+	for i := 1; i < retry && delay < r.maxWait; i++ {
+		delay *= 2
+	}
+	delay = min(delay, r.maxWait)
+	// add up to 10% jitter so that conflicting writers spread out
+	delay += time.Duration(rand.Int64N(int64(delay)/10 + 1))
+
+	if time.Now().Add(delay).After(r.deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
 }
 
 type StagedTable struct {
@@ -0,0 +1,76 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDayPartitionWithDateLogicalType(t *testing.T) {
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "ts", Type: iceberg.PrimitiveTypes.Timestamp, Required: true},
+		iceberg.NestedField{ID: 2, Name: "dt", Type: iceberg.PrimitiveTypes.Date, Required: true})
+	daySpec := iceberg.NewPartitionSpec(
+		iceberg.PartitionField{SourceID: 1, FieldID: 1000, Transform: iceberg.DayTransform{}, Name: "ts_day"})
+
+	// Spark writes the day partition values with the date logical type,
+	// which an identity partition of a date column is written with too
+	dateSpec := iceberg.NewPartitionSpec(
+		iceberg.PartitionField{SourceID: 2, FieldID: 1000, Transform: iceberg.IdentityTransform{}, Name: "ts_day"})
+	bldr, err := iceberg.NewDataFileBuilder(dateSpec, iceberg.EntryContentData, "data.parquet",
+		iceberg.ParquetFile, map[int]any{1000: iceberg.Date(19800)}, 1, 100)
+	require.NoError(t, err)
+
+	var snapshotID int64 = 1
+	var buf bytes.Buffer
+	mf, err := iceberg.WriteManifest("manifest.avro", &buf, 2, dateSpec, sc, snapshotID,
+		[]iceberg.ManifestEntry{iceberg.NewManifestEntryBuilder(iceberg.EntryStatusADDED, &snapshotID, bldr.Build()).Build()})
+	require.NoError(t, err)
+
+	entries, err := iceberg.ReadManifest(mf, &buf, false)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	df := entries[0].DataFile()
+	require.Equal(t, map[int]any{1000: iceberg.Date(19800)}, df.Partition())
+
+	values, err := partitionLiterals(df, daySpec.PartitionType(sc))
+	require.NoError(t, err)
+	assert.Equal(t, []iceberg.Literal{iceberg.Int32Literal(19800)}, values)
+
+	filter, exact, err := partitionsRowFilter(daySpec, sc, [][]iceberg.Literal{values})
+	require.NoError(t, err)
+	assert.True(t, exact)
+
+	const microsPerDay = 24 * 60 * 60 * 1_000_000
+	ref := iceberg.Reference("ts")
+	day := iceberg.NewAnd(
+		iceberg.LiteralPredicate(iceberg.OpGTEQ, ref, iceberg.TimestampLiteral(19800*microsPerDay)),
+		iceberg.LiteralPredicate(iceberg.OpLT, ref, iceberg.TimestampLiteral(19801*microsPerDay)))
+	assert.True(t, day.Equals(filter), "%s != %s", day, filter)
+
+	// a date literal of a day partition holds its days from epoch too
+	fieldFilter, _, err := partitionFieldRowFilter(slices.Collect(daySpec.Fields())[0], sc, iceberg.DateLiteral(19800))
+	require.NoError(t, err)
+	assert.True(t, day.Equals(fieldFilter), "%s != %s", day, fieldFilter)
+}
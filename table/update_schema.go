@@ -0,0 +1,702 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/apache/iceberg-go"
+)
+
+// tableRootID is the parent ID used for columns added at the top level
+// of the schema.
+const tableRootID = -1
+
+type moveOp string
+
+const (
+	moveFirst  moveOp = "first"
+	moveBefore moveOp = "before"
+	moveAfter  moveOp = "after"
+)
+
+type schemaMove struct {
+	fieldID    int
+	relativeTo int
+	op         moveOp
+}
+
+// UpdateSchema implements a builder for evolving a table's schema.
+//
+// It accumulates a sequence of schema update operations (e.g., AddColumn, DeleteColumn,
+// RenameColumn, UpdateColumn) which are applied during BuildUpdates. New field IDs are
+// assigned starting after the table's last assigned column ID.
+//
+// Use the builder methods to chain operations, and call BuildUpdates to apply them and produce the
+// final set of updates and requirements, or call Commit to apply the updates in the transaction.
+type UpdateSchema struct {
+	operations []updateSchemaOp
+
+	txn                      *Transaction
+	schema                   *iceberg.Schema
+	lastColumnID             int
+	caseSensitive            bool
+	allowIncompatibleChanges bool
+
+	idToParent           map[int]int
+	deletes              map[int]bool
+	updates              map[int]iceberg.NestedField
+	adds                 map[int][]iceberg.NestedField
+	addedNameToID        map[string]int
+	moves                map[int][]schemaMove
+	identifierFieldNames []string
+}
+
+type updateSchemaOp func() error
+
+func NewUpdateSchema(t *Transaction, caseSensitive bool) *UpdateSchema {
+	schema := t.meta.CurrentSchema()
+	idToParent, _ := iceberg.IndexParents(schema)
+
+	identifierFieldNames := make([]string, 0, len(schema.IdentifierFieldIDs))
+	for _, id := range schema.IdentifierFieldIDs {
+		name, _ := schema.FindColumnName(id)
+		identifierFieldNames = append(identifierFieldNames, name)
+	}
+
+	return &UpdateSchema{
+		txn:                  t,
+		schema:               schema,
+		lastColumnID:         max(t.meta.lastColumnId, schema.HighestFieldID()),
+		caseSensitive:        caseSensitive,
+		idToParent:           idToParent,
+		deletes:              make(map[int]bool),
+		updates:              make(map[int]iceberg.NestedField),
+		adds:                 make(map[int][]iceberg.NestedField),
+		addedNameToID:        make(map[string]int),
+		moves:                make(map[int][]schemaMove),
+		identifierFieldNames: identifierFieldNames,
+	}
+}
+
+// AllowIncompatibleChanges permits changes that could break readers of
+// existing data, such as adding a required column.
+func (us *UpdateSchema) AllowIncompatibleChanges() *UpdateSchema {
+	us.allowIncompatibleChanges = true
+
+	return us
+}
+
+// AddColumn adds a new column to the schema. The path is the list of names
+// leading to the new column, all but the last of which must resolve to an
+// existing struct, or to a list or map whose element or value is a struct.
+func (us *UpdateSchema) AddColumn(path []string, fieldType iceberg.Type, doc string, required bool) *UpdateSchema {
+	us.operations = append(us.operations, us.addColumn(path, fieldType, doc, required))
+
+	return us
+}
+
+// DeleteColumn removes the column with the given name from the schema.
+func (us *UpdateSchema) DeleteColumn(name string) *UpdateSchema {
+	us.operations = append(us.operations, us.deleteColumn(name))
+
+	return us
+}
+
+// RenameColumn changes the name of the column with the given name. Only the
+// last part of the name is changed, nested columns keep their parent.
+func (us *UpdateSchema) RenameColumn(name string, newName string) *UpdateSchema {
+	us.operations = append(us.operations, us.renameColumn(name, newName))
+
+	return us
+}
+
+// UpdateColumn promotes the type of a primitive column. Only promotions
+// allowed by the Iceberg spec are accepted: int to long, float to double
+// and widening the precision of a decimal.
+func (us *UpdateSchema) UpdateColumn(name string, newType iceberg.Type) *UpdateSchema {
+	us.operations = append(us.operations, us.updateColumn(name, newType))
+
+	return us
+}
+
+// UpdateColumnDoc sets the documentation string of a column.
+func (us *UpdateSchema) UpdateColumnDoc(name string, doc string) *UpdateSchema {
+	us.operations = append(us.operations, us.updateColumnDoc(name, doc))
+
+	return us
+}
+
+// MakeOptional changes a required column to be optional.
+func (us *UpdateSchema) MakeOptional(name string) *UpdateSchema {
+	us.operations = append(us.operations, us.makeOptional(name))
+
+	return us
+}
+
+// MoveFirst moves the column to be the first column in its struct.
+func (us *UpdateSchema) MoveFirst(name string) *UpdateSchema {
+	us.operations = append(us.operations, us.move(name, "", moveFirst))
+
+	return us
+}
+
+// MoveBefore moves the column so it is directly before the column named
+// beforeName. Both columns must be in the same struct.
+func (us *UpdateSchema) MoveBefore(name, beforeName string) *UpdateSchema {
+	us.operations = append(us.operations, us.move(name, beforeName, moveBefore))
+
+	return us
+}
+
+// MoveAfter moves the column so it is directly after the column named
+// afterName. Both columns must be in the same struct.
+func (us *UpdateSchema) MoveAfter(name, afterName string) *UpdateSchema {
+	us.operations = append(us.operations, us.move(name, afterName, moveAfter))
+
+	return us
+}
+
+// SetIdentifierFields replaces the identifier fields of the schema with the
+// columns with the given names.
+func (us *UpdateSchema) SetIdentifierFields(names ...string) *UpdateSchema {
+	us.operations = append(us.operations, func() error {
+		us.identifierFieldNames = slices.Clone(names)
+
+		return nil
+	})
+
+	return us
+}
+
+func (us *UpdateSchema) BuildUpdates() ([]Update, []Requirement, error) {
+	newSchema, err := us.Apply()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updates := make([]Update, 0)
+	requirements := []Requirement{AssertCurrentSchemaID(us.schema.ID)}
+
+	if newSchema.Equals(us.schema) {
+		return updates, requirements, nil
+	}
+
+	for _, s := range us.txn.meta.schemaList {
+		if newSchema.Equals(s) {
+			updates = append(updates, NewSetCurrentSchemaUpdate(s.ID))
+
+			return updates, requirements, nil
+		}
+	}
+
+	updates = append(updates,
+		NewAddSchemaUpdate(newSchema, us.lastColumnID, false),
+		NewSetCurrentSchemaUpdate(-1))
+
+	return updates, requirements, nil
+}
+
+// Apply runs the pending operations and returns the resulting schema.
+// The returned schema is assigned the next unused schema ID of the table.
+func (us *UpdateSchema) Apply() (*iceberg.Schema, error) {
+	ops := us.operations
+	us.operations = nil
+	for _, op := range ops {
+		if err := op(); err != nil {
+			return nil, err
+		}
+	}
+
+	fields, err := us.applyStruct(us.schema.Fields(), tableRootID)
+	if err != nil {
+		return nil, err
+	}
+
+	newSchemaID := 0
+	for _, s := range us.txn.meta.schemaList {
+		newSchemaID = max(newSchemaID, s.ID+1)
+	}
+
+	tmp := iceberg.NewSchema(newSchemaID, fields...)
+	identifierIDs := make([]int, 0, len(us.identifierFieldNames))
+	for _, name := range us.identifierFieldNames {
+		field, ok := tmp.FindFieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot find identifier field %s in the new schema",
+				iceberg.ErrInvalidSchema, name)
+		}
+		if err := validateIdentifierField(tmp, field); err != nil {
+			return nil, err
+		}
+		identifierIDs = append(identifierIDs, field.ID)
+	}
+
+	return iceberg.NewSchemaWithIdentifiers(newSchemaID, identifierIDs, fields...), nil
+}
+
+func (us *UpdateSchema) Commit() error {
+	updates, requirements, err := us.BuildUpdates()
+	if err != nil {
+		return err
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return us.txn.apply(updates, requirements)
+}
+
+func (us *UpdateSchema) findField(name string) (iceberg.NestedField, bool) {
+	if us.caseSensitive {
+		return us.schema.FindFieldByName(name)
+	}
+
+	return us.schema.FindFieldByNameCaseInsensitive(name)
+}
+
+func (us *UpdateSchema) findForUpdate(name string) (iceberg.NestedField, error) {
+	field, ok := us.findField(name)
+	if !ok {
+		return iceberg.NestedField{}, fmt.Errorf("%w: cannot find column %s", iceberg.ErrInvalidSchema, name)
+	}
+	if us.deletes[field.ID] {
+		return iceberg.NestedField{}, fmt.Errorf("%w: cannot update a column that will be deleted: %s",
+			iceberg.ErrInvalidSchema, name)
+	}
+
+	if updated, ok := us.updates[field.ID]; ok {
+		return updated, nil
+	}
+
+	return field, nil
+}
+
+func (us *UpdateSchema) addColumn(path []string, fieldType iceberg.Type, doc string, required bool) updateSchemaOp {
+	return func() error {
+		if len(path) == 0 {
+			return fmt.Errorf("%w: cannot add column with empty path", iceberg.ErrInvalidArgument)
+		}
+
+		name := path[len(path)-1]
+		if name == "" {
+			return fmt.Errorf("%w: cannot add column with empty name", iceberg.ErrInvalidArgument)
+		}
+		if required && !us.allowIncompatibleChanges {
+			return fmt.Errorf("%w: incompatible change: cannot add required column: %s",
+				iceberg.ErrInvalidSchema, strings.Join(path, "."))
+		}
+
+		parentID := tableRootID
+		if len(path) > 1 {
+			parentName := strings.Join(path[:len(path)-1], ".")
+			parent, ok := us.findField(parentName)
+			if !ok {
+				return fmt.Errorf("%w: cannot find parent struct: %s", iceberg.ErrInvalidSchema, parentName)
+			}
+
+			parentType := parent.Type
+			switch t := parentType.(type) {
+			case *iceberg.ListType:
+				parent = t.ElementField()
+			case *iceberg.MapType:
+				parent = t.ValueField()
+			}
+
+			if _, ok := parent.Type.(*iceberg.StructType); !ok {
+				return fmt.Errorf("%w: cannot add column to non-struct type: %s", iceberg.ErrInvalidSchema, parentName)
+			}
+			if us.deletes[parent.ID] {
+				return fmt.Errorf("%w: cannot add to a column that will be deleted: %s",
+					iceberg.ErrInvalidSchema, parentName)
+			}
+			parentID = parent.ID
+		}
+
+		fullName := strings.Join(path, ".")
+		if existing, ok := us.findField(fullName); ok && !us.deletes[existing.ID] {
+			return fmt.Errorf("%w: cannot add column, name already exists: %s", iceberg.ErrInvalidSchema, fullName)
+		}
+		if _, ok := us.addedNameToID[fullName]; ok {
+			return fmt.Errorf("%w: cannot add column, name already added: %s", iceberg.ErrInvalidSchema, fullName)
+		}
+
+		// assign fresh IDs to the new field and any nested fields, the outer
+		// field is visited first so it receives the first new ID.
+		tmp, err := iceberg.AssignFreshSchemaIDs(iceberg.NewSchema(0, iceberg.NestedField{
+			Name: name, Type: fieldType, Required: required, Doc: doc,
+		}), us.assignNewColumnID)
+		if err != nil {
+			return err
+		}
+
+		newField := tmp.Field(0)
+		us.addedNameToID[fullName] = newField.ID
+		us.idToParent[newField.ID] = parentID
+		us.adds[parentID] = append(us.adds[parentID], newField)
+
+		return nil
+	}
+}
+
+func (us *UpdateSchema) deleteColumn(name string) updateSchemaOp {
+	return func() error {
+		field, ok := us.findField(name)
+		if !ok {
+			return fmt.Errorf("%w: cannot delete missing column: %s", iceberg.ErrInvalidSchema, name)
+		}
+		if _, ok := us.adds[field.ID]; ok {
+			return fmt.Errorf("%w: cannot delete a column that has additions: %s", iceberg.ErrInvalidSchema, name)
+		}
+		if _, ok := us.updates[field.ID]; ok {
+			return fmt.Errorf("%w: cannot delete a column that has updates: %s", iceberg.ErrInvalidSchema, name)
+		}
+
+		us.deletes[field.ID] = true
+
+		return nil
+	}
+}
+
+func (us *UpdateSchema) renameColumn(name string, newName string) updateSchemaOp {
+	return func() error {
+		if newName == "" {
+			return fmt.Errorf("%w: cannot rename column to empty name", iceberg.ErrInvalidArgument)
+		}
+
+		field, err := us.findForUpdate(name)
+		if err != nil {
+			return err
+		}
+
+		oldName := field.Name
+		field.Name = newName
+		us.updates[field.ID] = field
+
+		// keep the identifier fields pointing at the renamed column
+		fullName, _ := us.schema.FindColumnName(field.ID)
+		for i, idName := range us.identifierFieldNames {
+			if idName == fullName {
+				us.identifierFieldNames[i] = fullName[:len(fullName)-len(oldName)] + newName
+			}
+		}
+
+		return nil
+	}
+}
+
+func (us *UpdateSchema) updateColumn(name string, newType iceberg.Type) updateSchemaOp {
+	return func() error {
+		field, err := us.findForUpdate(name)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := field.Type.(iceberg.PrimitiveType); !ok {
+			return fmt.Errorf("%w: cannot update type of non-primitive column: %s", iceberg.ErrInvalidSchema, name)
+		}
+
+		if field.Type.Equals(newType) {
+			return nil
+		}
+
+		if !isPromotionAllowed(field.Type, newType) {
+			return fmt.Errorf("%w: cannot change column type: %s: %s -> %s",
+				iceberg.ErrInvalidSchema, name, field.Type, newType)
+		}
+
+		field.Type = newType
+		us.updates[field.ID] = field
+
+		return nil
+	}
+}
+
+func (us *UpdateSchema) updateColumnDoc(name string, doc string) updateSchemaOp {
+	return func() error {
+		field, err := us.findForUpdate(name)
+		if err != nil {
+			return err
+		}
+
+		if field.Doc == doc {
+			return nil
+		}
+
+		field.Doc = doc
+		us.updates[field.ID] = field
+
+		return nil
+	}
+}
+
+func (us *UpdateSchema) makeOptional(name string) updateSchemaOp {
+	return func() error {
+		field, err := us.findForUpdate(name)
+		if err != nil {
+			return err
+		}
+
+		if !field.Required {
+			return nil
+		}
+
+		field.Required = false
+		us.updates[field.ID] = field
+
+		return nil
+	}
+}
+
+func (us *UpdateSchema) move(name, relativeName string, op moveOp) updateSchemaOp {
+	return func() error {
+		fieldID, err := us.findIDForMove(name)
+		if err != nil {
+			return err
+		}
+
+		parentID, ok := us.idToParent[fieldID]
+		if !ok {
+			parentID = tableRootID
+		}
+
+		mv := schemaMove{fieldID: fieldID, op: op}
+		if op != moveFirst {
+			relativeID, err := us.findIDForMove(relativeName)
+			if err != nil {
+				return err
+			}
+			if relativeID == fieldID {
+				return fmt.Errorf("%w: cannot move %s %s itself", iceberg.ErrInvalidSchema, name, op)
+			}
+
+			relativeParent, ok := us.idToParent[relativeID]
+			if !ok {
+				relativeParent = tableRootID
+			}
+			if relativeParent != parentID {
+				return fmt.Errorf("%w: cannot move field %s to a different struct", iceberg.ErrInvalidSchema, name)
+			}
+			mv.relativeTo = relativeID
+		}
+
+		us.moves[parentID] = append(us.moves[parentID], mv)
+
+		return nil
+	}
+}
+
+func (us *UpdateSchema) findIDForMove(name string) (int, error) {
+	if id, ok := us.addedNameToID[name]; ok {
+		return id, nil
+	}
+
+	field, ok := us.findField(name)
+	if !ok {
+		return 0, fmt.Errorf("%w: cannot move missing column: %s", iceberg.ErrInvalidSchema, name)
+	}
+
+	return field.ID, nil
+}
+
+func (us *UpdateSchema) assignNewColumnID() int {
+	us.lastColumnID++
+
+	return us.lastColumnID
+}
+
+// applyStruct rebuilds the given fields of the struct with the given parent ID,
+// dropping deleted fields, applying updates, appending added fields and then
+// performing any moves.
+func (us *UpdateSchema) applyStruct(fields []iceberg.NestedField, parentID int) ([]iceberg.NestedField, error) {
+	result := make([]iceberg.NestedField, 0, len(fields)+len(us.adds[parentID]))
+	for _, f := range fields {
+		if us.deletes[f.ID] {
+			continue
+		}
+
+		if updated, ok := us.updates[f.ID]; ok {
+			f.Name, f.Type, f.Required, f.Doc = updated.Name, updated.Type, updated.Required, updated.Doc
+		}
+
+		typ, err := us.applyType(f.Type, f.ID)
+		if err != nil {
+			return nil, err
+		}
+		f.Type = typ
+		result = append(result, f)
+	}
+
+	result = append(result, us.adds[parentID]...)
+
+	return applyMoves(result, us.moves[parentID])
+}
+
+func (us *UpdateSchema) applyType(typ iceberg.Type, fieldID int) (iceberg.Type, error) {
+	switch t := typ.(type) {
+	case *iceberg.StructType:
+		fields, err := us.applyStruct(t.FieldList, fieldID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &iceberg.StructType{FieldList: fields}, nil
+	case *iceberg.ListType:
+		elem, err := us.applyNested(t.ElementField())
+		if err != nil {
+			return nil, err
+		}
+
+		return &iceberg.ListType{
+			ElementID: elem.ID, Element: elem.Type, ElementRequired: elem.Required,
+		}, nil
+	case *iceberg.MapType:
+		key, err := us.applyNested(t.KeyField())
+		if err != nil {
+			return nil, err
+		}
+		if !key.Type.Equals(t.KeyType) {
+			return nil, fmt.Errorf("%w: cannot alter map keys", iceberg.ErrInvalidSchema)
+		}
+
+		value, err := us.applyNested(t.ValueField())
+		if err != nil {
+			return nil, err
+		}
+
+		return &iceberg.MapType{
+			KeyID: key.ID, KeyType: key.Type,
+			ValueID: value.ID, ValueType: value.Type, ValueRequired: value.Required,
+		}, nil
+	default:
+		return typ, nil
+	}
+}
+
+// applyNested applies the updates to the element of a list or the key and
+// value of a map, which cannot be deleted or renamed.
+func (us *UpdateSchema) applyNested(field iceberg.NestedField) (iceberg.NestedField, error) {
+	if us.deletes[field.ID] {
+		return field, fmt.Errorf("%w: cannot delete list elements, map keys or values: %d",
+			iceberg.ErrInvalidSchema, field.ID)
+	}
+
+	if updated, ok := us.updates[field.ID]; ok {
+		if updated.Name != field.Name {
+			return field, fmt.Errorf("%w: cannot rename list elements, map keys or values: %s",
+				iceberg.ErrInvalidSchema, field.Name)
+		}
+		field.Type, field.Required = updated.Type, updated.Required
+	}
+
+	typ, err := us.applyType(field.Type, field.ID)
+	if err != nil {
+		return field, err
+	}
+	field.Type = typ
+
+	return field, nil
+}
+
+func applyMoves(fields []iceberg.NestedField, moves []schemaMove) ([]iceberg.NestedField, error) {
+	for _, mv := range moves {
+		idx := slices.IndexFunc(fields, func(f iceberg.NestedField) bool { return f.ID == mv.fieldID })
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: cannot move deleted field %d", iceberg.ErrInvalidSchema, mv.fieldID)
+		}
+
+		toMove := fields[idx]
+		fields = slices.Delete(fields, idx, idx+1)
+
+		switch mv.op {
+		case moveFirst:
+			fields = slices.Insert(fields, 0, toMove)
+		case moveBefore, moveAfter:
+			rel := slices.IndexFunc(fields, func(f iceberg.NestedField) bool { return f.ID == mv.relativeTo })
+			if rel < 0 {
+				return nil, fmt.Errorf("%w: cannot move relative to deleted field %d",
+					iceberg.ErrInvalidSchema, mv.relativeTo)
+			}
+			if mv.op == moveAfter {
+				rel++
+			}
+			fields = slices.Insert(fields, rel, toMove)
+		}
+	}
+
+	return fields, nil
+}
+
+// isPromotionAllowed reports whether a column of type from can be changed
+// to type to according to the Iceberg schema evolution rules.
+func isPromotionAllowed(from, to iceberg.Type) bool {
+	switch f := from.(type) {
+	case iceberg.Int32Type:
+		_, ok := to.(iceberg.Int64Type)
+
+		return ok
+	case iceberg.Float32Type:
+		_, ok := to.(iceberg.Float64Type)
+
+		return ok
+	case iceberg.DecimalType:
+		t, ok := to.(iceberg.DecimalType)
+
+		return ok && f.Scale() == t.Scale() && f.Precision() <= t.Precision()
+	}
+
+	return false
+}
+
+func validateIdentifierField(sc *iceberg.Schema, field iceberg.NestedField) error {
+	if _, ok := field.Type.(iceberg.PrimitiveType); !ok {
+		return fmt.Errorf("%w: identifier field %d invalid: not a primitive type field: %s",
+			iceberg.ErrInvalidSchema, field.ID, field.Type)
+	}
+	if !field.Required {
+		return fmt.Errorf("%w: identifier field %d invalid: not a required field",
+			iceberg.ErrInvalidSchema, field.ID)
+	}
+	switch field.Type.(type) {
+	case iceberg.Float32Type, iceberg.Float64Type:
+		return fmt.Errorf("%w: identifier field %d invalid: must not be float or double field",
+			iceberg.ErrInvalidSchema, field.ID)
+	}
+
+	idToParent, err := iceberg.IndexParents(sc)
+	if err != nil {
+		return err
+	}
+
+	for parentID, ok := idToParent[field.ID]; ok; parentID, ok = idToParent[parentID] {
+		parent, _ := sc.FindFieldByID(parentID)
+		if _, isStruct := parent.Type.(*iceberg.StructType); !isStruct {
+			return fmt.Errorf("%w: identifier field %d cannot be nested in a list or map",
+				iceberg.ErrInvalidSchema, field.ID)
+		}
+		if !parent.Required {
+			return fmt.Errorf("%w: identifier field %d cannot be nested in an optional struct",
+				iceberg.ErrInvalidSchema, field.ID)
+		}
+	}
+
+	return nil
+}
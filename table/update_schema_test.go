@@ -0,0 +1,286 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package table_test
+
+import (
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fieldNames(fields []iceberg.NestedField) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+
+	return names
+}
+
+func TestUpdateSchemaAddColumn(t *testing.T) {
+	t.Run("add top level and nested columns", func(t *testing.T) {
+		txn := testNonPartitionedTable.NewTransaction()
+		newSchema, err := txn.UpdateSchema(true).
+			AddColumn([]string{"count"}, iceberg.PrimitiveTypes.Int32, "number of things", false).
+			AddColumn([]string{"address", "country"}, iceberg.PrimitiveTypes.String, "", false).
+			AddColumn([]string{"location"}, &iceberg.StructType{FieldList: []iceberg.NestedField{
+				{ID: 1, Name: "lat", Type: iceberg.PrimitiveTypes.Float64},
+				{ID: 2, Name: "long", Type: iceberg.PrimitiveTypes.Float64},
+			}}, "", false).
+			Apply()
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, newSchema.ID)
+		assert.Equal(t, 12, newSchema.HighestFieldID())
+		assert.Equal(t, []string{"id", "name", "ts", "address", "count", "location"}, fieldNames(newSchema.Fields()))
+
+		count, ok := newSchema.FindFieldByName("count")
+		require.True(t, ok)
+		assert.Equal(t, 8, count.ID)
+		assert.Equal(t, "number of things", count.Doc)
+		assert.False(t, count.Required)
+
+		country, ok := newSchema.FindFieldByName("address.country")
+		require.True(t, ok)
+		assert.Equal(t, 9, country.ID)
+
+		lat, ok := newSchema.FindFieldByName("location.lat")
+		require.True(t, ok)
+		assert.Equal(t, 11, lat.ID)
+	})
+
+	t.Run("add column to list element struct", func(t *testing.T) {
+		sc := iceberg.NewSchema(0,
+			iceberg.NestedField{ID: 1, Name: "points", Type: &iceberg.ListType{
+				ElementID: 2, ElementRequired: true, Element: &iceberg.StructType{
+					FieldList: []iceberg.NestedField{{ID: 3, Name: "x", Type: iceberg.PrimitiveTypes.Int32}},
+				},
+			}})
+		meta, err := table.NewMetadata(sc, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, "", nil)
+		require.NoError(t, err)
+		tbl := table.New([]string{"list_table"}, meta, "", nil, nil)
+
+		newSchema, err := tbl.NewTransaction().UpdateSchema(true).
+			AddColumn([]string{"points", "y"}, iceberg.PrimitiveTypes.Int32, "", false).
+			Apply()
+		require.NoError(t, err)
+
+		y, ok := newSchema.FindFieldByName("points.element.y")
+		require.True(t, ok)
+		assert.Equal(t, 4, y.ID)
+	})
+
+	t.Run("add required column", func(t *testing.T) {
+		txn := testNonPartitionedTable.NewTransaction()
+		_, err := txn.UpdateSchema(true).
+			AddColumn([]string{"req"}, iceberg.PrimitiveTypes.Int32, "", true).
+			Apply()
+		assert.ErrorIs(t, err, iceberg.ErrInvalidSchema)
+		assert.ErrorContains(t, err, "incompatible change: cannot add required column: req")
+
+		newSchema, err := txn.UpdateSchema(true).
+			AllowIncompatibleChanges().
+			AddColumn([]string{"req"}, iceberg.PrimitiveTypes.Int32, "", true).
+			Apply()
+		require.NoError(t, err)
+		req, _ := newSchema.FindFieldByName("req")
+		assert.True(t, req.Required)
+	})
+
+	t.Run("add existing column", func(t *testing.T) {
+		_, err := testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+			AddColumn([]string{"address", "city"}, iceberg.PrimitiveTypes.String, "", false).
+			Apply()
+		assert.ErrorContains(t, err, "cannot add column, name already exists: address.city")
+	})
+
+	t.Run("add to non-struct", func(t *testing.T) {
+		_, err := testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+			AddColumn([]string{"name", "first"}, iceberg.PrimitiveTypes.String, "", false).
+			Apply()
+		assert.ErrorContains(t, err, "cannot add column to non-struct type: name")
+	})
+}
+
+func TestUpdateSchemaDeleteAndRename(t *testing.T) {
+	newSchema, err := testNonPartitionedTable.NewTransaction().UpdateSchema(false).
+		DeleteColumn("ts").
+		RenameColumn("ADDRESS.zip_code", "postal_code").
+		RenameColumn("name", "full_name").
+		Apply()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"id", "full_name", "address"}, fieldNames(newSchema.Fields()))
+	postal, ok := newSchema.FindFieldByName("address.postal_code")
+	require.True(t, ok)
+	assert.Equal(t, 7, postal.ID)
+
+	_, err = testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+		DeleteColumn("ts").
+		RenameColumn("ts", "timestamp").
+		Apply()
+	assert.ErrorContains(t, err, "cannot update a column that will be deleted: ts")
+
+	_, err = testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+		DeleteColumn("missing").
+		Apply()
+	assert.ErrorContains(t, err, "cannot delete missing column: missing")
+}
+
+func TestUpdateSchemaUpdateColumn(t *testing.T) {
+	sc := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "i", Type: iceberg.PrimitiveTypes.Int32, Required: true},
+		iceberg.NestedField{ID: 2, Name: "f", Type: iceberg.PrimitiveTypes.Float32},
+		iceberg.NestedField{ID: 3, Name: "d", Type: iceberg.DecimalTypeOf(9, 2)},
+		iceberg.NestedField{ID: 4, Name: "s", Type: iceberg.PrimitiveTypes.String},
+	)
+	meta, err := table.NewMetadata(sc, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, "", nil)
+	require.NoError(t, err)
+	tbl := table.New([]string{"promote"}, meta, "", nil, nil)
+
+	newSchema, err := tbl.NewTransaction().UpdateSchema(true).
+		UpdateColumn("i", iceberg.PrimitiveTypes.Int64).
+		UpdateColumn("f", iceberg.PrimitiveTypes.Float64).
+		UpdateColumn("d", iceberg.DecimalTypeOf(18, 2)).
+		MakeOptional("i").
+		UpdateColumnDoc("s", "some text").
+		Apply()
+	require.NoError(t, err)
+
+	assert.True(t, newSchema.Equals(iceberg.NewSchema(1,
+		iceberg.NestedField{ID: 1, Name: "i", Type: iceberg.PrimitiveTypes.Int64},
+		iceberg.NestedField{ID: 2, Name: "f", Type: iceberg.PrimitiveTypes.Float64},
+		iceberg.NestedField{ID: 3, Name: "d", Type: iceberg.DecimalTypeOf(18, 2)},
+		iceberg.NestedField{ID: 4, Name: "s", Type: iceberg.PrimitiveTypes.String, Doc: "some text"},
+	)), newSchema.String())
+
+	tests := []struct {
+		name string
+		typ  iceberg.Type
+	}{
+		{"i", iceberg.PrimitiveTypes.Int32},
+		{"d", iceberg.DecimalTypeOf(18, 3)},
+		{"d", iceberg.DecimalTypeOf(8, 2)},
+		{"s", iceberg.PrimitiveTypes.Binary},
+		{"f", iceberg.PrimitiveTypes.Int64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" to "+tt.typ.String(), func(t *testing.T) {
+			if tt.name == "i" {
+				// long -> int is a narrowing of the promoted column
+				_, err := tbl.NewTransaction().UpdateSchema(true).
+					UpdateColumn("i", iceberg.PrimitiveTypes.Int64).
+					UpdateColumn("i", tt.typ).
+					Apply()
+				assert.ErrorIs(t, err, iceberg.ErrInvalidSchema)
+
+				return
+			}
+
+			_, err := tbl.NewTransaction().UpdateSchema(true).
+				UpdateColumn(tt.name, tt.typ).
+				Apply()
+			assert.ErrorIs(t, err, iceberg.ErrInvalidSchema)
+			assert.ErrorContains(t, err, "cannot change column type")
+		})
+	}
+}
+
+func TestUpdateSchemaMoves(t *testing.T) {
+	newSchema, err := testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+		AddColumn([]string{"count"}, iceberg.PrimitiveTypes.Int32, "", false).
+		MoveFirst("count").
+		MoveAfter("id", "ts").
+		MoveBefore("address.zip_code", "address.street").
+		Apply()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"count", "name", "ts", "id", "address"}, fieldNames(newSchema.Fields()))
+	address, _ := newSchema.FindFieldByName("address")
+	assert.Equal(t, []string{"zip_code", "street", "city"},
+		fieldNames(address.Type.(*iceberg.StructType).FieldList))
+
+	_, err = testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+		MoveAfter("id", "address.city").
+		Apply()
+	assert.ErrorContains(t, err, "cannot move field id to a different struct")
+
+	_, err = testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+		MoveBefore("id", "id").
+		Apply()
+	assert.ErrorContains(t, err, "cannot move id before itself")
+}
+
+func TestUpdateSchemaIdentifierFields(t *testing.T) {
+	newSchema, err := testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+		SetIdentifierFields("id", "name").
+		Apply()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, newSchema.IdentifierFieldIDs)
+
+	_, err = testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+		SetIdentifierFields("ts").
+		Apply()
+	assert.ErrorContains(t, err, "identifier field 3 invalid: not a required field")
+
+	_, err = testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+		SetIdentifierFields("address.street").
+		Apply()
+	assert.ErrorContains(t, err, "identifier field 5 cannot be nested in an optional struct")
+
+	_, err = testNonPartitionedTable.NewTransaction().UpdateSchema(true).
+		SetIdentifierFields("missing").
+		Apply()
+	assert.ErrorContains(t, err, "cannot find identifier field missing")
+}
+
+func TestUpdateSchemaCommit(t *testing.T) {
+	txn := testNonPartitionedTable.NewTransaction()
+	err := txn.UpdateSchema(true).
+		AddColumn([]string{"count"}, iceberg.PrimitiveTypes.Int32, "", false).
+		Commit()
+	require.NoError(t, err)
+
+	tbl, err := txn.StagedTable()
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, tbl.Schema().ID)
+	assert.Len(t, tbl.Metadata().Schemas(), 2)
+	assert.Equal(t, 8, tbl.Metadata().LastColumnID())
+	_, ok := tbl.Schema().FindFieldByName("count")
+	assert.True(t, ok)
+
+	// deleting the column again goes back to the original schema
+	err = txn.UpdateSchema(true).DeleteColumn("count").Commit()
+	require.NoError(t, err)
+
+	tbl, err = txn.StagedTable()
+	require.NoError(t, err)
+	assert.Equal(t, 0, tbl.Schema().ID)
+	assert.Len(t, tbl.Metadata().Schemas(), 2)
+
+	// no changes produces no updates
+	updates, reqs, err := txn.UpdateSchema(true).BuildUpdates()
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+	assert.Len(t, reqs, 1)
+}
@@ -351,6 +351,28 @@ func NewSetSnapshotRefUpdate(
 	}
 }
 
+// newSetSnapshotRefUpdateFrom creates an update setting the ref to the
+// snapshot, with the type and retention settings of the given ref.
+func newSetSnapshotRefUpdateFrom(name string, snapshotID int64, ref SnapshotRef) *setSnapshotRefUpdate {
+	var (
+		maxRefAgeMs, maxSnapshotAgeMs int64
+		minSnapshotsToKeep            int
+	)
+
+	if ref.MaxRefAgeMs != nil {
+		maxRefAgeMs = *ref.MaxRefAgeMs
+	}
+	if ref.MaxSnapshotAgeMs != nil {
+		maxSnapshotAgeMs = *ref.MaxSnapshotAgeMs
+	}
+	if ref.MinSnapshotsToKeep != nil {
+		minSnapshotsToKeep = *ref.MinSnapshotsToKeep
+	}
+
+	return NewSetSnapshotRefUpdate(name, snapshotID, ref.SnapshotRefType,
+		maxRefAgeMs, maxSnapshotAgeMs, minSnapshotsToKeep)
+}
+
 func (u *setSnapshotRefUpdate) Apply(builder *MetadataBuilder) error {
 	opts := []setSnapshotRefOption{}
 	if u.MaxRefAgeMs > 0 {
@@ -448,7 +470,9 @@ func NewRemoveSnapshotsUpdate(ids []int64) *removeSnapshotsUpdate {
 }
 
 func (u *removeSnapshotsUpdate) Apply(builder *MetadataBuilder) error {
-	return fmt.Errorf("%w: %s", iceberg.ErrNotImplemented, UpdateRemoveSnapshots)
+	_, err := builder.RemoveSnapshots(u.SnapshotIDs)
+
+	return err
 }
 
 type removeSnapshotRefUpdate struct {
@@ -466,7 +490,9 @@ func NewRemoveSnapshotRefUpdate(ref string) *removeSnapshotRefUpdate {
 }
 
 func (u *removeSnapshotRefUpdate) Apply(builder *MetadataBuilder) error {
-	return fmt.Errorf("%w: %s", iceberg.ErrNotImplemented, UpdateRemoveSnapshotRef)
+	_, err := builder.RemoveSnapshotRef(u.RefName)
+
+	return err
 }
 
 type removeSpecUpdate struct {
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"path"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/iceberg-go"
@@ -31,11 +32,21 @@ import (
 )
 
 type WriteTask struct {
-	Uuid        uuid.UUID
-	ID          int
-	Schema      *iceberg.Schema
-	Batches     []arrow.Record
+	Uuid    uuid.UUID
+	ID      int
+	Schema  *iceberg.Schema
+	Batches []arrow.Record
+	// SortOrderID is the id of the sort order the records are sorted by
+	// before being written, the records are written as they are for the
+	// unsorted order.
 	SortOrderID int
+	// PartitionValues maps the partition field ids of the current spec to
+	// the partition values shared by every record in the task. It is nil
+	// for unpartitioned tables.
+	PartitionValues map[int]any
+	// PartitionPath is the human readable path for the partition, such
+	// as `name1=value1/name2=value2`, empty for unpartitioned tables.
+	PartitionPath string
 }
 
 func (w WriteTask) GenerateDataFileName(extension string) string {
@@ -70,19 +81,52 @@ func (w *writer) writeFile(ctx context.Context, task WriteTask) (iceberg.DataFil
 		batches[i] = rec
 	}
 
+	var sortOrderID *int
+	if task.SortOrderID != UnsortedSortOrderID {
+		order, err := w.meta.GetSortOrderByID(task.SortOrderID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !order.IsUnsorted() {
+			sorted, err := sortRecords(ctx, w.fileSchema, *order, batches)
+			for _, b := range batches {
+				b.Release()
+			}
+			if err != nil {
+				return nil, err
+			}
+			defer func() {
+				for _, rec := range sorted {
+					rec.Release()
+				}
+			}()
+
+			batches = sorted
+		}
+		sortOrderID = &order.OrderID
+	}
+
 	statsCols, err := computeStatsPlan(w.fileSchema, w.meta.props)
 	if err != nil {
 		return nil, err
 	}
 
-	filePath := w.loc.NewDataLocation(
-		task.GenerateDataFileName("parquet"))
+	fileName := task.GenerateDataFileName("parquet")
+	if task.PartitionPath != "" {
+		fileName = path.Join(task.PartitionPath, fileName)
+	}
+
+	filePath := w.loc.NewDataLocation(fileName)
 
 	return w.format.WriteDataFile(ctx, w.fs, internal.WriteFileInfo{
-		FileSchema: w.fileSchema,
-		FileName:   filePath,
-		StatsCols:  statsCols,
-		WriteProps: w.props,
+		FileSchema:      w.fileSchema,
+		Spec:            w.meta.CurrentSpec(),
+		FileName:        filePath,
+		StatsCols:       statsCols,
+		WriteProps:      w.props,
+		PartitionValues: task.PartitionValues,
+		SortOrderID:     sortOrderID,
 	}, batches)
 }
 
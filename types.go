@@ -121,12 +121,20 @@ func (t *typeIFace) UnmarshalJSON(b []byte) error {
 			t.Type = TimestampType{}
 		case "timestamptz":
 			t.Type = TimestampTzType{}
+		case "timestamp_ns":
+			t.Type = TimestampNsType{}
+		case "timestamptz_ns":
+			t.Type = TimestampTzNsType{}
 		case "string":
 			t.Type = StringType{}
 		case "uuid":
 			t.Type = UUIDType{}
 		case "binary":
 			t.Type = BinaryType{}
+		case "unknown":
+			t.Type = UnknownType{}
+		case "variant":
+			t.Type = VariantType{}
 		default:
 			switch {
 			case strings.HasPrefix(typename, "fixed"):
@@ -641,6 +649,34 @@ func (TimestampTzType) primitive()     {}
 func (TimestampTzType) Type() string   { return "timestamptz" }
 func (TimestampTzType) String() string { return "timestamptz" }
 
+// TimestampNsType represents a number of nanoseconds since the unix epoch
+// without regard for timezone. Requires format version 3.
+type TimestampNsType struct{}
+
+func (TimestampNsType) Equals(other Type) bool {
+	_, ok := other.(TimestampNsType)
+
+	return ok
+}
+
+func (TimestampNsType) primitive()     {}
+func (TimestampNsType) Type() string   { return "timestamp_ns" }
+func (TimestampNsType) String() string { return "timestamp_ns" }
+
+// TimestampTzNsType represents a timestamp stored as UTC representing the
+// number of nanoseconds since the unix epoch. Requires format version 3.
+type TimestampTzNsType struct{}
+
+func (TimestampTzNsType) Equals(other Type) bool {
+	_, ok := other.(TimestampTzNsType)
+
+	return ok
+}
+
+func (TimestampTzNsType) primitive()     {}
+func (TimestampTzNsType) Type() string   { return "timestamptz_ns" }
+func (TimestampTzNsType) String() string { return "timestamptz_ns" }
+
 type StringType struct{}
 
 func (StringType) Equals(other Type) bool {
@@ -677,6 +713,37 @@ func (BinaryType) primitive()     {}
 func (BinaryType) Type() string   { return "binary" }
 func (BinaryType) String() string { return "binary" }
 
+// UnknownType is the type of a column whose values are all null and
+// whose type has not been determined yet. Fields of this type must be
+// optional and it can be promoted to any other type. Requires format
+// version 3.
+type UnknownType struct{}
+
+func (UnknownType) Equals(other Type) bool {
+	_, ok := other.(UnknownType)
+
+	return ok
+}
+
+func (UnknownType) primitive()     {}
+func (UnknownType) Type() string   { return "unknown" }
+func (UnknownType) String() string { return "unknown" }
+
+// VariantType represents semi-structured data encoded using the variant
+// binary encoding, a metadata buffer and a value buffer. Requires format
+// version 3.
+type VariantType struct{}
+
+func (VariantType) Equals(other Type) bool {
+	_, ok := other.(VariantType)
+
+	return ok
+}
+
+func (VariantType) primitive()     {}
+func (VariantType) Type() string   { return "variant" }
+func (VariantType) String() string { return "variant" }
+
 var PrimitiveTypes = struct {
 	Bool        PrimitiveType
 	Int32       PrimitiveType
@@ -690,6 +757,11 @@ var PrimitiveTypes = struct {
 	String      PrimitiveType
 	Binary      PrimitiveType
 	UUID        PrimitiveType
+
+	TimestampNs   PrimitiveType
+	TimestampTzNs PrimitiveType
+	Unknown       PrimitiveType
+	Variant       PrimitiveType
 }{
 	Bool:        BooleanType{},
 	Int32:       Int32Type{},
@@ -703,6 +775,11 @@ var PrimitiveTypes = struct {
 	String:      StringType{},
 	Binary:      BinaryType{},
 	UUID:        UUIDType{},
+
+	TimestampNs:   TimestampNsType{},
+	TimestampTzNs: TimestampTzNsType{},
+	Unknown:       UnknownType{},
+	Variant:       VariantType{},
 }
 
 // PromoteType promotes the type being read from a file to a requested read type.
@@ -710,6 +787,8 @@ var PrimitiveTypes = struct {
 // readType is the requested readType
 func PromoteType(fileType, readType Type) (Type, error) {
 	switch t := fileType.(type) {
+	case UnknownType:
+		return readType, nil
 	case Int32Type:
 		if _, ok := readType.(Int64Type); ok {
 			return readType, nil
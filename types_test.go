@@ -40,8 +40,12 @@ func TestTypesBasic(t *testing.T) {
 		{"time", iceberg.PrimitiveTypes.Time},
 		{"timestamp", iceberg.PrimitiveTypes.Timestamp},
 		{"timestamptz", iceberg.PrimitiveTypes.TimestampTz},
+		{"timestamp_ns", iceberg.PrimitiveTypes.TimestampNs},
+		{"timestamptz_ns", iceberg.PrimitiveTypes.TimestampTzNs},
 		{"uuid", iceberg.PrimitiveTypes.UUID},
 		{"binary", iceberg.PrimitiveTypes.Binary},
+		{"unknown", iceberg.PrimitiveTypes.Unknown},
+		{"variant", iceberg.PrimitiveTypes.Variant},
 		{"fixed[5]", iceberg.FixedTypeOf(5)},
 		{"decimal(9, 4)", iceberg.DecimalTypeOf(9, 4)},
 	}
@@ -183,6 +187,10 @@ var NonParameterizedTypes = []iceberg.Type{
 	iceberg.PrimitiveTypes.String,
 	iceberg.PrimitiveTypes.Binary,
 	iceberg.PrimitiveTypes.UUID,
+	iceberg.PrimitiveTypes.TimestampNs,
+	iceberg.PrimitiveTypes.TimestampTzNs,
+	iceberg.PrimitiveTypes.Unknown,
+	iceberg.PrimitiveTypes.Variant,
 }
 
 func TestNonParameterizedTypeEquality(t *testing.T) {
@@ -211,6 +219,8 @@ func TestTypeStrings(t *testing.T) {
 		{iceberg.PrimitiveTypes.Time, "time"},
 		{iceberg.PrimitiveTypes.Timestamp, "timestamp"},
 		{iceberg.PrimitiveTypes.TimestampTz, "timestamptz"},
+		{iceberg.PrimitiveTypes.TimestampNs, "timestamp_ns"},
+		{iceberg.PrimitiveTypes.TimestampTzNs, "timestamptz_ns"},
 		{iceberg.PrimitiveTypes.String, "string"},
 		{iceberg.PrimitiveTypes.UUID, "uuid"},
 		{iceberg.PrimitiveTypes.Binary, "binary"},